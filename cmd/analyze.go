@@ -2,10 +2,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/jasonKoogler/comma/internal/analyze"
 	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -14,16 +21,42 @@ var (
 		Use:     "analyze",
 		Aliases: []string{"a"},
 		Short:   "Analyze repository commit patterns",
-		RunE:    runAnalyze,
+		Long: `Analyze repository commit patterns.
+The window defaults to the last 30 days; use --days, or --since and --until
+with YYYY-MM-DD dates, to change it. Use --format to export the results as json, csv or markdown for dashboards
+and CI reports, and --output to write them to a file instead of stdout.
+Parsed history is cached per HEAD commit and window, so repeated runs are fast;
+use --no-cache to re-read it from git.
+
+--author limits the analysis to authors whose names contain the given text.
+With --interactive the report is followed by a prompt to change the authors
+and dates, re-running the analysis without restarting.`,
+		RunE: runAnalyze,
 	}
 
-	daysToAnalyze int
-	exportFormat  string
+	daysToAnalyze  int
+	analyzeSince   string
+	analyzeUntil   string
+	exportFormat   string
+	analyzeFormat  string
+	analyzeOutput  string
+	analyzeNoCache bool
+	analyzeAuthors []string
+	analyzeLoop    bool
 )
 
 func init() {
 	analyzeCmd.Flags().IntVar(&daysToAnalyze, "days", 30, "number of days to analyze")
+	analyzeCmd.Flags().StringVar(&analyzeSince, "since", "", "start of the analysis window (YYYY-MM-DD, overrides --days)")
+	analyzeCmd.Flags().StringVar(&analyzeUntil, "until", "", "end of the analysis window (YYYY-MM-DD, default now)")
+	analyzeCmd.Flags().StringVar(&analyzeFormat, "format", "text", "output format (text, json, csv, markdown)")
+	analyzeCmd.Flags().Bool("json", false, "print the results as JSON; same as --format json")
+	analyzeCmd.Flags().StringVarP(&analyzeOutput, "output", "o", "", "write results to a file instead of stdout")
+	analyzeCmd.Flags().BoolVar(&analyzeNoCache, "no-cache", false, "re-read commit history instead of using the cache")
+	analyzeCmd.Flags().StringSliceVar(&analyzeAuthors, "author", nil, "only analyze commits by authors whose names contain this (repeatable)")
+	analyzeCmd.Flags().BoolVarP(&analyzeLoop, "interactive", "i", false, "change the author and date filters after the report and re-run it")
 	analyzeCmd.Flags().StringVar(&exportFormat, "export", "", "export format (csv, json)")
+	analyzeCmd.Flags().MarkDeprecated("export", "use --format instead")
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) error {
@@ -31,7 +64,13 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration manager not initialized")
 	}
 
-	fmt.Println("Analyzing repository commit patterns...")
+	format := analyzeFormat
+	if cmd.Flags().Changed("export") && !cmd.Flags().Changed("format") {
+		format = exportFormat
+	}
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		format = "json"
+	}
 
 	// Get git repository
 	repo, err := git.NewRepository(".")
@@ -43,20 +82,217 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("days") {
 		appContext.ConfigManager.Set("analysis.days", daysToAnalyze)
 	}
-	if cmd.Flags().Changed("export") {
-		appContext.ConfigManager.Set("analysis.export_format", exportFormat)
+
+	if analyzeLoop && (format != "text" || analyzeOutput != "") {
+		return fmt.Errorf("--interactive shows the text report; it cannot be combined with --format or --output")
+	}
+	if analyzeLoop {
+		if err := requireTerminal("Filter"); err != nil {
+			return err
+		}
 	}
 
-	// Use the analyze service to analyze the repository
-	result, err := appContext.AnalyzeService.AnalyzeRepository(repo, daysToAnalyze)
+	if analyzeNoCache {
+		appContext.AnalyzeService().SetCacheDir("")
+	}
+
+	result, err := runAnalysis(repo, format == "text")
 	if err != nil {
-		return fmt.Errorf("failed to analyze repository: %w", err)
+		return err
+	}
+	if analyzeLoop {
+		return analyzeInteractively(repo, result)
 	}
 
 	if result.TotalCommits == 0 {
-		return fmt.Errorf("no commits found in the last %d days", daysToAnalyze)
+		return fmt.Errorf("no commits found in the analysis window (%s)%s", result.Period(), authorNote(result))
+	}
+
+	var out io.Writer = os.Stdout
+	if analyzeOutput != "" {
+		file, err := os.Create(analyzeOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if format == "text" {
+		printAnalysis(out, result)
+	} else if err := analyze.Export(out, result, format); err != nil {
+		return err
+	}
+
+	if analyzeOutput != "" && !quiet {
+		fmt.Printf("✓ Analysis written to %s\n", analyzeOutput)
+	}
+
+	return nil
+}
+
+// runAnalysis analyzes the window and authors set by the flags. The spinner
+// is left out of machine-readable output so stdout stays clean.
+func runAnalysis(repo *git.Repository, spinner bool) (*analyze.AnalysisResult, error) {
+	since, until, err := analysisWindow()
+	if err != nil {
+		return nil, err
+	}
+
+	progress := newProgress(spinner)
+	progress.Start("Analyzing repository commit patterns")
+	result, err := appContext.AnalyzeService().AnalyzeFiltered(repo, since, until, analyze.Filter{Authors: analyzeAuthors})
+	progress.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze repository: %w", err)
+	}
+	return result, nil
+}
+
+// analyzeInteractively prints the report, then lets the user change the
+// author and date filters and prints it again until they quit
+func analyzeInteractively(repo *git.Repository, result *analyze.AnalysisResult) error {
+	for {
+		if result.TotalCommits == 0 {
+			fmt.Printf("\n⚠️  No commits found in the analysis window (%s)%s\n", result.Period(), authorNote(result))
+		} else {
+			printAnalysis(os.Stdout, result)
+		}
+
+		choice, err := promptChoice("\nFilter: [a]uthors, [d]ates, [c]lear, [q]uit: ")
+		if err != nil {
+			return err
+		}
+
+		// Keep the old filters if the new ones are invalid
+		authors, since, until := analyzeAuthors, analyzeSince, analyzeUntil
+		switch choice {
+		case "a", "authors":
+			answer, err := promptLine("Authors, comma-separated (empty for everyone): ")
+			if err != nil {
+				return err
+			}
+			analyzeAuthors = splitList(answer)
+		case "d", "dates":
+			if analyzeSince, err = promptLine(fmt.Sprintf("Since (YYYY-MM-DD, empty for %d days before the end): ", daysToAnalyze)); err != nil {
+				return err
+			}
+			if analyzeUntil, err = promptLine("Until (YYYY-MM-DD, empty for today): "); err != nil {
+				return err
+			}
+		case "c", "clear":
+			analyzeAuthors, analyzeSince, analyzeUntil = nil, "", ""
+		case "q", "quit", "":
+			return nil
+		default:
+			fmt.Println("⚠️  Choose a, d, c or q.")
+			continue
+		}
+
+		next, err := runAnalysis(repo, true)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			analyzeAuthors, analyzeSince, analyzeUntil = authors, since, until
+			continue
+		}
+		result = next
+	}
+}
+
+// authorNote describes the author filter for messages, if there is one
+func authorNote(result *analyze.AnalysisResult) string {
+	if len(result.AuthorFilter) == 0 {
+		return ""
+	}
+	return " by authors matching " + strings.Join(result.AuthorFilter, ", ")
+}
+
+// splitList splits a comma-separated answer, dropping empty items
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// analysisWindow resolves the --days, --since and --until flags into a time range
+func analysisWindow() (time.Time, time.Time, error) {
+	until := time.Now()
+	if analyzeUntil != "" {
+		date, err := time.ParseInLocation("2006-01-02", analyzeUntil, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until date %q (use YYYY-MM-DD)", analyzeUntil)
+		}
+		// Include the whole final day
+		until = date.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	since := until.AddDate(0, 0, -daysToAnalyze)
+	if analyzeSince != "" {
+		date, err := time.ParseInLocation("2006-01-02", analyzeSince, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --since date %q (use YYYY-MM-DD)", analyzeSince)
+		}
+		since = date
+	}
+
+	return since, until, nil
+}
+
+// weekdayCounts sums the daily series by weekday, Monday first
+func weekdayCounts(result *analyze.AnalysisResult) []int {
+	counts := make([]int, 7)
+	for _, day := range result.DailyCommits {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		counts[(int(date.Weekday())+6)%7] += day.Count
 	}
+	return counts
+}
+
+// printHeatmaps writes the weekday by hour heatmap of the most active authors
+func printHeatmaps(w io.Writer, result *analyze.AnalysisResult, limit int) {
+	authors := make([]string, 0, len(result.AuthorStats))
+	for author := range result.AuthorStats {
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if result.AuthorStats[authors[i]] != result.AuthorStats[authors[j]] {
+			return result.AuthorStats[authors[i]] > result.AuthorStats[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
 
+	for i, author := range authors {
+		if i >= limit {
+			fmt.Fprintf(w, "\n  ...and %d more (use --format json or markdown for all authors)\n", len(authors)-limit)
+			break
+		}
+		if grid := result.Heatmap[author]; grid != nil {
+			fmt.Fprintf(w, "\nActivity by Hour - %s (%d commits):\n", author, result.AuthorStats[author])
+			fmt.Fprint(w, indent(ui.Heatmap(analyze.Weekdays, grid.Rows()), "  "))
+		}
+	}
+}
+
+// indent prefixes every non-empty line of text
+func indent(text, prefix string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// printAnalysis writes the human-readable analysis report
+func printAnalysis(w io.Writer, result *analyze.AnalysisResult) {
 	// Calculate statistics
 	conventionalPercent := result.ConventionalPercent
 
@@ -76,31 +312,84 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	})
 
 	// Print results
-	fmt.Println("\nRepository Statistics:")
-	fmt.Println("---------------------")
-	fmt.Printf("Total commits: %d\n", result.TotalCommits)
-	fmt.Printf("Time period: Last %d days\n", daysToAnalyze)
-	fmt.Printf("Contributors: %d\n", len(result.AuthorStats))
-	fmt.Printf("Conventional commits: %.1f%%\n", conventionalPercent)
-
-	fmt.Println("\nCommit Types:")
+	fmt.Fprintln(w, "\nRepository Statistics:")
+	fmt.Fprintln(w, "---------------------")
+	fmt.Fprintf(w, "Total commits: %d\n", result.TotalCommits)
+	fmt.Fprintf(w, "Time period: %s\n", result.Period())
+	if len(result.AuthorFilter) > 0 {
+		fmt.Fprintf(w, "Authors matching: %s\n", strings.Join(result.AuthorFilter, ", "))
+	}
+	fmt.Fprintf(w, "Contributors: %d\n", len(result.AuthorStats))
+	fmt.Fprintf(w, "Conventional commits: %.1f%%\n", conventionalPercent)
+
+	if len(result.DailyCommits) > 0 {
+		first := result.DailyCommits[0].Date
+		last := result.DailyCommits[len(result.DailyCommits)-1].Date
+		fmt.Fprintf(w, "\nActivity (%s to %s):\n", first, last)
+		fmt.Fprintf(w, "  %s\n", ui.Sparkline(result.Counts(), 60))
+	}
+
+	fmt.Fprintln(w, "\nCommits by Weekday:")
+	fmt.Fprint(w, indent(ui.BarChart(analyze.Weekdays, weekdayCounts(result), 30), "  "))
+
+	printHeatmaps(w, result, 3)
+
+	fmt.Fprintln(w, "\nCommit Types:")
 	for i, tc := range sortedTypes {
 		if i >= 5 {
 			break // Show top 5
 		}
 		percent := float64(tc.Count) / float64(result.TotalCommits) * 100
-		fmt.Printf("  %s: %d (%.1f%%)\n", tc.Type, tc.Count, percent)
+		fmt.Fprintf(w, "  %s: %d (%.1f%%)\n", tc.Type, tc.Count, percent)
+	}
+
+	if len(result.CommitSizes) > 0 {
+		dist := result.SizeDistribution()
+		fmt.Fprintln(w, "\nCommit Size (lines changed):")
+		fmt.Fprintf(w, "  median %d, p75 %d, p90 %d, p95 %d, max %d\n", dist.Median, dist.P75, dist.P90, dist.P95, dist.Max)
+		if len(dist.Outliers) > 0 {
+			fmt.Fprintln(w, "  Outliers:")
+			for _, outlier := range dist.Outliers {
+				fmt.Fprintf(w, "    %.8s +%d/-%d %s\n", outlier.Hash, outlier.Additions, outlier.Deletions, outlier.Subject)
+			}
+		}
+	}
+
+	if len(result.ScopeStats) > 0 {
+		fmt.Fprintln(w, "\nTop Scopes:")
+		for _, scope := range result.TopScopes(5) {
+			fmt.Fprintf(w, "  %s: %d\n", scope.Scope, scope.Count)
+		}
+
+		if drift := result.ScopeDrift(); len(drift) > 0 {
+			fmt.Fprintln(w, "\nScopes used only once (possible typos):")
+			for _, d := range drift {
+				if d.Suggestion != "" {
+					fmt.Fprintf(w, "  %s (did you mean %s?)\n", d.Scope, d.Suggestion)
+				} else {
+					fmt.Fprintf(w, "  %s\n", d.Scope)
+				}
+			}
+		}
 	}
 
 	// Print suggestions
-	fmt.Println("\nSuggestions:")
+	fmt.Fprintln(w, "\nSuggestions:")
 	if conventionalPercent < 80 {
-		fmt.Println("- Consider adopting conventional commits format more consistently")
+		fmt.Fprintln(w, "- Consider adopting conventional commits format more consistently")
 	}
 
 	if len(result.AuthorStats) == 1 {
-		fmt.Println("- Repository has only one contributor, consider collaborating")
+		fmt.Fprintln(w, "- Repository has only one contributor, consider collaborating")
 	}
 
-	return nil
+	if outliers := result.SizeDistribution().Outliers; len(outliers) > 0 {
+		fmt.Fprintf(w, "- %d unusually large commit(s) found, consider splitting changes into smaller commits\n", len(outliers))
+	}
+
+	if canonical := result.CanonicalScopes(); len(canonical) > 0 && len(result.ScopeDrift()) > 0 {
+		fmt.Fprintln(w, "- Enforce a canonical scope list by adding this check to your team's convention_checks:")
+		check, _ := json.MarshalIndent(analyze.ScopeConventionCheck(canonical), "    ", "  ")
+		fmt.Fprintf(w, "    %s\n", check)
+	}
 }
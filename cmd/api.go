@@ -0,0 +1,287 @@
+// cmd/api.go
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/analysis"
+	"github.com/jasonKoogler/comma/internal/analyze"
+	"github.com/jasonKoogler/comma/internal/audit"
+	"github.com/jasonKoogler/comma/internal/commit"
+	"github.com/jasonKoogler/comma/internal/config"
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/llm"
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/jasonKoogler/comma/internal/security"
+	"github.com/jasonKoogler/comma/internal/team"
+)
+
+// The API operations below back 'comma serve' and 'comma rpc'. The commit
+// service and the configuration are not safe for concurrent use, so requests
+// run one at a time.
+var apiMu sync.Mutex
+
+// errInvalidRequest marks errors caused by the caller's input
+var errInvalidRequest = errors.New("invalid request")
+
+// invalidRequest returns an errInvalidRequest with details
+func invalidRequest(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errInvalidRequest, fmt.Sprintf(format, args...))
+}
+
+// generateRequest asks for a message for a diff, or for the staged changes
+// of a repository when no diff is given
+type generateRequest struct {
+	Diff    string   `json:"diff,omitempty"`
+	Repo    string   `json:"repo,omitempty"`
+	Issues  []string `json:"issues,omitempty"`
+	NoCache bool     `json:"no_cache,omitempty"`
+}
+
+// apiGenerate generates a commit message. Changes with blocking security
+// findings are rejected with ErrSensitiveDataFound and the findings.
+func apiGenerate(ctx context.Context, req generateRequest) (*generateOutput, error) {
+	apiMu.Lock()
+	defer apiMu.Unlock()
+
+	source, changes, err := apiChanges(req.Diff, req.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &generateOutput{Findings: []security.Finding{}, TraceID: logging.TraceID()}
+	if appContext.ConfigManager.GetBool(config.SecurityScanSensitiveDataKey) {
+		if repo, ok := source.(*git.Repository); ok {
+			if err := applyAllowlist(repo); err != nil {
+				return nil, err
+			}
+		}
+		findings, blocking, err := scanSource(source, changes)
+		if err != nil {
+			return nil, err
+		}
+		out.Findings = findings
+		if blocking > 0 {
+			return out, fmt.Errorf("%w: %d finding(s) at %s severity or above", apperrors.ErrSensitiveDataFound,
+				blocking, blockSeverity())
+		}
+	}
+
+	commitService, err := getCommitService()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := commitService.Generate(ctx, source, commit.GenerateOptions{NoCache: req.NoCache, Issues: req.Issues})
+	if err != nil {
+		recordAuditEvent(source, audit.Event{Action: audit.ActionGenerate, Status: "error", Error: err.Error()})
+		return nil, fmt.Errorf("failed to generate commit message: %w", err)
+	}
+	if result.Cached {
+		recordAuditEvent(source, audit.Event{Action: audit.ActionGenerate, Status: "cached"})
+	} else {
+		recordAuditEvent(source, generatedEvent(result))
+	}
+
+	out.Message = result.Message
+	out.Subject, out.Body = commit.SplitMessage(result.Message)
+	out.Type = result.Type
+	out.Scope = result.Scope
+	out.Confidence = result.Confidence
+	out.Provider = llm.ActiveProvider(appContext)
+	out.Model = appContext.ConfigManager.GetString(config.LLMModelKey)
+	out.Cached = result.Cached
+	out.Usage = result.Usage
+	if result.Provider != "" {
+		out.Provider, out.Model = result.Provider, result.Model
+	}
+	if result.Cached && result.CacheEntry != nil {
+		out.Provider = result.CacheEntry.Provider
+	}
+	return out, nil
+}
+
+// classifyRequest asks for the likely commit types of a diff, or of the
+// staged changes of a repository when no diff is given
+type classifyRequest struct {
+	Diff string `json:"diff,omitempty"`
+	Repo string `json:"repo,omitempty"`
+}
+
+// classifyResponse lists the suggested types, most likely first
+type classifyResponse struct {
+	Types []analysis.CommitType `json:"types"`
+}
+
+// apiClassify suggests commit types and scopes without calling the LLM
+func apiClassify(req classifyRequest) (*classifyResponse, error) {
+	apiMu.Lock()
+	defer apiMu.Unlock()
+
+	source, changes, err := apiChanges(req.Diff, req.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	commitService, err := getCommitService()
+	if err != nil {
+		return nil, err
+	}
+
+	types := commitService.Classify(source, changes)
+	if types == nil {
+		types = []analysis.CommitType{}
+	}
+	return &classifyResponse{Types: types}, nil
+}
+
+// lintRequest checks a single message, or the commits in a revision range
+// when Range is set
+type lintRequest struct {
+	Message string `json:"message,omitempty"`
+	Range   string `json:"range,omitempty"`
+	Repo    string `json:"repo,omitempty"`
+	Team    string `json:"team,omitempty"`
+}
+
+// lintResponse holds the problems with a message or the report for a range
+type lintResponse struct {
+	Valid    bool             `json:"valid"`
+	Problems []string         `json:"problems"`
+	Report   *team.LintReport `json:"report,omitempty"`
+}
+
+// apiLint checks commit messages against the team's conventions
+func apiLint(req lintRequest) (*lintResponse, error) {
+	apiMu.Lock()
+	defer apiMu.Unlock()
+
+	if req.Message == "" && req.Range == "" {
+		return nil, invalidRequest("message or range is required")
+	}
+
+	name := req.Team
+	if name == "" {
+		name = appContext.ConfigManager.GetString(config.TeamNameKey)
+	}
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return nil, err
+	}
+	if err := teamMgr.LoadTeam(name); err != nil {
+		return nil, fmt.Errorf("failed to load team: %w", err)
+	}
+
+	if req.Message != "" {
+		valid, problems := teamMgr.ValidateCommitMessage(req.Message)
+		if problems == nil {
+			problems = []string{}
+		}
+		return &lintResponse{Valid: valid, Problems: problems}, nil
+	}
+
+	repo, err := apiRepository(req.Repo)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := repo.GetCommitRange(req.Range)
+	if err != nil {
+		return nil, invalidRequest("%v", err)
+	}
+
+	lintCommits := make([]team.LintCommit, len(commits))
+	for i, c := range commits {
+		lintCommits[i] = team.LintCommit{Hash: c.Hash, Author: c.Author, Message: c.Message}
+	}
+	report := teamMgr.LintCommits(lintCommits)
+	return &lintResponse{Valid: report.Compliant == report.Commits, Problems: []string{}, Report: report}, nil
+}
+
+// analyzeRequest selects the repository and window to analyze
+type analyzeRequest struct {
+	Repo  string `json:"repo,omitempty"`
+	Days  int    `json:"days,omitempty"`
+	Since string `json:"since,omitempty"` // YYYY-MM-DD
+	Until string `json:"until,omitempty"` // YYYY-MM-DD
+}
+
+// apiAnalyze analyzes the commit history of a repository
+func apiAnalyze(req analyzeRequest) (*analyze.AnalysisResult, error) {
+	apiMu.Lock()
+	defer apiMu.Unlock()
+
+	repo, err := apiRepository(req.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now()
+	if req.Until != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.Until, time.Local)
+		if err != nil {
+			return nil, invalidRequest("invalid until date %q (use YYYY-MM-DD)", req.Until)
+		}
+		until = date.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	days := req.Days
+	if days <= 0 {
+		days = 30
+	}
+	since := until.AddDate(0, 0, -days)
+	if req.Since != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.Since, time.Local)
+		if err != nil {
+			return nil, invalidRequest("invalid since date %q (use YYYY-MM-DD)", req.Since)
+		}
+		since = date
+	}
+
+	result, err := appContext.AnalyzeService().AnalyzeRange(repo, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze repository: %w", err)
+	}
+	return result, nil
+}
+
+// apiChanges returns the changes a request is about: its diff, or the staged
+// changes of its repository
+func apiChanges(diff, path string) (git.ChangeSource, string, error) {
+	var source git.ChangeSource
+	if diff != "" {
+		source = git.NewPatch("api", diff)
+	} else {
+		repo, err := apiRepository(path)
+		if err != nil {
+			return nil, "", err
+		}
+		source = repo
+	}
+
+	changes, err := source.GetStagedChanges()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get staged changes: %w", err)
+	}
+	if changes == "" {
+		return nil, "", fmt.Errorf("%w: send a diff or stage changes in the repository", apperrors.ErrGitNoChanges)
+	}
+	return source, changes, nil
+}
+
+// apiRepository opens the repository a request names, defaulting to the
+// directory the server was started in
+func apiRepository(path string) (*git.Repository, error) {
+	if path == "" {
+		path = "."
+	}
+	repo, err := git.NewRepository(path)
+	if err != nil {
+		return nil, invalidRequest("%s: %v", path, err)
+	}
+	return repo, nil
+}
@@ -0,0 +1,168 @@
+// cmd/approve.go
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/team"
+	"github.com/spf13/cobra"
+)
+
+var (
+	approveTeamName string
+	approveKey      string
+
+	approveCmd = &cobra.Command{
+		Use:   "approve [approval-id]",
+		Short: "Sign off on commits awaiting team approval",
+		Long: `Sign off on commits awaiting team approval.
+Teams with requires_approval enabled record generated commits as pending until
+one of the team's admin users approves them. Run without arguments to list
+pending requests.
+
+Approvals are signed with the admin's SSH key, which must be listed in the
+team's admin_keys; the approver is the admin the key belongs to. With a team
+server configured, requests and approvals are shared through it, so authors
+and reviewers can be on different machines.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runApprove,
+	}
+)
+
+func init() {
+	approveCmd.Flags().StringVar(&approveTeamName, "team-name", "", "team the approval belongs to (default: team.name or the repository's team)")
+	approveCmd.Flags().StringVar(&approveKey, "key", "", "SSH key to sign the approval with (default: git's user.signingkey or ~/.ssh/id_ed25519)")
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	name := approveTeamName
+	if name == "" {
+		name = appContext.ConfigManager.GetString(config.TeamNameKey)
+	}
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return err
+	}
+	if err := teamMgr.LoadTeam(name); err != nil {
+		return fmt.Errorf("failed to load team: %w", err)
+	}
+
+	if len(args) == 0 {
+		pending, err := teamMgr.ListApprovals(team.ApprovalPending)
+		if err != nil {
+			return fmt.Errorf("failed to list approval requests: %w", err)
+		}
+
+		if len(pending) == 0 {
+			fmt.Println("No commits are awaiting approval.")
+			return nil
+		}
+
+		fmt.Println("Commits awaiting approval:")
+		for _, approval := range pending {
+			subject := strings.SplitN(approval.Message, "\n", 2)[0]
+			fmt.Printf("  %s  %s  (%s, team %s)\n", approval.ID, subject, approval.Author, approval.Team)
+		}
+		fmt.Println("\nRun 'comma approve <id>' to sign off.")
+		return nil
+	}
+
+	key := approveKey
+	if key == "" {
+		key = team.SigningKey()
+	}
+
+	approval, err := teamMgr.Approve(args[0], key)
+	if err != nil {
+		return fmt.Errorf("failed to approve commit: %w", err)
+	}
+
+	fmt.Printf("✓ Commit %s approved by %s\n", approval.ID, approval.ApprovedBy)
+	fmt.Println("The author can now commit the approved message.")
+	return nil
+}
+
+// unapprovedID returns the approval ID of committing the staged changes with
+// message when the team requires an approval that has not been given, or ""
+func unapprovedID(repo *git.Repository, message string) (string, error) {
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return "", err
+	}
+	if !teamMgr.RequiresApproval() {
+		return "", nil
+	}
+
+	// The full staged diff, since the prompt's leaves out lockfiles,
+	// generated files and anything past max_diff_bytes
+	diff, err := repo.StagedDiffReader()
+	if err != nil {
+		return "", err
+	}
+	id, err := team.ApprovalID(message, diff)
+	if closeErr := diff.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to hash staged changes: %w", err)
+	}
+
+	if teamMgr.IsApproved(id) {
+		return "", nil
+	}
+	return id, nil
+}
+
+// pendingApproval is returned by an approval check when the message the
+// pre-commit hooks settled on has not been approved
+type pendingApproval struct {
+	id      string
+	message string
+}
+
+func (e *pendingApproval) Error() string {
+	return fmt.Sprintf("commit is awaiting team approval (%s)", e.id)
+}
+
+// approvalCheck returns a commit check that refuses messages the team has not
+// approved for the staged changes. It runs after the pre-commit hooks, so the
+// approved message is the one that is committed.
+func approvalCheck(repo *git.Repository) func(message string) error {
+	return func(message string) error {
+		id, err := unapprovedID(repo, message)
+		if err != nil {
+			return err
+		}
+		if id != "" {
+			return &pendingApproval{id: id, message: message}
+		}
+		return nil
+	}
+}
+
+// requestApproval records a pending approval for a commit and tells the user who can sign off
+func requestApproval(id, message, author string) error {
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return err
+	}
+	approval, err := teamMgr.RequestApproval(id, message, author)
+	if err != nil {
+		return fmt.Errorf("failed to record approval request: %w", err)
+	}
+
+	fmt.Printf("Team '%s' requires approval before committing.\n", approval.Team)
+	fmt.Printf("Approval request %s is pending.\n", approval.ID)
+	if len(approval.Reviewers) > 0 {
+		fmt.Printf("Reviewers: %s\n", strings.Join(approval.Reviewers, ", "))
+	}
+	fmt.Printf("Once a reviewer runs 'comma approve %s', commit again with the same message.\n", approval.ID)
+	return nil
+}
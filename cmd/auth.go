@@ -0,0 +1,465 @@
+// cmd/auth.go
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/llm"
+	"github.com/jasonKoogler/comma/internal/vault"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	authKeyFromStdin     bool
+	authSkipPing         bool
+	authRemovePassphrase bool
+	authAgentTTL         time.Duration
+
+	authCmd = &cobra.Command{
+		Use:   "auth",
+		Short: "Manage stored API keys",
+	}
+
+	authRotateCmd = &cobra.Command{
+		Use:   "rotate <provider>",
+		Short: "Replace the stored API key for a provider",
+		Long: `Replace the stored API key for a provider.
+The new key is verified after it is written, and the previous key is restored
+if the update fails. Rotating resets the key's age for expiry warnings.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAuthRotate,
+	}
+
+	authSetCmd = &cobra.Command{
+		Use:   "set <provider>",
+		Short: "Store an API key for a provider",
+		Long: `Store an API key for a provider in the credential store: the system keyring
+(or the encrypted file when there is no keyring), or the backend named by
+security.credential_backend. The key is asked for without echoing it, or read
+from stdin with --stdin.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAuthSet,
+	}
+
+	authRemoveCmd = &cobra.Command{
+		Use:   "remove <provider>",
+		Short: "Delete the stored API key for a provider",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAuthRemove,
+	}
+
+	authStatusCmd = &cobra.Command{
+		Use:   "status [provider]",
+		Short: "Show where a provider's API key comes from and test it",
+		Long: `Show every place Comma looks for a provider's API key, in the order it looks,
+and which one is used. The key that is used is tested with a minimal request
+to the provider unless --no-ping is given or Comma is offline. The provider
+defaults to the configured one.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runAuthStatus,
+	}
+
+	authPassphraseCmd = &cobra.Command{
+		Use:   "passphrase",
+		Short: "Protect the encrypted credentials file with a passphrase",
+		Long: `Protect the encrypted credentials file with a passphrase, or change it.
+Without one, the file's key is derived from the host name and user name,
+which others may guess. Keys in the system keyring or an external backend
+are not affected.
+
+The passphrase is asked for once and then remembered as set by
+security.passphrase_cache: by a running 'comma auth agent' (the default) or
+in the system keyring. ` + vault.PassphraseEnv + ` supplies it without a prompt.`,
+		Args: cobra.NoArgs,
+		RunE: runAuthPassphrase,
+	}
+
+	authAgentCmd = &cobra.Command{
+		Use:   "agent",
+		Short: "Remember the credentials passphrase for other commands",
+		Long: `Remember the passphrase of the encrypted credentials file in memory, so it
+is typed only once. The agent listens on a socket in the config directory
+(or ` + vault.AgentSocketEnv + `) until --ttl passes or it is stopped, e.g.
+
+  comma auth agent --ttl 4h &`,
+		Args: cobra.NoArgs,
+		RunE: runAuthAgent,
+	}
+
+	authLockCmd = &cobra.Command{
+		Use:   "lock",
+		Short: "Forget the remembered credentials passphrase",
+		Args:  cobra.NoArgs,
+		RunE:  runAuthLock,
+	}
+)
+
+func init() {
+	authSetCmd.Flags().BoolVar(&authKeyFromStdin, "stdin", false, "read the key from stdin")
+	authStatusCmd.Flags().BoolVar(&authSkipPing, "no-ping", false, "don't send a request to test the key")
+	authPassphraseCmd.Flags().BoolVar(&authRemovePassphrase, "remove", false, "go back to the machine-derived key")
+	authAgentCmd.Flags().DurationVar(&authAgentTTL, "ttl", 8*time.Hour, "how long to remember the passphrase (0 for until stopped)")
+
+	authCmd.AddCommand(authRotateCmd)
+	authCmd.AddCommand(authSetCmd)
+	authCmd.AddCommand(authRemoveCmd)
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authPassphraseCmd)
+	authCmd.AddCommand(authAgentCmd)
+	authCmd.AddCommand(authLockCmd)
+}
+
+func runAuthRotate(cmd *cobra.Command, args []string) error {
+	credMgr, err := credentialManager()
+	if err != nil {
+		return err
+	}
+
+	provider := args[0]
+
+	newKey, err := promptAPIKey(fmt.Sprintf("New %s API Key", provider))
+	if err != nil {
+		if err == promptui.ErrInterrupt {
+			return fmt.Errorf("rotation cancelled")
+		}
+		return err
+	}
+
+	if err := credMgr.Rotate(provider, newKey); err != nil {
+		return fmt.Errorf("failed to rotate %s key: %w", provider, err)
+	}
+
+	fmt.Printf("✓ %s API key rotated\n", provider)
+	fmt.Println("Remember to revoke the old key with your provider.")
+	return nil
+}
+
+func runAuthSet(cmd *cobra.Command, args []string) error {
+	credMgr, err := credentialManager()
+	if err != nil {
+		return err
+	}
+
+	provider := args[0]
+
+	var key string
+	if authKeyFromStdin {
+		key, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return fmt.Errorf("failed to read API key from stdin: %w", err)
+		}
+		if err = validateAPIKey(key); err != nil {
+			return err
+		}
+	} else {
+		key, err = promptAPIKey(fmt.Sprintf("%s API Key", provider))
+		if err == promptui.ErrInterrupt {
+			return fmt.Errorf("cancelled")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := credMgr.Store(provider, key); err != nil {
+		return fmt.Errorf("failed to store %s key: %w", provider, err)
+	}
+
+	fmt.Printf("✓ %s API key stored in the %s\n", provider, credMgr.Location(provider))
+	fmt.Printf("Run 'comma auth status %s' to test it.\n", provider)
+	return nil
+}
+
+func runAuthRemove(cmd *cobra.Command, args []string) error {
+	credMgr, err := credentialManager()
+	if err != nil {
+		return err
+	}
+
+	provider := args[0]
+
+	removed, err := credMgr.Remove(provider)
+	if err != nil {
+		return fmt.Errorf("failed to remove %s key: %w", provider, err)
+	}
+	if !removed {
+		fmt.Printf("No %s API key is stored.\n", provider)
+		return nil
+	}
+
+	fmt.Printf("✓ %s API key removed\n", provider)
+	fmt.Println("Remember to revoke it with your provider if it is no longer needed.")
+	return nil
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) error {
+	credMgr, err := credentialManager()
+	if err != nil {
+		return err
+	}
+
+	provider := llm.ActiveProvider(appContext)
+	if len(args) > 0 {
+		provider = args[0]
+	}
+	if provider == "" {
+		return fmt.Errorf("no provider configured - name one, e.g. 'comma auth status openai'")
+	}
+
+	fmt.Printf("%s API key sources, in the order they are checked:\n", provider)
+
+	var used *llm.KeySource
+	sources := llm.APIKeySources(provider, credMgr, appContext)
+	for i := range sources {
+		source := &sources[i]
+
+		state := "not set"
+		if source.Err != nil {
+			state = source.Err.Error()
+		}
+		marker := " "
+		if source.Key != "" {
+			state = maskKey(source.Key)
+			if used == nil {
+				used = source
+				marker = "✓"
+				state += ", used"
+			} else {
+				state += ", ignored"
+			}
+		}
+		if source.Name == llm.KeySourceVault && source.Key != "" {
+			if info, err := credMgr.RecordFirstSeen(provider, source.Key); err == nil {
+				state += fmt.Sprintf(", stored %d days ago", int(info.Age()/(24*time.Hour)))
+			}
+		}
+
+		fmt.Printf("  %s %-30s %s\n", marker, keySourceLabel(cmd, *source), state)
+	}
+
+	if used == nil {
+		fmt.Printf("⚠️  No API key found. Store one with 'comma auth set %s'.\n", provider)
+		return nil
+	}
+
+	if authSkipPing || appContext.IsOffline() {
+		return nil
+	}
+
+	fmt.Printf("Testing the key with %s...\n", provider)
+	start := time.Now()
+	if err := llm.Ping(context.Background(), provider, used.Key, appContext); err != nil {
+		return fmt.Errorf("%s rejected the request: %w", provider, err)
+	}
+	fmt.Printf("✓ %s accepted the key (%s)\n", provider, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func runAuthPassphrase(cmd *cobra.Command, args []string) error {
+	credMgr, err := credentialManager()
+	if err != nil {
+		return err
+	}
+
+	if authRemovePassphrase {
+		if !credMgr.FileProtected() {
+			fmt.Println("The encrypted credentials file has no passphrase.")
+			return nil
+		}
+		if err := credMgr.SetFilePassphrase(""); err != nil {
+			return fmt.Errorf("failed to remove passphrase: %w", err)
+		}
+		fmt.Println("✓ Passphrase removed; the encrypted credentials file uses the machine key again")
+		return nil
+	}
+
+	// Unlock the file first, so the old passphrase is not asked for after
+	// the new one
+	if err := credMgr.Unlock(); err != nil {
+		return fmt.Errorf("failed to unlock credentials file: %w", err)
+	}
+
+	passphrase, err := promptNewPassphrase()
+	if err != nil {
+		return err
+	}
+	if err := credMgr.SetFilePassphrase(passphrase); err != nil {
+		return fmt.Errorf("failed to set passphrase: %w", err)
+	}
+
+	fmt.Println("✓ Encrypted credentials file is now passphrase protected")
+	if appContext.ConfigManager.GetString(config.SecurityPassphraseCacheKey) == vault.PassphraseCacheAgent {
+		fmt.Println("Run 'comma auth agent &' to type the passphrase only once per session.")
+	}
+	return nil
+}
+
+func runAuthAgent(cmd *cobra.Command, args []string) error {
+	credMgr, err := credentialManager()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	socket := credMgr.AgentSocket()
+	fmt.Printf("✓ Passphrase agent listening on %s\n", socket)
+	return vault.ServeAgent(ctx, socket, authAgentTTL)
+}
+
+func runAuthLock(cmd *cobra.Command, args []string) error {
+	credMgr, err := credentialManager()
+	if err != nil {
+		return err
+	}
+
+	credMgr.ForgetPassphrase()
+	fmt.Println("✓ Credentials passphrase forgotten")
+	return nil
+}
+
+// promptNewPassphrase asks for a new passphrase twice
+func promptNewPassphrase() (string, error) {
+	if err := requireTerminal("New passphrase"); err != nil {
+		return "", err
+	}
+
+	first := promptui.Prompt{
+		Label: "New passphrase",
+		Mask:  '*',
+		Validate: func(input string) error {
+			if len(input) < 8 {
+				return fmt.Errorf("passphrase must be at least 8 characters")
+			}
+			return nil
+		},
+	}
+	passphrase, err := first.Run()
+	if err != nil {
+		if err == promptui.ErrInterrupt {
+			return "", fmt.Errorf("cancelled")
+		}
+		return "", fmt.Errorf("prompt failed: %w", err)
+	}
+
+	again := promptui.Prompt{Label: "Repeat passphrase", Mask: '*'}
+	repeated, err := again.Run()
+	if err != nil {
+		if err == promptui.ErrInterrupt {
+			return "", fmt.Errorf("cancelled")
+		}
+		return "", fmt.Errorf("prompt failed: %w", err)
+	}
+	if passphrase == "" || repeated != passphrase {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+
+	return passphrase, nil
+}
+
+// keySourceLabel describes a key source for 'comma auth status'
+func keySourceLabel(cmd *cobra.Command, source llm.KeySource) string {
+	switch source.Name {
+	case llm.KeySourceVault:
+		return "vault (" + source.Detail + ")"
+	case llm.KeySourceEnv:
+		return "env " + source.Detail
+	case llm.KeySourceLLM:
+		if flag := cmd.Flags().Lookup("api-key"); flag != nil && flag.Changed {
+			return "flag --api-key"
+		}
+	}
+	return "config " + source.Detail
+}
+
+// credentialManager returns the credential manager for the auth commands
+func credentialManager() (*vault.CredentialManager, error) {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return nil, fmt.Errorf("configuration manager not initialized")
+	}
+	return appContext.CredentialMgr()
+}
+
+// maskKey shows only the end of an API key
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return strings.Repeat("*", len(key))
+	}
+	return "****" + key[len(key)-4:]
+}
+
+// validateAPIKey rejects keys too short to be real
+func validateAPIKey(key string) error {
+	if len(key) < 8 {
+		return fmt.Errorf("API key is too short")
+	}
+	return nil
+}
+
+// promptAPIKey asks for an API key without echoing it
+func promptAPIKey(label string) (string, error) {
+	if err := requireTerminal(label); err != nil {
+		return "", err
+	}
+
+	keyPrompt := promptui.Prompt{
+		Label:    label,
+		Mask:     '*',
+		Validate: validateAPIKey,
+	}
+
+	key, err := keyPrompt.Run()
+	if err != nil && err != promptui.ErrInterrupt {
+		return "", fmt.Errorf("prompt failed: %w", err)
+	}
+	return key, err
+}
+
+// checkKeyAge warns when a stored API key is older than the configured maximum
+// age, or refuses to continue when the expiry policy is "block". Keys stored
+// without metadata are aged from the first time they are checked.
+func checkKeyAge(provider, apiKey string) error {
+	maxAgeDays := appContext.ConfigManager.GetInt(config.SecurityKeyMaxAgeKey)
+	if maxAgeDays <= 0 {
+		return nil
+	}
+
+	// Keys supplied through the environment are managed outside Comma
+	if os.Getenv(config.GetProviderAPIEnvVar(provider)) != "" {
+		return nil
+	}
+
+	credMgr, err := appContext.CredentialMgr()
+	if err != nil {
+		return nil
+	}
+	info, err := credMgr.RecordFirstSeen(provider, apiKey)
+	if err != nil {
+		return nil
+	}
+
+	ageDays := int(info.Age() / (24 * time.Hour))
+	if ageDays < maxAgeDays {
+		return nil
+	}
+
+	if appContext.ConfigManager.GetString(config.SecurityKeyExpiryPolicyKey) == "block" {
+		return fmt.Errorf("%s API key is %d days old (maximum %d) - run 'comma auth rotate %s'",
+			provider, ageDays, maxAgeDays, provider)
+	}
+
+	fmt.Printf("⚠️  Your %s API key is %d days old. Consider running 'comma auth rotate %s'.\n",
+		provider, ageDays, provider)
+	return nil
+}
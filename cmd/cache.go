@@ -0,0 +1,126 @@
+// cmd/cache.go
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/commit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clearExpired bool
+
+	cacheCmd = &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and manage the commit message cache",
+	}
+
+	cacheStatsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Show cache hit rate, entries and disk usage",
+		RunE:  runCacheStats,
+	}
+
+	cacheClearCmd = &cobra.Command{
+		Use:   "clear",
+		Short: "Remove cached commit messages",
+		Long: `Remove cached commit messages.
+By default every entry is removed and the hit/miss counters are reset.
+Use --expired to only remove entries older than the cache's maximum age.`,
+		RunE: runCacheClear,
+	}
+)
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+
+	cacheClearCmd.Flags().BoolVar(&clearExpired, "expired", false, "only remove expired entries")
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	if appContext == nil {
+		return fmt.Errorf("commit cache not initialized")
+	}
+
+	commitCache, err := appContext.Cache()
+	if err != nil {
+		return err
+	}
+
+	stats, err := commitCache.Stats()
+	if err != nil {
+		return fmt.Errorf("failed to read cache statistics: %w", err)
+	}
+
+	fmt.Println("Commit Cache")
+	fmt.Println("============")
+	fmt.Printf("Hits: %d\n", stats.Hits)
+	fmt.Printf("Misses: %d\n", stats.Misses)
+	fmt.Printf("Hit rate: %.1f%%\n", stats.HitRate()*100)
+	fmt.Printf("Entries: %d (%d expired)\n", stats.Entries, stats.Expired)
+	fmt.Printf("Disk usage: %s\n", formatBytes(stats.DiskUsage))
+	if !stats.OldestEntry.IsZero() {
+		fmt.Printf("Oldest entry: %s (%s ago)\n",
+			stats.OldestEntry.Format("2006-01-02 15:04"), time.Since(stats.OldestEntry).Round(time.Minute))
+	}
+
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	if appContext == nil {
+		return fmt.Errorf("commit cache not initialized")
+	}
+
+	commitCache, err := appContext.Cache()
+	if err != nil {
+		return err
+	}
+
+	removed, err := commitCache.Clear(clearExpired)
+	if err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	if clearExpired {
+		fmt.Printf("✓ Removed %d expired cache entries\n", removed)
+	} else {
+		fmt.Printf("✓ Removed %d cache entries\n", removed)
+	}
+	return nil
+}
+
+// printCacheProvenance explains where a cached commit message came from
+func printCacheProvenance(result *commit.Result) {
+	entry := result.CacheEntry
+	if entry == nil {
+		return
+	}
+
+	match := "identical changes"
+	if result.Similarity < 1 {
+		match = fmt.Sprintf("a similar diff (%.0f%% match)", result.Similarity*100)
+	}
+
+	fmt.Printf("Cached message for %s, generated %s ago by %s\n",
+		match, time.Since(entry.CreatedAt).Round(time.Minute), entry.Provider)
+	fmt.Printf("  %d files changed, +%d/-%d lines\n",
+		entry.Stats.ChangedFiles, entry.Stats.Additions, entry.Stats.Deletions)
+}
+
+// formatBytes renders a byte count in human-readable units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
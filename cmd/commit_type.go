@@ -0,0 +1,138 @@
+// cmd/commit_type.go
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/analysis"
+	"github.com/jasonKoogler/comma/internal/commit"
+	"github.com/jasonKoogler/comma/internal/conventional"
+)
+
+// typeAnswer matches a commit type typed by the user, e.g. "feat" or
+// "fix(auth)"
+var typeAnswer = regexp.MustCompile(`^([a-z]+)(?:\(([^()\s]+)\))?$`)
+
+// parseCommitType reads "type" or "type(scope)"
+func parseCommitType(answer string) (commitType, scope string, err error) {
+	m := typeAnswer.FindStringSubmatch(strings.TrimSpace(answer))
+	if m == nil {
+		return "", "", fmt.Errorf("%q is not a commit type such as feat or fix(auth)", answer)
+	}
+	return m[1], m[2], nil
+}
+
+// typeLabel formats a type and scope as in a commit header
+func typeLabel(commitType, scope string) string {
+	if scope == "" {
+		return commitType
+	}
+	return commitType + "(" + scope + ")"
+}
+
+// printClassification shows the classifier's suggestions with their
+// confidence
+func printClassification(suggestions []analysis.CommitType) {
+	if len(suggestions) == 0 {
+		fmt.Println("Detected type: none, the model will choose")
+		return
+	}
+
+	top := suggestions[0]
+	fmt.Printf("Detected type: %s, %.0f%% confident", typeLabel(top.Type, top.Scope), top.Confidence*100)
+	if top.Description != "" {
+		fmt.Printf(" (%s)", top.Description)
+	}
+	fmt.Println()
+
+	if len(suggestions) > 1 {
+		others := make([]string, 0, len(suggestions)-1)
+		for _, s := range suggestions[1:] {
+			others = append(others, fmt.Sprintf("%s %.0f%%", s.Type, s.Confidence*100))
+		}
+		fmt.Printf("Also possible: %s\n", strings.Join(others, ", "))
+	}
+	if top.Confidence <= commit.ConfidenceThreshold {
+		fmt.Printf("That is below the %.0f%% needed to suggest it, so the model will choose unless you do.\n",
+			commit.ConfidenceThreshold*100)
+	}
+}
+
+// chooseCommitType asks which type the message should have, offering the
+// suggested types first and then the conventional ones. Enter keeps def,
+// which may be empty to let the model choose; "-" always lets it choose.
+func chooseCommitType(suggestions []analysis.CommitType, def string) (commitType, scope string, err error) {
+	candidates := make([]string, 0, len(suggestions)+len(conventional.Types))
+	seen := make(map[string]bool)
+	for _, s := range suggestions {
+		if !seen[s.Type] {
+			seen[s.Type] = true
+			candidates = append(candidates, s.Type)
+		}
+	}
+	for _, t := range conventional.Types {
+		if !seen[t] {
+			seen[t] = true
+			candidates = append(candidates, t)
+		}
+	}
+
+	numbered := make([]string, len(candidates))
+	for i, t := range candidates {
+		numbered[i] = fmt.Sprintf("%d) %s", i+1, t)
+	}
+	fmt.Println("Types: " + strings.Join(numbered, "  "))
+
+	keys := "Enter: model chooses"
+	if def != "" {
+		keys = "Enter: " + def + ", -: model chooses"
+	}
+	for {
+		answer, err := promptLine(fmt.Sprintf("Type for the message, a number or e.g. fix(auth) [%s]: ", keys))
+		if err != nil {
+			return "", "", err
+		}
+		switch answer {
+		case "":
+			if def == "" {
+				return "", "", nil
+			}
+			return parseCommitType(def)
+		case "-":
+			return "", "", nil
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil {
+			if n < 1 || n > len(candidates) {
+				fmt.Printf("⚠️  Choose a number from 1 to %d.\n", len(candidates))
+				continue
+			}
+			// A suggested type keeps its detected scope
+			for _, s := range suggestions {
+				if s.Type == candidates[n-1] {
+					return s.Type, s.Scope, nil
+				}
+			}
+			return candidates[n-1], "", nil
+		}
+
+		commitType, scope, err := parseCommitType(answer)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			continue
+		}
+		return commitType, scope, nil
+	}
+}
+
+// suggestedDefault is the type Enter keeps in chooseCommitType: the top
+// suggestion when the classifier is confident enough, otherwise none
+func suggestedDefault(suggestions []analysis.CommitType) string {
+	if len(suggestions) == 0 || suggestions[0].Confidence <= commit.ConfidenceThreshold {
+		return ""
+	}
+	return typeLabel(suggestions[0].Type, suggestions[0].Scope)
+}
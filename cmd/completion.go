@@ -0,0 +1,115 @@
+// cmd/completion.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a completion script for your shell.
+
+  # bash (current session, or save it to /etc/bash_completion.d/comma)
+  source <(comma completion bash)
+
+  # zsh
+  comma completion zsh > "${fpath[1]}/_comma"
+
+  # fish
+  comma completion fish > ~/.config/fish/completions/comma.fish
+
+  # PowerShell
+  comma completion powershell | Out-String | Invoke-Expression
+
+Provider, model and team template names are completed from your configuration.`,
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+// registerCompletions adds dynamic completion to flags. It is called from
+// root's init, once the other commands have defined their flags.
+func registerCompletions() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+
+	rootCmd.RegisterFlagCompletionFunc("provider", completeProviders)
+	rootCmd.RegisterFlagCompletionFunc("model", completeModels)
+	generateCmd.RegisterFlagCompletionFunc("model", completeModels)
+	generateCmd.RegisterFlagCompletionFunc("template", completeTemplates)
+	configSetCmd.RegisterFlagCompletionFunc("provider", completeProviders)
+	configSetCmd.RegisterFlagCompletionFunc("model", completeModels)
+	authRotateCmd.ValidArgsFunction = completeProviders
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	}
+	return fmt.Errorf("unsupported shell: %s", args[0])
+}
+
+// completeProviders completes built-in and plugin provider names
+func completeProviders(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	providers := []string{"openai\tOpenAI", "anthropic\tAnthropic", "groq\tGroq", "deepseek\tDeepSeek", "xai\txAI Grok", "local\tLocal models via Ollama", "local-server\tOpenAI-compatible server at llm.endpoint"}
+	for _, info := range llm.RegisteredProviders() {
+		providers = append(providers, fmt.Sprintf("%s\t%s (plugin)", info.Name, info.Description))
+	}
+	return providers, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeModels completes the models of the provider given on the
+// command line, or of the configured provider
+func completeModels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	provider, _ := cmd.Flags().GetString("provider")
+	if provider == "" && appContext != nil {
+		provider = appContext.ConfigManager.GetString(config.LLMProviderKey)
+	}
+
+	if p, ok := llm.LookupProvider(provider); ok {
+		return p.Info().Models, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.ModelOptions(provider), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplates completes the template names of the team given with
+// --team-name, or of the configured team
+func completeTemplates(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	name, _ := cmd.Flags().GetString("team-name")
+	if name == "" {
+		name = appContext.ConfigManager.GetString(config.TeamNameKey)
+	}
+	if err := teamMgr.LoadTeam(name); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, template := range teamMgr.TemplateNames() {
+		if strings.HasPrefix(template, toComplete) {
+			names = append(names, template)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/llm"
 	"github.com/spf13/cobra"
 )
 
@@ -24,11 +26,45 @@ var (
 		Short: "Update configuration values",
 		RunE:  runConfigSet,
 	}
+
+	configUnsetCmd = &cobra.Command{
+		Use:   "unset <key>...",
+		Short: "Remove keys from the config file so their defaults apply",
+		Long: `Remove keys from the config file so their defaults apply.
+A key is a dotted path such as llm.temperature; naming a section such as jira
+removes every key in it.
+
+Examples:
+  comma config unset llm.temperature
+  comma config unset jira issues.keyword`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runConfigUnset,
+	}
+
+	configResetYes bool
+
+	configResetCmd = &cobra.Command{
+		Use:   "reset [section]",
+		Short: "Reset the configuration, or one section of it, to the defaults",
+		Long: `Reset the configuration, or one section of it, to the defaults.
+The old config file is copied next to it first, e.g. to
+config.yaml.20260102-150405.bak.
+
+Examples:
+  comma config reset
+  comma config reset cache --yes`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runConfigReset,
+	}
 )
 
 func init() {
 	configCmd.AddCommand(configViewCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configResetCmd)
+
+	configResetCmd.Flags().BoolVarP(&configResetYes, "yes", "y", false, "reset without asking for confirmation")
 
 	// Add flags to config set command
 	configSetCmd.Flags().String("provider", "", "LLM provider (openai, anthropic, etc.)")
@@ -38,6 +74,7 @@ func init() {
 	configSetCmd.Flags().String("template", "", "template for the commit message")
 	configSetCmd.Flags().Bool("include-diff", false, "include detailed diff in the prompt")
 	configSetCmd.Flags().String("model", "", "model name to use (e.g., gpt-4, claude-3-opus)")
+	configSetCmd.Flags().String("language", "", "language of generated messages (e.g., es, ja, de; empty for English)")
 }
 
 func runConfigView(cmd *cobra.Command, args []string) error {
@@ -54,6 +91,7 @@ func runConfigView(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Max Tokens: %d\n", appContext.ConfigManager.GetInt(config.LLMMaxTokensKey))
 	fmt.Printf("Temperature: %.2f\n", appContext.ConfigManager.GetFloat64(config.LLMTemperatureKey))
 	fmt.Printf("Include Diff: %v\n", appContext.ConfigManager.GetBool(config.IncludeDiffKey))
+	fmt.Printf("Language: %s\n", messageLanguage())
 	fmt.Println("\nTemplate:")
 	fmt.Println(appContext.ConfigManager.GetString(config.TemplateKey))
 
@@ -61,13 +99,23 @@ func runConfigView(cmd *cobra.Command, args []string) error {
 	fmt.Println("----------------")
 	fmt.Println("OpenAI:    gpt-4o, gpt-4-turbo, gpt-4, gpt-3.5-turbo")
 	fmt.Println("Anthropic: claude-3-opus, claude-3-sonnet, claude-3-haiku, claude-3.5-sonnet")
+	fmt.Println("Groq:      llama-3.3-70b-versatile, llama-3.1-8b-instant, gemma2-9b-it, mixtral-8x7b-32768")
+	fmt.Println("DeepSeek:  deepseek-chat, deepseek-reasoner")
+	fmt.Println("xAI:       grok-3-mini, grok-3, grok-2-1212")
 	fmt.Println("Local:     llama3, llama2, mixtral, mistral, phi3")
+	fmt.Println("Local server: whatever model the server at the endpoint has loaded")
+	for _, info := range llm.RegisteredProviders() {
+		fmt.Printf("%-10s %s (plugin)\n", info.Name+":", strings.Join(info.Models, ", "))
+	}
 
 	fmt.Println("\nSecurity Note:")
 	fmt.Println("-------------")
 	fmt.Println("For better security, consider using environment variables instead of storing API keys in config:")
 	fmt.Println("- ANTHROPIC_API_KEY   (for Anthropic Claude)")
 	fmt.Println("- OPENAI_API_KEY      (for OpenAI)")
+	fmt.Println("- GROQ_API_KEY        (for Groq)")
+	fmt.Println("- DEEPSEEK_API_KEY    (for DeepSeek)")
+	fmt.Println("- XAI_API_KEY         (for xAI Grok)")
 
 	return nil
 }
@@ -88,11 +136,24 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Refuse providers the team does not allow before changing anything
+	if cmd.Flags().Changed("provider") && loadActiveTeam() {
+		provider, _ := cmd.Flags().GetString("provider")
+		teamMgr, err := appContext.TeamManager()
+		if err != nil {
+			return err
+		}
+		if err := teamMgr.CheckProvider(provider); err != nil {
+			return err
+		}
+	}
+
 	// Update string configs
 	updateIfSet("provider", config.LLMProviderKey)
 	updateIfSet("endpoint", config.LLMEndpointKey)
 	updateIfSet("template", config.TemplateKey)
 	updateIfSet("model", config.LLMModelKey)
+	updateIfSet("language", config.LLMLanguageKey)
 
 	// Update bool configs
 	if cmd.Flags().Changed("include-diff") {
@@ -127,6 +188,67 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	for _, key := range args {
+		removed, err := appContext.ConfigManager.Unset(key)
+		if err != nil {
+			return fmt.Errorf("failed to unset %s: %w", key, err)
+		}
+		if !removed {
+			fmt.Printf("%s is not set in %s\n", key, appContext.ConfigManager.ConfigFile)
+			continue
+		}
+
+		if value, ok := config.DefaultValues[strings.ToLower(key)]; ok {
+			fmt.Printf("✓ Removed %s; the default %v applies\n", key, value)
+		} else {
+			fmt.Printf("✓ Removed %s\n", key)
+		}
+	}
+	return nil
+}
+
+func runConfigReset(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	section := ""
+	what := "the whole configuration"
+	if len(args) == 1 {
+		section = strings.ToLower(args[0])
+		if !config.IsSection(section) {
+			return fmt.Errorf("unknown config section %q (sections: %s)", section, strings.Join(config.Sections(), ", "))
+		}
+		what = "the " + section + " section"
+	}
+
+	if !configResetYes {
+		confirmed, err := promptYesNo(fmt.Sprintf("Reset %s of %s to the defaults?", what, appContext.ConfigManager.ConfigFile))
+		if err != nil {
+			return fmt.Errorf("%w (pass --yes to reset without asking)", err)
+		}
+		if !confirmed {
+			fmt.Println("Reset cancelled.")
+			return nil
+		}
+	}
+
+	backup, err := appContext.ConfigManager.Reset(section)
+	if err != nil {
+		return fmt.Errorf("failed to reset configuration: %w", err)
+	}
+	if backup != "" {
+		fmt.Printf("Backed up the old configuration to %s\n", backup)
+	}
+	fmt.Printf("✓ Reset %s to the defaults\n", what)
+	return nil
+}
+
 // func runConfigTui(cmd *cobra.Command, args []string) error {
 // 	// Make sure config is loaded before starting the TUI
 // 	if viper.ConfigFileUsed() == "" {
@@ -139,3 +261,12 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 // 	// Use the TUI package's RunConfigTUI function
 // 	return tui.RunConfigTUI(appContext)
 // }
+
+// messageLanguage describes the configured llm.language for display
+func messageLanguage() string {
+	language := appContext.ConfigManager.GetString(config.LLMLanguageKey)
+	if language == "" {
+		return "English (default)"
+	}
+	return llm.LanguageName(language)
+}
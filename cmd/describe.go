@@ -0,0 +1,274 @@
+// cmd/describe.go
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/audit"
+	"github.com/jasonKoogler/comma/internal/commit"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// describeMaxCommits bounds how many commits one run sends to the LLM
+const describeMaxCommits = 100
+
+var (
+	describeRange    string
+	describeFormat   string
+	describeOutput   string
+	describeShowDiff bool
+
+	describeCmd = &cobra.Command{
+		Use:   "describe [rev]",
+		Short: "Generate better messages for existing commits",
+		Long: `Generate better messages for existing commits.
+Reads the diff of a commit, or of every non-merge commit in --range, and
+generates an improved message from it, taking the current message into
+account. Nothing is rewritten: the result is a mapping from commit hashes to
+new messages.
+
+Formats:
+  text         each commit's current subject followed by the new message
+  json         an object mapping full commit hashes to new messages
+  filter-repo  a commit callback for git filter-repo that applies the messages
+
+Examples:
+  comma describe HEAD~2
+  comma describe --range main..feature --diff
+  comma describe --range v1.0.0..HEAD --format filter-repo -o reword.py
+  git filter-repo --refs main --commit-callback "$(cat reword.py)"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runDescribe,
+	}
+)
+
+func init() {
+	describeCmd.Flags().StringVar(&describeRange, "range", "", "revision range of commits to describe, e.g. main..HEAD")
+	describeCmd.Flags().StringVar(&describeFormat, "format", "text", "output format (text, json, filter-repo)")
+	describeCmd.Flags().StringVarP(&describeOutput, "output", "o", "", "write the output to a file instead of stdout")
+	describeCmd.Flags().BoolVar(&describeShowDiff, "diff", false, "show each commit's diff")
+	describeCmd.Flags().BoolVar(&noCache, "no-cache", false, "bypass commit cache")
+	describeCmd.Flags().BoolVar(&skipScan, "skip-scan", false, "skip security scanning")
+}
+
+// describedCommit is an existing commit with its generated message
+type describedCommit struct {
+	Hash     string
+	Original string
+	Message  string
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	switch describeFormat {
+	case "text", "json", "filter-repo":
+	default:
+		return fmt.Errorf("unsupported format: %s (use text, json or filter-repo)", describeFormat)
+	}
+
+	revRange := describeRange
+	switch {
+	case revRange != "" && len(args) > 0:
+		return fmt.Errorf("give either a revision or --range, not both")
+	case len(args) > 0:
+		revRange = args[0] + "^!"
+	case revRange == "":
+		revRange = "HEAD^!"
+	}
+
+	if err := validateConfig(); err != nil {
+		return fmt.Errorf("%w\nRun 'comma setup' to configure your LLM provider and API key", err)
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	commits, err := repo.GetCommitRange(revRange)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no non-merge commits in %s", revRange)
+	}
+	if len(commits) > describeMaxCommits {
+		return fmt.Errorf("%d commits in %s; describe at most %d at a time", len(commits), revRange, describeMaxCommits)
+	}
+
+	commitService, err := getCommitService()
+	if err != nil {
+		return err
+	}
+
+	// Structured output goes to stdout only when nothing else does
+	chatty := describeFormat == "text" || describeOutput != ""
+
+	// Oldest first, in the order the commits would be reworded
+	var described []describedCommit
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		message, err := describeCommit(cmd, repo, commitService, c, chatty)
+		if err != nil {
+			return err
+		}
+		if message == "" {
+			continue
+		}
+		described = append(described, describedCommit{Hash: c.Hash, Original: c.Message, Message: message})
+		if describeFormat == "text" {
+			printDescribedCommit(described[len(described)-1])
+		}
+	}
+
+	if describeFormat == "text" {
+		return nil
+	}
+
+	out := os.Stdout
+	if describeOutput != "" {
+		f, err := os.Create(describeOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if describeFormat == "json" {
+		mapping := make(map[string]string, len(described))
+		for _, d := range described {
+			mapping[d.Hash] = d.Message
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(mapping); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	} else if _, err := fmt.Fprint(out, filterRepoCallback(described)); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	if describeOutput != "" {
+		fmt.Printf("✓ Wrote %d message(s) to %s\n", len(described), describeOutput)
+	}
+	return nil
+}
+
+// describeCommit generates a new message for one commit
+func describeCommit(cmd *cobra.Command, repo *git.Repository, commitService *commit.Service, c git.Commit, chatty bool) (string, error) {
+	diff, err := repo.CommitDiff(c.Hash)
+	if err != nil {
+		return "", err
+	}
+
+	if describeShowDiff && describeFormat == "text" && strings.TrimSpace(diff) != "" {
+		fmt.Printf("\ncommit %s\n\n", c.Hash)
+		if err := showCommitDiff(repo, c.Hash); err != nil {
+			return "", err
+		}
+	}
+
+	return describeDiff(cmd, repo, commitService, c.Hash[:min(len(c.Hash), 7)], diff, c.Message, chatty)
+}
+
+// showCommitDiff prints a commit's full diff as git produces it, rendering
+// one hunk at a time
+func showCommitDiff(repo *git.Repository, hash string) error {
+	diff, err := repo.CommitDiffReader(hash)
+	if err != nil {
+		return err
+	}
+
+	err = appContext.Renderer().RenderDiffTo(os.Stdout, diff, "")
+	if closeErr := diff.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to show diff: %w", err)
+	}
+	fmt.Println()
+	return nil
+}
+
+// describeDiff generates a message for a diff that is already recorded in
+// the repository, such as a commit or a stash, given its current message.
+// Empty diffs and diffs with blocking scan findings are skipped with a
+// warning and give "", since there is nobody to ask about each of them.
+func describeDiff(cmd *cobra.Command, repo *git.Repository, commitService *commit.Service, label, diff, original string, chatty bool) (string, error) {
+	if strings.TrimSpace(diff) == "" {
+		if chatty {
+			fmt.Printf("⚠️  %s has no changes, skipping\n", label)
+		}
+		return "", nil
+	}
+
+	source := git.NewPatch(repo.Name(), diff)
+	changes, err := source.GetStagedChanges()
+	if err != nil {
+		return "", err
+	}
+
+	if !skipScan {
+		_, blocking, err := scanSource(source, changes)
+		if err != nil {
+			return "", err
+		}
+		if blocking > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  %s has %d blocking finding(s), skipping (use --skip-scan to bypass)\n", label, blocking)
+			return "", nil
+		}
+	}
+
+	progress := newProgress(chatty)
+	progress.Start(fmt.Sprintf("Describing %s", label))
+	ctx, stop := interruptible(cmd.Context())
+	result, err := commitService.Generate(ctx, source, commit.GenerateOptions{NoCache: noCache, Original: original})
+	stop()
+	progress.Stop()
+	if err != nil {
+		recordAuditEvent(repo, audit.Event{Action: audit.ActionGenerate, Status: "error", Error: err.Error()})
+		return "", fmt.Errorf("failed to describe %s: %w", label, err)
+	}
+	if result.Cached {
+		recordAuditEvent(repo, audit.Event{Action: audit.ActionGenerate, Status: "cached"})
+	} else {
+		recordAuditEvent(repo, generatedEvent(result))
+	}
+
+	return result.Message, nil
+}
+
+// printDescribedCommit shows a commit's current subject and its new message
+func printDescribedCommit(d describedCommit) {
+	subject, _, _ := strings.Cut(d.Original, "\n")
+	fmt.Printf("\n%s %s\n", d.Hash[:min(len(d.Hash), 7)], subject)
+	for _, line := range strings.Split(d.Message, "\n") {
+		fmt.Println("    " + line)
+	}
+}
+
+// filterRepoCallback renders the messages as the body of a git filter-repo
+// --commit-callback. Messages are Python string literals, which accept the
+// escapes strconv.Quote produces.
+func filterRepoCallback(described []describedCommit) string {
+	var sb strings.Builder
+	sb.WriteString("# Generated by comma describe; use with:\n")
+	sb.WriteString("#   git filter-repo --commit-callback \"$(cat this-file)\"\n")
+	sb.WriteString("messages = {\n")
+	for _, d := range described {
+		fmt.Fprintf(&sb, "    b%q: %s,\n", d.Hash, strconv.Quote(strings.TrimSpace(d.Message)+"\n"))
+	}
+	sb.WriteString("}\n")
+	sb.WriteString("if commit.original_id in messages:\n")
+	sb.WriteString("    commit.message = messages[commit.original_id].encode(\"utf-8\")\n")
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+// cmd/docs.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var (
+	docsDir string
+
+	docsCmd = &cobra.Command{
+		Use:   "docs man|markdown",
+		Short: "Generate man pages or a Markdown CLI reference",
+		Long: `Generate documentation from the command tree, one file per command.
+Packagers can ship the man pages with their distribution packages:
+
+  comma docs man --dir ./man/man1
+  comma docs markdown --dir ./docs/cli`,
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"man", "markdown"},
+		RunE:      runDocs,
+	}
+)
+
+func init() {
+	docsCmd.Flags().StringVar(&docsDir, "dir", "docs", "directory to write the documentation to")
+}
+
+func runDocs(cmd *cobra.Command, args []string) error {
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create docs directory: %w", err)
+	}
+
+	// Generated files should not change with the build date
+	rootCmd.DisableAutoGenTag = true
+
+	switch args[0] {
+	case "man":
+		header := &doc.GenManHeader{
+			Title:   "COMMA",
+			Section: "1",
+			Source:  "comma " + version,
+			Manual:  "Comma Manual",
+		}
+		if err := doc.GenManTree(rootCmd, header, docsDir); err != nil {
+			return fmt.Errorf("failed to generate man pages: %w", err)
+		}
+	case "markdown":
+		if err := doc.GenMarkdownTree(rootCmd, docsDir); err != nil {
+			return fmt.Errorf("failed to generate Markdown reference: %w", err)
+		}
+	}
+
+	fmt.Printf("✓ Documentation written to %s\n", docsDir)
+	return nil
+}
@@ -2,11 +2,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"time"
 
+	"github.com/jasonKoogler/comma/internal/audit"
 	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/conventional"
 	"github.com/jasonKoogler/comma/internal/team"
 	"github.com/spf13/cobra"
 )
@@ -18,9 +22,13 @@ var (
 	}
 
 	auditCmd = &cobra.Command{
-		Use:   "audit",
+		Use:   "audit [export-files...]",
 		Short: "View audit logs and usage reports",
-		RunE:  runAudit,
+		Long: `View audit logs and usage reports.
+Use --export-anonymized to write your usage without identifying details, and
+--team with a list of such exports to merge them into a team-wide report.
+When a member's exports are given more than once, only the newest counts.`,
+		RunE: runAudit,
 	}
 
 	teamCmd = &cobra.Command{
@@ -39,6 +47,19 @@ var (
 		Short: "Import team configuration from file",
 		RunE:  runTeamImport,
 	}
+
+	teamServerCmd = &cobra.Command{
+		Use:   "server",
+		Short: "Configure the central team configuration server",
+		RunE:  runTeamServer,
+	}
+
+	teamSyncCmd = &cobra.Command{
+		Use:   "sync [team]",
+		Short: "Fetch the latest team configuration from the team server",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runTeamSync,
+	}
 )
 
 func init() {
@@ -47,14 +68,20 @@ func init() {
 
 	teamCmd.AddCommand(teamCreateCmd)
 	teamCmd.AddCommand(teamImportCmd)
+	teamCmd.AddCommand(teamServerCmd)
+	teamCmd.AddCommand(teamSyncCmd)
 
 	// Audit command flags
 	auditCmd.Flags().Int("days", 30, "Number of days to include in report")
 	auditCmd.Flags().Bool("export", false, "Export report to CSV")
+	auditCmd.Flags().String("export-anonymized", "", "Write anonymized usage to a file for team aggregation")
+	auditCmd.Flags().Bool("team", false, "Merge anonymized usage exports into a team report")
 
 	// Team command flags
 	teamCreateCmd.Flags().String("name", "", "Team name")
 	teamCreateCmd.Flags().String("description", "", "Team description")
+	teamServerCmd.Flags().String("url", "", "Base HTTPS URL of the team server")
+	teamServerCmd.Flags().String("token", "", "Auth token for the team server (stored in the credential vault)")
 }
 
 func runAudit(cmd *cobra.Command, args []string) error {
@@ -63,25 +90,94 @@ func runAudit(cmd *cobra.Command, args []string) error {
 	}
 
 	days, _ := cmd.Flags().GetInt("days")
+	exportPath, _ := cmd.Flags().GetString("export-anonymized")
+	teamReport, _ := cmd.Flags().GetBool("team")
+
+	// Merge anonymized exports from team members into a team-wide report
+	if teamReport {
+		if len(args) == 0 {
+			return fmt.Errorf("at least one usage export file is required with --team")
+		}
+
+		var exports []audit.UsageExport
+		for _, filename := range args {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+
+			var export audit.UsageExport
+			if err := json.Unmarshal(data, &export); err != nil {
+				return fmt.Errorf("failed to parse usage export %s: %w", filename, err)
+			}
+			exports = append(exports, export)
+		}
+
+		report := audit.MergeUsageExports(exports)
+		fmt.Printf("Team Usage Report (%d members, last %d days):\n", report.Members, report.Days)
+		printUsageReport(report)
+		return nil
+	}
+
+	// Export this member's anonymized usage for aggregation by the team
+	if exportPath != "" {
+		auditLogger, err := appContext.AuditLogger()
+		if err != nil {
+			return err
+		}
+		export, err := auditLogger.ExportAnonymized(days)
+		if err != nil {
+			return fmt.Errorf("failed to export usage: %w", err)
+		}
+
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal usage export: %w", err)
+		}
+
+		if err := os.WriteFile(exportPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write usage export: %w", err)
+		}
+
+		fmt.Printf("✓ Anonymized usage exported to %s\n", exportPath)
+		return nil
+	}
 
 	// Generate usage report
-	report, err := appContext.AuditLogger.GetUsageReport(days)
+	auditLogger, err := appContext.AuditLogger()
+	if err != nil {
+		return err
+	}
+	report, err := auditLogger.GetUsageReport(days)
 	if err != nil {
 		return fmt.Errorf("failed to generate usage report: %w", err)
 	}
 
 	// Display report
 	fmt.Printf("Usage Report (Last %d days):\n", days)
-	fmt.Printf("Total Requests: %d\n", report["total_requests"])
-	fmt.Printf("Total Tokens: %d\n", report["total_tokens"])
-	fmt.Printf("Average Tokens per Request: %d\n", report["avg_tokens"])
+	printUsageReport(report)
+
+	return nil
+}
+
+// printUsageReport displays the statistics of a usage report
+func printUsageReport(report *audit.UsageReport) {
+	fmt.Printf("Total Requests: %d\n", report.TotalRequests)
+	fmt.Printf("Total Tokens: %d\n", report.TotalTokens)
+	fmt.Printf("Average Tokens per Request: %d\n", report.AvgTokens)
+	fmt.Printf("Estimated Cost: $%.2f\n", report.CostUSD)
+	fmt.Printf("Commits: %d\n", report.Commits)
+	fmt.Printf("Conventional Commit Rate: %.1f%%\n", report.ConventionalRate())
 
 	fmt.Println("\nUsage by Provider:")
-	for provider, count := range report["by_provider"].(map[string]int) {
-		fmt.Printf("  %s: %d requests\n", provider, count)
+	providers := make([]string, 0, len(report.ByProvider))
+	for provider := range report.ByProvider {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		fmt.Printf("  %s: %d requests\n", provider, report.ByProvider[provider])
 	}
-
-	return nil
 }
 
 func runTeamCreate(cmd *cobra.Command, args []string) error {
@@ -105,7 +201,7 @@ func runTeamCreate(cmd *cobra.Command, args []string) error {
 			{
 				Name:        "Conventional Format",
 				Description: "Follows conventional commits format",
-				Regex:       `^(feat|fix|docs|style|refactor|perf|test|build|ci|chore|revert)(\([a-zA-Z0-9_-]+\))?:\s.+`,
+				Regex:       conventional.TypePattern() + `\s.+`,
 				Required:    true,
 				ErrorMsg:    "Commit message must follow conventional format: type(scope): message",
 			},
@@ -125,7 +221,11 @@ func runTeamCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Save team configuration
-	if err := appContext.TeamManager.SaveTeam(name, &teamConfig); err != nil {
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return err
+	}
+	if err := teamMgr.SaveTeam(name, &teamConfig); err != nil {
 		return fmt.Errorf("failed to save team configuration: %w", err)
 	}
 
@@ -151,7 +251,11 @@ func runTeamImport(cmd *cobra.Command, args []string) error {
 	}
 
 	// Import team configuration
-	name, err := appContext.TeamManager.ImportFromJSON(data)
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return err
+	}
+	name, err := teamMgr.ImportFromJSON(data)
 	if err != nil {
 		return fmt.Errorf("failed to import team configuration: %w", err)
 	}
@@ -159,3 +263,142 @@ func runTeamImport(cmd *cobra.Command, args []string) error {
 	fmt.Printf("✓ Team '%s' imported successfully!\n", name)
 	return nil
 }
+
+func runTeamServer(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	if !cmd.Flags().Changed("url") && !cmd.Flags().Changed("token") {
+		serverURL := appContext.ConfigManager.GetString(config.TeamServerURLKey)
+		if serverURL == "" {
+			fmt.Println("No team server configured. Team configurations are read from local files.")
+		} else {
+			fmt.Printf("Team server: %s\n", serverURL)
+		}
+		return nil
+	}
+
+	if cmd.Flags().Changed("url") {
+		serverURL, _ := cmd.Flags().GetString("url")
+		if serverURL != "" {
+			// Validate the URL before saving it
+			if _, err := team.NewRemoteSource(serverURL, nil); err != nil {
+				return err
+			}
+		}
+		appContext.ConfigManager.Set(config.TeamServerURLKey, serverURL)
+		if err := appContext.ConfigManager.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+	}
+
+	if cmd.Flags().Changed("token") {
+		token, _ := cmd.Flags().GetString("token")
+		credMgr, err := appContext.CredentialMgr()
+		if err != nil {
+			return err
+		}
+		if err := credMgr.Store(team.TeamServerCredential, token); err != nil {
+			return fmt.Errorf("failed to store team server token: %w", err)
+		}
+	}
+
+	fmt.Println("✓ Team server configuration updated successfully!")
+	return nil
+}
+
+func runTeamSync(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	serverURL := appContext.ConfigManager.GetString(config.TeamServerURLKey)
+	if serverURL == "" {
+		return fmt.Errorf("no team server configured - run 'comma enterprise team server --url <url>' first")
+	}
+
+	name := appContext.ConfigManager.GetString(config.TeamNameKey)
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" {
+		return fmt.Errorf("team name is required")
+	}
+
+	credMgr, err := appContext.CredentialMgr()
+	if err != nil {
+		return err
+	}
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return err
+	}
+
+	token := func() string {
+		token, _ := credMgr.Retrieve(team.TeamServerCredential)
+		return token
+	}
+	if err := teamMgr.ConfigureRemote(serverURL, token); err != nil {
+		return err
+	}
+
+	if err := teamMgr.SyncTeam(name); err != nil {
+		return fmt.Errorf("failed to sync team configuration: %w", err)
+	}
+
+	fmt.Printf("✓ Team '%s' is up to date with %s\n", name, serverURL)
+	return nil
+}
+
+// loadActiveTeam loads the team selected by flags or configuration and
+// reports whether a team configuration is in effect
+func loadActiveTeam() bool {
+	if !useTeam && teamName == "" && !appContext.ConfigManager.GetBool(config.TeamEnabledKey) {
+		return false
+	}
+
+	name := teamName
+	if name == "" {
+		name = appContext.ConfigManager.GetString(config.TeamNameKey)
+	}
+
+	teamMgr, err := appContext.TeamManager()
+	if err == nil {
+		err = teamMgr.LoadTeam(name)
+	}
+	if err != nil {
+		fmt.Printf("Warning: Failed to load team configuration: %v\n", err)
+		return false
+	}
+
+	return true
+}
+
+// applyTeamSettings lets the loaded team override the user's system prompt
+// and decide how commits are linked to issues
+func applyTeamSettings() {
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return
+	}
+	teamConfig := teamMgr.Config()
+	if teamConfig.SystemPrompt != "" {
+		appContext.ConfigManager.Set(config.LLMSystemPromptKey, teamConfig.SystemPrompt)
+	}
+	if len(teamConfig.CommitTypes) > 0 {
+		conventional.SetTypes(teamConfig.CommitTypes)
+	}
+
+	linking := teamConfig.IssueLinking
+	if linking == nil {
+		return
+	}
+	appContext.ConfigManager.Set(config.IssuesEnabledKey, linking.Enabled)
+	if linking.Tracker != "" {
+		appContext.ConfigManager.Set(config.IssuesTrackerKey, linking.Tracker)
+	}
+	if linking.Keyword != "" {
+		appContext.ConfigManager.Set(config.IssuesKeywordKey, linking.Keyword)
+	}
+}
@@ -2,38 +2,67 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"unicode/utf8"
 
+	"github.com/fatih/color"
+	"github.com/jasonKoogler/comma/internal/analyze"
+	"github.com/jasonKoogler/comma/internal/audit"
 	"github.com/jasonKoogler/comma/internal/commit"
 	"github.com/jasonKoogler/comma/internal/config"
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
 	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/llm"
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/jasonKoogler/comma/internal/security"
+	"github.com/jasonKoogler/comma/internal/ui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	template   string
-	maxTokens  int
-	withDiff   bool
-	editPrompt bool
-	staged     bool
-	useTeam    bool
-	teamName   string
-	skipScan   bool
-	noCache    bool
+	template     string
+	maxTokens    int
+	withDiff     bool
+	editPrompt   bool
+	staged       bool
+	useTeam      bool
+	teamName     string
+	skipScan     bool
+	noCache      bool
+	assumeYes    bool
+	generateJSON bool
+	dryRun       bool
+	fromStdin    bool
+	issues       []string
+	strict       bool
+	untracked    bool
+	selectFiles  bool
+	commitType   string
 
 	generateCmd = &cobra.Command{
-		Use:     "generate",
+		Use:     "generate [paths...]",
 		Aliases: []string{"gen", "g"},
 		Short:   "Generate a commit message based on your changes",
-		RunE:    runGenerate,
+		Long: `Generate a commit message based on your staged changes.
+Given paths, or files picked with --select, only those files are staged,
+described and committed; anything else that is staged stays staged.`,
+		RunE: runGenerate,
 	}
 )
 
 func init() {
 	// Add flags
-	generateCmd.Flags().StringVarP(&template, "template", "t", "", "template for the commit message")
+	generateCmd.Flags().StringVarP(&template, "template", "t", "", "template for the commit message, or the name of a team template")
 	generateCmd.Flags().IntVarP(&maxTokens, "max-tokens", "m", 0, "maximum number of tokens for the response")
 	generateCmd.Flags().StringVar(&model, "model", "", "LLM model to use (e.g., gpt-4, claude-3-sonnet)")
 	generateCmd.Flags().BoolVarP(&withDiff, "with-diff", "d", false, "include detailed diff in the prompt")
@@ -43,12 +72,24 @@ func init() {
 	generateCmd.Flags().StringVar(&teamName, "team-name", "", "specify team name")
 	generateCmd.Flags().BoolVar(&skipScan, "skip-scan", false, "skip security scanning")
 	generateCmd.Flags().BoolVar(&noCache, "no-cache", false, "bypass commit cache")
+	generateCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "commit with the generated message without prompting")
+	generateCmd.Flags().BoolVar(&assumeYes, "no-input", false, "never prompt; same as --yes")
+	generateCmd.Flags().BoolVar(&fromStdin, "stdin", false, "read the diff from stdin instead of the repository and print the message")
+	generateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the prompt and estimated tokens without calling the LLM")
+	generateCmd.Flags().BoolVar(&untracked, "include-untracked", false, "add untracked files' contents to the prompt (they are not committed)")
+	generateCmd.Flags().BoolVar(&selectFiles, "select", false, "pick the changed files to commit from a list")
+	generateCmd.Flags().Bool("tree", false, "with --select, show the changed files as a directory tree")
+	generateCmd.Flags().BoolVar(&strict, "strict", false, "only produce messages semantic-release and commitizen can parse")
+	generateCmd.Flags().StringVar(&commitType, "type", "", "commit type to use instead of the detected one, e.g. feat or fix(auth)")
+	generateCmd.Flags().StringSliceVar(&issues, "issue", nil, "add a footer closing this issue, e.g. 42 or ENG-123 (repeatable)")
+	generateCmd.Flags().BoolVar(&generateJSON, "json", false, "print the result as JSON instead of prompting (commits only with --yes)")
 
 	// Bind flags to viper for temporary overrides
 	viper.BindPFlag(config.TemplateKey, generateCmd.Flags().Lookup("template"))
 	viper.BindPFlag(config.LLMModelKey, generateCmd.Flags().Lookup("model"))
 	viper.BindPFlag(config.LLMMaxTokensKey, generateCmd.Flags().Lookup("max-tokens"))
 	viper.BindPFlag(config.IncludeDiffKey, generateCmd.Flags().Lookup("with-diff"))
+	viper.BindPFlag(config.UIFileTreeKey, generateCmd.Flags().Lookup("tree"))
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
@@ -56,6 +97,21 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration manager not initialized")
 	}
 
+	if fromStdin && assumeYes {
+		return fmt.Errorf("--stdin only prints the message; it cannot be combined with --yes")
+	}
+	if fromStdin && (len(args) > 0 || selectFiles) {
+		return fmt.Errorf("--stdin reads the whole diff; it cannot be combined with paths or --select")
+	}
+
+	opts := commit.GenerateOptions{NoCache: noCache, Issues: issues}
+	if cmd.Flags().Changed("type") {
+		var err error
+		if opts.Type, opts.Scope, err = parseCommitType(commitType); err != nil {
+			return err
+		}
+	}
+
 	// Apply temporary overrides from flags to the config manager
 	// These won't be saved to disk
 	if cmd.Flags().Changed("template") {
@@ -70,74 +126,389 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("with-diff") {
 		appContext.ConfigManager.Set(config.IncludeDiffKey, withDiff)
 	}
+	if strict {
+		appContext.ConfigManager.Set(config.ConventionalStrictKey, true)
+	}
+	if cmd.Flags().Changed("include-untracked") {
+		git.SetIncludeUntracked(untracked)
+	}
+
+	// Load the team first so its policies apply to everything below
+	teamLoaded := loadActiveTeam()
+
+	// --template may also name one of the team's templates
+	if teamLoaded && cmd.Flags().Changed("template") {
+		teamMgr, err := appContext.TeamManager()
+		if err != nil {
+			return err
+		}
+		if content, err := teamMgr.GetTemplate(template); err == nil {
+			appContext.ConfigManager.Set(config.TemplateKey, content)
+		}
+	}
+
+	if teamLoaded {
+		applyTeamSettings()
+	}
 
-	// Validate configuration
-	if err := validateConfig(); err != nil {
+	// Validate configuration; a dry run needs no API key
+	if dryRun {
+		if llm.ActiveProvider(appContext) == "" {
+			return fmt.Errorf("LLM provider is not set - run 'comma setup' first")
+		}
+	} else if err := validateConfig(); err != nil {
 		// Make a specific suggestion for setup
-		fmt.Println("Configuration error:", err)
-		fmt.Println("\nSuggestion: Run 'comma setup' to configure your LLM provider and API key.")
-		return nil // Return nil to avoid showing the error again
+		return fmt.Errorf("%w\nRun 'comma setup' to configure your LLM provider and API key", err)
+	}
+
+	// Without a terminal there is nobody to confirm the message, so fail
+	// before spending an LLM request
+	if !assumeYes && !generateJSON && !dryRun && !fromStdin && !stdinIsTerminal() {
+		return fmt.Errorf("stdin is not a terminal - pass --yes to commit the generated message without prompting")
 	}
 
 	// Check if the model flag was set
+	// Progress output is left out of machine-readable and quiet runs
+	chatty := !quiet && !generateJSON && !fromStdin
+
 	if model != "" {
-		fmt.Printf("Using specified model: %s\n", model)
+		appContext.Logger.Info("Using specified model: %s", model)
 	}
 
-	// Get git repository info
-	repo, err := git.NewRepository(".")
-	if err != nil {
-		return fmt.Errorf("failed to open git repository: %w", err)
+	// Read the changes from the repository, or from a diff on stdin
+	var repo *git.Repository
+	var source git.ChangeSource
+	if fromStdin {
+		patch, err := readStdinPatch()
+		if err != nil {
+			return err
+		}
+		source = patch
+	} else {
+		var err error
+		repo, err = git.NewRepository(".")
+		if err != nil {
+			return fmt.Errorf("failed to open git repository: %w", err)
+		}
+
+		// Stage and describe only the chosen files
+		paths := args
+		if selectFiles {
+			if paths, err = pickFiles(repo); err != nil {
+				return err
+			}
+			if len(paths) == 0 {
+				fmt.Println("No files selected.")
+				return nil
+			}
+		}
+		if len(paths) > 0 {
+			if err := repo.Stage(paths...); err != nil {
+				return err
+			}
+			repo = repo.Only(paths...)
+		}
+		source = repo
 	}
 
 	// Check for staged changes
-	changes, err := repo.GetStagedChanges()
+	changes, err := source.GetStagedChanges()
 	if err != nil {
 		return fmt.Errorf("failed to get staged changes: %w", err)
 	}
 
 	if changes == "" {
-		fmt.Println("No staged changes found. Stage changes with 'git add' before generating a commit message.")
-		return nil
+		if fromStdin {
+			return fmt.Errorf("%w: no diff found on stdin", apperrors.ErrGitNoChanges)
+		}
+		return fmt.Errorf("%w: stage changes with 'git add' before generating a commit message", apperrors.ErrGitNoChanges)
 	}
 
-	fmt.Println("Generating commit message...")
+	// Say what the commit leaves out, since it is easy to forget a file
+	if chatty && repo != nil && !dryRun {
+		printNotCommitted(repo)
+	}
+
+	// Scan for secrets before anything leaves the machine
+	findings, err := enforceSecurityScan(source, changes)
+	if err != nil {
+		if generateJSON {
+			printJSON(&generateOutput{Provider: llm.ActiveProvider(appContext), Findings: findings, TraceID: logging.TraceID()})
+		}
+		return err
+	}
 
 	// Get the commit service from the app context
-	commitService, ok := appContext.CommitService.(*commit.Service)
-	if !ok {
-		return fmt.Errorf("commit service not initialized properly")
+	commitService, err := getCommitService()
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return printDryRun(source, commitService, opts, findings)
+	}
+
+	// Show the detected type and let the user choose another before
+	// generating, rather than applying it only when it is confident
+	if opts.Type == "" && chatty && !assumeYes && appContext.ConfigManager.GetBool(config.AnalysisSmartDetectionKey) {
+		suggestions := commitService.Classify(source, changes)
+		printClassification(suggestions)
+		if opts.Type, opts.Scope, err = chooseCommitType(suggestions, suggestedDefault(suggestions)); err != nil {
+			return err
+		}
 	}
 
 	// Use the commit service to generate a message
-	message, err := commitService.GenerateCommitMessage(repo)
+	progress := newProgress(chatty)
+	progress.Start("Generating commit message")
+	ctx, stop := interruptible(cmd.Context())
+	result, err := commitService.Generate(ctx, source, opts)
+	stop()
+	progress.Stop()
 	if err != nil {
+		recordAuditEvent(source, audit.Event{Action: audit.ActionGenerate, Status: "error", Error: err.Error()})
 		return fmt.Errorf("failed to generate commit message: %w", err)
 	}
+	if result.Cached {
+		recordAuditEvent(source, audit.Event{Action: audit.ActionGenerate, Status: "cached"})
+	} else {
+		recordAuditEvent(source, generatedEvent(result))
+	}
 
-	fmt.Println("\nGenerated Commit Message:")
-	fmt.Println("-------------------")
-	fmt.Println(message)
-	fmt.Println("-------------------")
+	if generateJSON {
+		return writeGenerateJSON(repo, commitService, result, changes, findings, teamLoaded)
+	}
 
-	// Ask if the user wants to use this message
-	useMessage, err := promptYesNo("Use this commit message?")
-	if err != nil {
+	// Diffs from stdin have no repository to commit to
+	if fromStdin {
+		fmt.Println(result.Message)
+		return nil
+	}
+
+	message := result.Message
+	if assumeYes {
+		if chatty {
+			printGeneratedMessage(result)
+		}
+	} else if message, err = reviewMessage(cmd.Context(), repo, commitService, result, opts); err != nil {
 		return err
 	}
 
-	if useMessage {
-		if err := repo.Commit(message); err != nil {
-			return fmt.Errorf("failed to commit: %w", err)
+	if message != "" {
+		var check func(string) error
+		if teamLoaded {
+			check = approvalCheck(repo)
 		}
-		fmt.Println("✓ Changes committed successfully!")
-	} else {
+
+		if _, err := commitWithHooks(repo, commitService, message, check); err != nil {
+			var pending *pendingApproval
+			if errors.As(err, &pending) {
+				return requestApproval(pending.id, pending.message, repo.GetUserIdentity())
+			}
+			return err
+		}
+		if chatty {
+			fmt.Println("✓ Changes committed successfully!")
+		}
+	} else if chatty {
 		fmt.Println("Commit aborted.")
 	}
 
 	return nil
 }
 
+// getCommitService returns the commit service built by main
+func getCommitService() (*commit.Service, error) {
+	service, err := appContext.CommitService()
+	if err != nil {
+		return nil, err
+	}
+
+	commitService, ok := service.(*commit.Service)
+	if !ok {
+		return nil, fmt.Errorf("commit service not initialized properly")
+	}
+	return commitService, nil
+}
+
+// commitWithHooks commits the message through the commit service and
+// records it, returning the message that was committed. check is passed on
+// to the service. Post-commit hook failures are only reported, since the
+// commit already exists.
+func commitWithHooks(repo *git.Repository, commitService *commit.Service, message string, check func(string) error) (string, error) {
+	message, err := commitService.Commit(repo, message, check)
+	if err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+	recordAuditEvent(repo, audit.Event{
+		Action:       audit.ActionCommit,
+		Status:       "success",
+		Conventional: analyze.IsConventional(message),
+	})
+
+	if err := commitService.AfterCommit(repo, message); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+	return message, nil
+}
+
+// generateOutput is the result of 'comma generate --json'
+type generateOutput struct {
+	Message    string             `json:"message"`
+	Subject    string             `json:"subject"`
+	Body       string             `json:"body,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Scope      string             `json:"scope,omitempty"`
+	Confidence float64            `json:"confidence,omitempty"`
+	Provider   string             `json:"provider"`
+	Model      string             `json:"model,omitempty"`
+	Cached     bool               `json:"cached"`
+	Usage      *llm.Usage         `json:"usage,omitempty"`
+	Findings   []security.Finding `json:"findings"`
+	Committed  bool               `json:"committed"`
+	CommitHash string             `json:"commit_hash,omitempty"`
+	ApprovalID string             `json:"approval_id,omitempty"`
+	TraceID    string             `json:"trace_id,omitempty"`
+}
+
+// writeGenerateJSON prints a generation result as JSON, committing it
+// first when --yes was given
+func writeGenerateJSON(repo *git.Repository, commitService *commit.Service, result *commit.Result,
+	changes string, findings []security.Finding, teamLoaded bool) error {
+	if findings == nil {
+		findings = []security.Finding{}
+	}
+
+	out := &generateOutput{
+		Message:    result.Message,
+		Type:       result.Type,
+		Scope:      result.Scope,
+		Confidence: result.Confidence,
+		Provider:   llm.ActiveProvider(appContext),
+		Model:      appContext.ConfigManager.GetString(config.LLMModelKey),
+		Cached:     result.Cached,
+		Usage:      result.Usage,
+		Findings:   findings,
+		TraceID:    logging.TraceID(),
+	}
+	if result.Provider != "" {
+		out.Provider, out.Model = result.Provider, result.Model
+	}
+	if result.Cached && result.CacheEntry != nil {
+		out.Provider = result.CacheEntry.Provider
+	}
+
+	if assumeYes && repo != nil {
+		var check func(string) error
+		if teamLoaded {
+			check = approvalCheck(repo)
+		}
+
+		message, err := commitWithHooks(repo, commitService, result.Message, check)
+		var pending *pendingApproval
+		switch {
+		case errors.As(err, &pending):
+			teamMgr, err := appContext.TeamManager()
+			if err != nil {
+				return err
+			}
+			approval, err := teamMgr.RequestApproval(pending.id, pending.message, repo.GetUserIdentity())
+			if err != nil {
+				return fmt.Errorf("failed to record approval request: %w", err)
+			}
+			out.Message = pending.message
+			out.ApprovalID = approval.ID
+		case err != nil:
+			return err
+		default:
+			out.Message = message
+			out.Committed = true
+			out.CommitHash, _ = repo.Head()
+		}
+	}
+
+	out.Subject, out.Body = commit.SplitMessage(out.Message)
+	return printJSON(out)
+}
+
+// readStdinPatch reads a unified diff from stdin. It is named after the
+// repository when run inside one, so hooks and the audit log can tell
+// where it came from.
+func readStdinPatch() (*git.Patch, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diff from stdin: %w", err)
+	}
+
+	name := "stdin"
+	if repo, err := git.NewRepository("."); err == nil {
+		name = repo.Name()
+	}
+	return git.NewPatch(name, string(data)), nil
+}
+
+// dryRunOutput is the result of 'comma generate --dry-run --json'
+type dryRunOutput struct {
+	System          string             `json:"system"`
+	Prompt          string             `json:"prompt"`
+	Type            string             `json:"type,omitempty"`
+	Scope           string             `json:"scope,omitempty"`
+	Confidence      float64            `json:"confidence,omitempty"`
+	Provider        string             `json:"provider"`
+	Model           string             `json:"model,omitempty"`
+	EstimatedTokens int                `json:"estimated_tokens"`
+	MaxTokens       int                `json:"max_tokens"`
+	Findings        []security.Finding `json:"findings"`
+}
+
+// printDryRun prepares the prompt exactly as generation would and prints
+// it with its estimated size instead of sending it
+func printDryRun(repo git.ChangeSource, commitService *commit.Service, opts commit.GenerateOptions, findings []security.Finding) error {
+	prompt, err := commitService.PreparePrompt(repo, opts)
+	if err != nil {
+		return fmt.Errorf("failed to prepare prompt: %w", err)
+	}
+
+	provider := llm.ActiveProvider(appContext)
+	model := appContext.ConfigManager.GetString(config.LLMModelKey)
+
+	if generateJSON {
+		if findings == nil {
+			findings = []security.Finding{}
+		}
+		return printJSON(&dryRunOutput{
+			System:          llm.SystemPrompt(appContext),
+			Prompt:          prompt.Text,
+			Type:            prompt.Type,
+			Scope:           prompt.Scope,
+			Confidence:      prompt.Confidence,
+			Provider:        provider,
+			Model:           model,
+			EstimatedTokens: prompt.EstimatedTokens,
+			MaxTokens:       prompt.MaxTokens,
+			Findings:        findings,
+		})
+	}
+
+	fmt.Printf("\nPrompt for %s (%s):\n", provider, model)
+	fmt.Println("-------------------")
+	fmt.Printf("System: %s\n\n", llm.SystemPrompt(appContext))
+	fmt.Println(prompt.Text)
+	fmt.Println("-------------------")
+	if opts.Type != "" {
+		fmt.Printf("Chosen type: %s\n", typeLabel(opts.Type, opts.Scope))
+	} else if prompt.Type != "" {
+		fmt.Printf("Detected type: %s", prompt.Type)
+		if prompt.Scope != "" {
+			fmt.Printf("(%s)", prompt.Scope)
+		}
+		fmt.Printf(" (confidence %.0f%%)\n", prompt.Confidence*100)
+	}
+	fmt.Printf("Estimated tokens: ~%d prompt + up to %d completion\n", prompt.EstimatedTokens, prompt.MaxTokens)
+	fmt.Println("Dry run: nothing was sent to the LLM.")
+	return nil
+}
+
 // countLines counts lines in text that start with a prefix
 // func countLines(text, prefix string) int {
 // 	count := 0
@@ -150,30 +521,75 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 // 	return count
 // }
 
-// Helper function to prompt for yes/no
-func promptYesNo(question string) (bool, error) {
-	var response string
-	fmt.Printf("%s (y/n): ", question)
-	_, err := fmt.Scanln(&response)
+// interruptible returns a context that ctrl+c cancels, aborting the LLM
+// request in flight. Call stop as soon as the request returns so ctrl+c
+// exits as usual again.
+func interruptible(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+// generatedEvent is the audit event for a message the LLM wrote
+func generatedEvent(result *commit.Result) audit.Event {
+	event := audit.Event{Action: audit.ActionGenerate, Status: "success", Provider: result.Provider}
+	if result.Usage != nil {
+		event.TokensUsed = result.Usage.TotalTokens()
+		event.PromptTokens = result.Usage.PromptTokens
+		event.OutputTokens = result.Usage.CompletionTokens
+		event.CostUSD = result.Usage.CostUSD
+	}
+	return event
+}
+
+// recordAuditEvent fills in the provider and repository and writes an audit event.
+// Audit failures are reported but never interrupt the command.
+func recordAuditEvent(repo git.ChangeSource, event audit.Event) {
+	// Skip building the audit logger when there is nothing to record
+	if !appContext.ConfigManager.GetBool(config.SecurityAuditLoggingKey) {
+		return
+	}
+
+	if event.Provider == "" {
+		event.Provider = llm.ActiveProvider(appContext)
+	}
+	if event.RepoName == "" && repo != nil {
+		event.RepoName = repo.Name()
+	}
+	if event.TraceID == "" {
+		event.TraceID = logging.TraceID()
+	}
+
+	auditLogger, err := appContext.AuditLogger()
+	if err == nil {
+		err = auditLogger.LogEvent(event)
+	}
 	if err != nil {
-		return false, err
+		fmt.Printf("Warning: Failed to write audit log: %v\n", err)
 	}
-	return strings.ToLower(response) == "y" || strings.ToLower(response) == "yes", nil
 }
 
 // validateConfig checks if the configuration is valid
 func validateConfig() error {
-	provider := appContext.ConfigManager.GetString(config.LLMProviderKey)
+	provider := llm.ActiveProvider(appContext)
 	if provider == "" {
 		return fmt.Errorf("LLM provider is not set - run 'comma setup' first")
 	}
 
-	if provider != "openai" && provider != "anthropic" && provider != "local" && provider != "none" {
+	pluginProvider, isPlugin := llm.LookupProvider(provider)
+	if !llm.IsBuiltinProvider(provider) && provider != "none" && !isPlugin {
 		return fmt.Errorf("unsupported LLM provider: %s", provider)
 	}
 
-	// Skip API key check for local models
-	if provider == "local" || provider == "none" {
+	// Refuse providers the loaded team does not allow
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return err
+	}
+	if err := teamMgr.CheckProvider(provider); err != nil {
+		return err
+	}
+
+	// Skip API key check for local models and plugin providers without keys
+	if provider == "local" || provider == "local-server" || provider == "none" || (isPlugin && !pluginProvider.Info().RequiresAPIKey) {
 		return nil
 	}
 
@@ -185,5 +601,179 @@ func validateConfig() error {
 			provider, envKey)
 	}
 
-	return nil
+	return checkKeyAge(provider, apiKey)
+}
+
+// fileState describes whether a file's changes are staged, unstaged or
+// both, or that the file is untracked, as two colored columns
+func fileState(change git.FileChange) string {
+	// Padding goes outside the color so it is measured without escapes
+	cell := func(attr color.Attribute, text string) string {
+		return color.New(attr).Sprint(text) + strings.Repeat(" ", max(20-len(text), 0))
+	}
+
+	first, second := strings.Repeat(" ", 20), strings.Repeat(" ", 20)
+	switch {
+	case change.Untracked():
+		first = cell(color.FgRed, "untracked")
+	case change.Staged != "":
+		first = cell(color.FgGreen, "staged "+change.Staged)
+	}
+	if change.Unstaged != "" {
+		second = cell(color.FgYellow, "unstaged "+change.Unstaged)
+	}
+	return first + " " + second
+}
+
+// printNotCommitted lists the changed files whose changes, or some of them,
+// are not staged and so will not be in the commit
+func printNotCommitted(repo *git.Repository) {
+	changes, err := repo.GetChangedFiles()
+	if err != nil {
+		return
+	}
+
+	var unstaged, untracked []string
+	for _, change := range changes {
+		switch {
+		case change.Untracked():
+			untracked = append(untracked, change.Path)
+		case change.Unstaged != "":
+			unstaged = append(unstaged, change.Path)
+		}
+	}
+	if len(unstaged) > 0 {
+		fmt.Printf("Not staged, so not committed: %s\n", color.YellowString(strings.Join(unstaged, ", ")))
+	}
+	if len(untracked) > 0 {
+		fmt.Printf("Untracked, so not committed: %s\n", color.RedString(strings.Join(untracked, ", ")))
+	}
+}
+
+// pickFiles lets the user choose among the changed files and returns them
+// as pathspecs. Both sides of a rename are returned, so it is committed whole.
+func pickFiles(repo *git.Repository) ([]string, error) {
+	changes, err := repo.GetChangedFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("%w: there are no changed files to choose from", apperrors.ErrGitNoChanges)
+	}
+
+	fmt.Println("Picked files are committed with all their changes, staged or not.")
+	var picked []int
+	if appContext.ConfigManager.GetBool(config.UIFileTreeKey) {
+		picked, err = pickFromTree(changes)
+	} else {
+		items := make([]string, len(changes))
+		for i, change := range changes {
+			items[i] = fileState(change) + " " + change.Path
+		}
+		picked, err = promptMultiSelect("Files to commit (e.g. 1 3-5): ", items)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, i := range picked {
+		// Status paths are relative to the top of the work tree
+		for _, path := range strings.Split(changes[i].Path, " -> ") {
+			paths = append(paths, ":(top,literal)"+path)
+		}
+	}
+	return paths, nil
+}
+
+// treeCollapseAbove is how many files a folder may hold before the tree
+// picker shows it collapsed
+const treeCollapseAbove = 10
+
+// pickFromTree lets the user choose among the changed files in a directory
+// tree and returns their indexes. Picking a folder picks every file under
+// it; +N and -N expand and collapse folder N.
+func pickFromTree(changes []git.FileChange) ([]int, error) {
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+		if _, renamed, ok := strings.Cut(change.Path, " -> "); ok {
+			paths[i] = renamed
+		}
+	}
+
+	tree := ui.NewFileTree(paths)
+	tree.CollapseAbove(treeCollapseAbove)
+
+	for {
+		shown := printTree(tree, changes)
+		answer, err := promptLine("Files or folders to commit (e.g. 1 3-5; +2 expands and -2 collapses folder 2): ")
+		if err != nil {
+			return nil, err
+		}
+
+		answer = strings.TrimSpace(answer)
+		if strings.HasPrefix(answer, "+") || strings.HasPrefix(answer, "-") {
+			n, err := strconv.Atoi(answer[1:])
+			if err != nil || n < 1 || n > len(shown) || !shown[n-1].IsFolder() {
+				fmt.Printf("⚠️  %q is not a folder number\n", answer[1:])
+				continue
+			}
+			shown[n-1].Collapsed = answer[0] == '-'
+			continue
+		}
+
+		picked, err := parseSelection(answer, len(shown))
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			continue
+		}
+		seen := make(map[int]bool)
+		var files []int
+		for _, i := range picked {
+			for _, file := range shown[i].FileIndexes() {
+				if !seen[file] {
+					seen[file] = true
+					files = append(files, file)
+				}
+			}
+		}
+		sort.Ints(files)
+		return files, nil
+	}
+}
+
+// printTree prints the numbered entries of the tree that are not inside a
+// collapsed folder, with each folder's file count and each file's state,
+// and returns them in order
+func printTree(tree *ui.TreeNode, changes []git.FileChange) []*ui.TreeNode {
+	var shown []*ui.TreeNode
+	var labels []string
+	width := 0
+	tree.Walk(func(node *ui.TreeNode, depth int) {
+		label := strings.Repeat("  ", depth)
+		switch {
+		case !node.IsFolder():
+			label += "  " + node.Name
+		case node.Collapsed:
+			label += "▸ " + node.Name + "/"
+		default:
+			label += "▾ " + node.Name + "/"
+		}
+		shown = append(shown, node)
+		labels = append(labels, label)
+		width = max(width, utf8.RuneCountInString(label))
+	})
+
+	for i, node := range shown {
+		// Padding goes outside the color so it is measured without escapes
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(labels[i])+2)
+		if node.IsFolder() {
+			fmt.Printf("%3d) %s%s%s\n", i+1, color.New(color.Bold).Sprint(labels[i]), padding,
+				color.New(color.Faint).Sprintf("%d changed", node.Files))
+			continue
+		}
+		fmt.Printf("%3d) %s%s%s\n", i+1, labels[i], padding, fileState(changes[node.Index]))
+	}
+	return shown
 }
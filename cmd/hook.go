@@ -0,0 +1,273 @@
+// cmd/hook.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/config"
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hookCommitMsg bool
+	hookTeamName  string
+
+	hookCmd = &cobra.Command{
+		Use:   "hook",
+		Short: "Manage Comma git hooks",
+	}
+
+	hookInstallCmd = &cobra.Command{
+		Use:   "install",
+		Short: "Install Comma git hooks in the current repository",
+		Long: `Install Comma git hooks in the current repository.
+By default the prepare-commit-msg hook is installed, which generates a commit message.
+Use --commit-msg to install a commit-msg hook that validates the final message
+against the loaded team's convention checks and blocks commits that fail them.`,
+		RunE: runHookInstall,
+	}
+
+	hookPreCommitRunCmd = &cobra.Command{
+		Use:   "pre-commit-run scan|lint [files...]",
+		Short: "Run Comma checks as hooks of the pre-commit framework",
+		Long: `Run Comma's checks from the pre-commit framework (https://pre-commit.com).
+The scan check looks for secrets in the staged changes of the files
+pre-commit passes, or of every staged file when none are given. The lint
+check runs in the commit-msg stage and validates the message file against
+the team's convention checks. Both fail the hook instead of prompting.
+
+  repos:
+    - repo: https://github.com/jasonKoogler/comma
+      rev: v1.0.0
+      hooks:
+        - id: comma-scan
+        - id: comma-lint`,
+		Args:      cobra.MatchAll(cobra.MinimumNArgs(1), validPreCommitCheck),
+		ValidArgs: []string{"scan", "lint"},
+		RunE:      runHookPreCommitRun,
+	}
+
+	hookCommitMsgCmd = &cobra.Command{
+		Use:    "commit-msg <message-file>",
+		Short:  "Validate a commit message file against team conventions",
+		Args:   cobra.ExactArgs(1),
+		Hidden: true,
+		RunE:   runHookCommitMsg,
+	}
+)
+
+// commitMsgHookContent is the script installed as the commit-msg hook
+const commitMsgHookContent = `#!/bin/sh
+# Comma commit-msg hook
+# Generated by comma hook install --commit-msg
+
+# Validate the final commit message against team conventions
+comma hook commit-msg "$1"
+`
+
+func init() {
+	hookCmd.AddCommand(hookInstallCmd)
+	hookCmd.AddCommand(hookCommitMsgCmd)
+	hookCmd.AddCommand(hookPreCommitRunCmd)
+
+	hookInstallCmd.Flags().BoolVar(&hookCommitMsg, "commit-msg", false, "install a commit-msg hook that enforces team conventions")
+	hookCommitMsgCmd.Flags().StringVar(&hookTeamName, "team-name", "", "team whose conventions should be enforced")
+	hookPreCommitRunCmd.Flags().StringVar(&hookTeamName, "team-name", "", "team whose conventions should be enforced")
+}
+
+func runHookInstall(cmd *cobra.Command, args []string) error {
+	if !hookCommitMsg {
+		return runInstall(cmd, args)
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	installed, err := writeHook(repo, "commit-msg", commitMsgHookContent)
+	if err != nil {
+		return err
+	}
+
+	if installed {
+		fmt.Println("✓ commit-msg hook installed successfully!")
+		fmt.Println("Commits will now be validated against your team's convention checks.")
+	}
+	return nil
+}
+
+func runHookCommitMsg(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read commit message file: %w", err)
+	}
+
+	message := stripCommitComments(string(data))
+	if message == "" {
+		// Let git handle empty messages with its own error
+		return nil
+	}
+
+	name := hookTeamName
+	if name == "" {
+		name = appContext.ConfigManager.GetString(config.TeamNameKey)
+	}
+
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return err
+	}
+	if err := teamMgr.LoadTeam(name); err != nil {
+		// Nothing to enforce without a team configuration
+		return nil
+	}
+
+	valid, problems := teamMgr.ValidateCommitMessage(message)
+	if !valid {
+		fmt.Fprintf(os.Stderr, "✗ Commit message does not follow %s team conventions:\n", teamMgr.CurrentTeam())
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		fmt.Fprintln(os.Stderr, "\nEdit your message and try again, or bypass with 'git commit --no-verify'.")
+
+		return fmt.Errorf("%w: commit message rejected", apperrors.ErrLintFailed)
+	}
+
+	if !teamMgr.RequiresApproval() {
+		return nil
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	id, err := unapprovedID(repo, message)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return nil
+	}
+
+	if err := requestApproval(id, message, repo.GetUserIdentity()); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("commit is awaiting team approval")
+}
+
+// validPreCommitCheck checks that the first argument names a check; the rest
+// are file names
+func validPreCommitCheck(cmd *cobra.Command, args []string) error {
+	if args[0] != "scan" && args[0] != "lint" {
+		return fmt.Errorf("unknown check %q (use scan or lint)", args[0])
+	}
+	return nil
+}
+
+func runHookPreCommitRun(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	check, files := args[0], args[1:]
+	if check == "lint" {
+		if len(files) != 1 {
+			return fmt.Errorf("lint takes the commit message file - add 'stages: [commit-msg]' to the hook")
+		}
+		return runHookCommitMsg(cmd, files)
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	if err := applyAllowlist(repo); err != nil {
+		return err
+	}
+
+	diff, err := repo.StagedDiffReader(files...)
+	if err != nil {
+		return err
+	}
+	findings, blocking, err := scanDiff(diff)
+	if closeErr := diff.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	printFindings(findings)
+
+	if blocking > 0 {
+		return fmt.Errorf("%w: %d finding(s) at %s severity or above", apperrors.ErrSensitiveDataFound,
+			blocking, blockSeverity())
+	}
+	return nil
+}
+
+// writeHook writes a hook script into the repository's hooks directory,
+// asking before overwriting an existing hook. It reports whether the hook was written.
+func writeHook(repo *git.Repository, name, content string) (bool, error) {
+	gitDir, err := repo.GetGitDir()
+	if err != nil {
+		return false, fmt.Errorf("failed to get git directory: %w", err)
+	}
+
+	hooksDir := filepath.Join(gitDir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	hookPath := filepath.Join(hooksDir, name)
+
+	// Check if hook already exists
+	if _, err := os.Stat(hookPath); err == nil {
+		overwrite, err := promptYesNo(fmt.Sprintf("%s hook already exists. Overwrite?", name))
+		if err != nil {
+			return false, err
+		}
+		if !overwrite {
+			fmt.Println("Hook installation aborted.")
+			return false, nil
+		}
+	}
+
+	if err := os.WriteFile(hookPath, []byte(content), 0755); err != nil {
+		return false, fmt.Errorf("failed to write hook file: %w", err)
+	}
+
+	return true, nil
+}
+
+// stripCommitComments removes git comment lines and the verbose-mode diff
+// from a commit message file, returning the trimmed message
+func stripCommitComments(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		// Everything below the scissors line is the diff shown by 'git commit -v'
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -2,8 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/jasonKoogler/comma/internal/git"
 	"github.com/spf13/cobra"
@@ -21,25 +19,6 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to open git repository: %w", err)
 	}
 
-	gitDir, err := repo.GetGitDir()
-	if err != nil {
-		return fmt.Errorf("failed to get git directory: %w", err)
-	}
-
-	hookPath := filepath.Join(gitDir, "hooks", "prepare-commit-msg")
-
-	// Check if hook already exists
-	if _, err := os.Stat(hookPath); err == nil {
-		overwrite, err := promptYesNo("Hook already exists. Overwrite?")
-		if err != nil {
-			return err
-		}
-		if !overwrite {
-			fmt.Println("Hook installation aborted.")
-			return nil
-		}
-	}
-
 	// Create hook script
 	hookContent := `#!/bin/sh
 # Comma prepare-commit-msg hook
@@ -63,9 +42,12 @@ fi
 echo "$COMMIT_MSG" > "$1"
 `
 
-	// Write hook file
-	if err := os.WriteFile(hookPath, []byte(hookContent), 0755); err != nil {
-		return fmt.Errorf("failed to write hook file: %w", err)
+	installed, err := writeHook(repo, "prepare-commit-msg", hookContent)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		return nil
 	}
 
 	fmt.Println("✓ Hook installed successfully!")
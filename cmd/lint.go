@@ -0,0 +1,129 @@
+// cmd/lint.go
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jasonKoogler/comma/internal/config"
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/team"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintRange    string
+	lintTeamName string
+	lintFormat   string
+
+	lintCmd = &cobra.Command{
+		Use:   "lint",
+		Short: "Check historical commits against team conventions",
+		Long: `Check historical commits against team conventions.
+Runs the loaded team's convention checks over every non-merge commit in a
+revision range and reports compliance grouped by author, which is useful for
+tracking adoption of the conventions over time. Use --verbose to list the
+failing commits, or --format json for dashboards.
+
+Examples:
+  comma lint --range v1.0.0..HEAD
+  comma lint --range main~100..main --format json`,
+		Args: cobra.NoArgs,
+		RunE: runLint,
+	}
+)
+
+func init() {
+	lintCmd.Flags().StringVar(&lintRange, "range", "HEAD", "revision range of commits to check")
+	lintCmd.Flags().StringVar(&lintTeamName, "team-name", "", "team whose conventions should be checked")
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "output format (text, json)")
+	lintCmd.Flags().Bool("json", false, "print the report as JSON; same as --format json")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		lintFormat = "json"
+	}
+	if lintFormat != "text" && lintFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", lintFormat)
+	}
+
+	name := lintTeamName
+	if name == "" {
+		name = appContext.ConfigManager.GetString(config.TeamNameKey)
+	}
+	teamMgr, err := appContext.TeamManager()
+	if err != nil {
+		return err
+	}
+	if err := teamMgr.LoadTeam(name); err != nil {
+		return fmt.Errorf("failed to load team: %w", err)
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	commits, err := repo.GetCommitRange(lintRange)
+	if err != nil {
+		return err
+	}
+
+	lintCommits := make([]team.LintCommit, len(commits))
+	for i, commit := range commits {
+		lintCommits[i] = team.LintCommit{Hash: commit.Hash, Author: commit.Author, Message: commit.Message}
+	}
+	report := teamMgr.LintCommits(lintCommits)
+
+	if lintFormat == "json" {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+		return lintResult(report)
+	}
+
+	if quiet {
+		return lintResult(report)
+	}
+
+	if report.Commits == 0 {
+		fmt.Printf("No commits found in %s.\n", lintRange)
+		return nil
+	}
+
+	fmt.Printf("Convention compliance for team %s (%s):\n", report.Team, lintRange)
+	fmt.Printf("  %d of %d commits compliant (%.1f%%)\n", report.Compliant, report.Commits, report.Percent())
+
+	fmt.Println("\nBy author:")
+	for _, author := range report.Authors {
+		fmt.Printf("  %-30s %4d/%-4d %5.1f%%\n", author.Author, author.Compliant, author.Commits, author.Percent())
+
+		if GetVerbose() {
+			for _, failure := range author.Failures {
+				fmt.Printf("    %.8s %s\n", failure.Hash, failure.Subject)
+				for _, problem := range failure.Problems {
+					fmt.Printf("      - %s\n", problem)
+				}
+			}
+		}
+	}
+
+	if report.Compliant < report.Commits && !GetVerbose() {
+		fmt.Println("\nRun with --verbose to list the failing commits.")
+	}
+	return lintResult(report)
+}
+
+// lintResult fails the command when any commit violates the conventions,
+// so scripts can rely on the exit code
+func lintResult(report *team.LintReport) error {
+	if failed := report.Commits - report.Compliant; failed > 0 {
+		return fmt.Errorf("%w: %d of %d commits failed", apperrors.ErrLintFailed, failed, report.Commits)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// cmd/log.go
+package cmd
+
+import (
+	"os"
+
+	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/llm"
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/jasonKoogler/comma/internal/ui"
+)
+
+// configureLogging sends log output to stderr as well as the log file, at
+// the level chosen with -v, -vv or --quiet, and hands the logger to the
+// git and LLM layers so their chatter follows the same verbosity. The log
+// file only receives messages at logging.level (--log-level) or above, in
+// logging.format. The commit service picks it up from the app context when
+// it is built.
+func configureLogging() error {
+	fileLevel, err := logging.ParseLogLevel(appContext.ConfigManager.GetString(config.LogLevelKey))
+	if err != nil {
+		return err
+	}
+	logFile, isFile := appContext.Logger.(*logging.FileLogger)
+	if isFile {
+		if err := logFile.SetFormat(appContext.ConfigManager.GetString(config.LogFormatKey)); err != nil {
+			return err
+		}
+	}
+
+	level := logging.LevelForVerbosity(GetVerbosity())
+	if quiet {
+		level = logging.ErrorLevel
+	}
+
+	// Callers such as editor plugins may pass in their own trace ID
+	traceID := os.Getenv("COMMA_TRACE_ID")
+	if traceID == "" {
+		traceID = logging.NewTraceID()
+	}
+	logging.SetTraceID(traceID)
+
+	file := logging.NewLeveledLogger(appContext.Logger, fileLevel)
+	console := logging.NewLeveledLogger(logging.NewConsoleLoggerTo(os.Stderr), level)
+	logger := logging.WithFields(logging.NewMultiLogger(file, console), logging.Fields{"trace_id": traceID})
+	appContext.Logger = logger
+	logger.Debug("Trace ID %s", traceID)
+	ui.SetNoticeLog(file)
+
+	git.SetLogger(logging.WithComponent(logger, "git"))
+	llm.SetLogger(logging.WithComponent(logger, "llm"))
+
+	// Old log files are pruned as a side effect of normal use
+	if isFile {
+		if removed, err := pruneLogs(false); err != nil {
+			logger.Debug("Failed to prune log files: %v", err)
+		} else if len(removed) > 0 {
+			logger.Debug("Pruned %d old log file(s)", len(removed))
+		}
+	}
+	return nil
+}
+
+// newProgress returns a spinner for a long-running step, or an indicator
+// that prints nothing when the output must stay clean
+func newProgress(enabled bool) ui.ProgressIndicator {
+	if !enabled || quiet {
+		return ui.NullProgress{}
+	}
+	return ui.CreateProgress(true)
+}
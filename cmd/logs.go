@@ -0,0 +1,194 @@
+// cmd/logs.go
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsCleanAll bool
+	logsLines    int
+	logsFollow   bool
+
+	logsCmd = &cobra.Command{
+		Use:   "logs",
+		Short: "Inspect and clean up Comma's log files",
+		Long: `Comma writes a dated log file per day to ~/.comma/logs. Files older than
+logging.max_age_days are removed automatically, as are the oldest files once
+all of them take more than logging.max_size_mb.`,
+	}
+
+	logsCleanCmd = &cobra.Command{
+		Use:   "clean",
+		Short: "Remove log files past the retention limits",
+		Long: `Remove log files older than logging.max_age_days and the oldest files
+beyond logging.max_size_mb. Use --all to remove every log file except today's.`,
+		Args: cobra.NoArgs,
+		RunE: runLogsClean,
+	}
+
+	logsTailCmd = &cobra.Command{
+		Use:   "tail",
+		Short: "Print the end of the current log file",
+		Long: `Print the last lines of the most recent log file. With --follow, keep
+printing lines as they are written until interrupted. Combine with
+--log-level debug on the command being debugged to see everything.`,
+		Args: cobra.NoArgs,
+		RunE: runLogsTail,
+	}
+)
+
+func init() {
+	logsCmd.AddCommand(logsCleanCmd)
+	logsCmd.AddCommand(logsTailCmd)
+
+	logsCleanCmd.Flags().BoolVar(&logsCleanAll, "all", false, "remove every log file except the current one")
+	logsTailCmd.Flags().IntVarP(&logsLines, "lines", "n", 20, "number of lines to print")
+	logsTailCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep printing new lines as they are written")
+}
+
+// pruneLogs applies the log retention settings, returning the removed files
+func pruneLogs(all bool) ([]logging.LogFile, error) {
+	dir, err := logging.LogDir("comma")
+	if err != nil {
+		return nil, err
+	}
+
+	if all {
+		files, err := logging.ListLogFiles(dir, "comma")
+		if err != nil || len(files) < 2 {
+			return nil, err
+		}
+		// The newest file is the one being written to
+		for i, f := range files[:len(files)-1] {
+			if err := os.Remove(f.Path); err != nil {
+				return files[:i], fmt.Errorf("failed to remove %s: %w", f.Path, err)
+			}
+		}
+		return files[:len(files)-1], nil
+	}
+
+	maxAge := time.Duration(appContext.ConfigManager.GetInt(config.LogMaxAgeKey)) * 24 * time.Hour
+	maxBytes := int64(appContext.ConfigManager.GetInt(config.LogMaxSizeKey)) << 20
+	return logging.PruneLogFiles(dir, "comma", maxAge, maxBytes)
+}
+
+func runLogsClean(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	removed, err := pruneLogs(logsCleanAll)
+	if err != nil {
+		return fmt.Errorf("failed to clean log files: %w", err)
+	}
+
+	var freed int64
+	for _, f := range removed {
+		freed += f.Size
+	}
+	fmt.Printf("✓ Removed %d log file(s), freeing %s\n", len(removed), formatBytes(freed))
+	return nil
+}
+
+func runLogsTail(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	dir, err := logging.LogDir("comma")
+	if err != nil {
+		return err
+	}
+	path, err := currentLogFile(dir)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { file.Close() }()
+
+	if err := printLastLines(file, logsLines); err != nil {
+		return err
+	}
+	if !logsFollow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if _, err := io.Copy(os.Stdout, file); err != nil {
+			return fmt.Errorf("failed to read log file: %w", err)
+		}
+
+		// Move on to the next day's file once it is created
+		if next, err := currentLogFile(dir); err == nil && next != path {
+			if nextFile, err := os.Open(next); err == nil {
+				file.Close()
+				file, path = nextFile, next
+			}
+		}
+	}
+}
+
+// currentLogFile returns the most recent log file
+func currentLogFile(dir string) (string, error) {
+	files, err := logging.ListLogFiles(dir, "comma")
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no log files in %s", dir)
+	}
+	return files[len(files)-1].Path, nil
+}
+
+// printLastLines prints the last n lines of r, leaving r at its end
+func printLastLines(r io.Reader, n int) error {
+	if n <= 0 {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+
+	lines := make([]string, 0, n)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log file: %w", err)
+	}
+
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// cmd/notify.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/update"
+	"github.com/spf13/cobra"
+)
+
+// updateNoticeGrace is how long a command waits at exit for a background
+// update check that is still running, so fast commands can still refresh it
+const updateNoticeGrace = 250 * time.Millisecond
+
+// updateNotifier prints a one-line notice when a newer release is known
+type updateNotifier struct {
+	checker *update.VersionChecker
+	info    *update.UpdateInfo
+	done    chan *update.UpdateInfo
+}
+
+// startUpdateNotifier looks up the cached update check and, when it is more
+// than a day old, refreshes it in the background. It returns nil when
+// notifications are disabled or would be out of place for the command.
+func startUpdateNotifier(cmd *cobra.Command) *updateNotifier {
+	if version == "dev" || quiet || appContext.IsOffline() ||
+		!appContext.ConfigManager.GetBool(config.UpdateNotifyKey) {
+		return nil
+	}
+
+	// The update command reports versions itself; hooks, servers and shell
+	// completion must stay quiet
+	switch cmd.Name() {
+	case "update", "version", "commit-msg", "pre-commit-run", "rpc", "serve", "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return nil
+	}
+
+	n := &updateNotifier{checker: update.NewVersionChecker(version, appContext.ConfigDir)}
+
+	info, fresh := n.checker.CachedUpdate()
+	n.info = info
+	if !fresh {
+		// Record the attempt first so a failed or abandoned check is not
+		// retried by every command until the cache expires
+		n.checker.MarkAttempted()
+		n.done = make(chan *update.UpdateInfo, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			info, _ := n.checker.CheckForUpdates(ctx)
+			n.done <- info
+		}()
+	}
+
+	return n
+}
+
+// finish prints the update notice, if any, to stderr so it never mixes with
+// command output that may be piped elsewhere
+func (n *updateNotifier) finish() {
+	if n == nil {
+		return
+	}
+
+	if n.done != nil {
+		select {
+		case info := <-n.done:
+			n.info = info
+		case <-time.After(updateNoticeGrace):
+		}
+	}
+
+	if n.info != nil {
+		fmt.Fprintf(os.Stderr, "\n%s\n", n.checker.GetNotice(n.info))
+	}
+}
@@ -0,0 +1,47 @@
+// cmd/output.go
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/jasonKoogler/comma/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// printJSON writes a value to stdout as indented JSON
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// colorEnabled reports whether output may use ANSI colors. Both --no-color
+// and the NO_COLOR environment variable (https://no-color.org) turn them off.
+func colorEnabled() bool {
+	return !noColor && os.Getenv("NO_COLOR") == ""
+}
+
+// configureColor applies --no-color to colored messages and the diff renderer
+func configureColor() {
+	if colorEnabled() {
+		return
+	}
+
+	color.NoColor = true
+	appContext.Renderer().SetColor(false)
+}
+
+// configureNotices hides notices for --quiet and for commands whose output
+// is read by other programs. Notices still go to the log file.
+func configureNotices(cmd *cobra.Command) {
+	switch cmd.Name() {
+	case "commit-msg", "pre-commit-run", "rpc", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		ui.SetNoticeOutput(nil)
+		return
+	}
+	if quiet {
+		ui.SetNoticeOutput(nil)
+	}
+}
@@ -0,0 +1,160 @@
+// cmd/plugin.go
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jasonKoogler/comma/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pluginInstallSHA256 string
+
+	pluginCmd = &cobra.Command{
+		Use:   "plugin",
+		Short: "Install and manage Comma plugins",
+	}
+
+	pluginListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List installed plugins",
+		Args:  cobra.NoArgs,
+		RunE:  runPluginList,
+	}
+
+	pluginInstallCmd = &cobra.Command{
+		Use:   "install <path|url>",
+		Short: "Install a plugin from a local file or an https URL",
+		Long: `Install a plugin from a local file or an https URL.
+Go plugins (.so files) must be built for this operating system, architecture
+and Go version. Any other file is installed as a gRPC plugin executable, which
+only needs to match the operating system and architecture. Both are checked
+before the plugin is copied into the plugins directory.
+
+Pass --sha256 to pin the expected checksum of the plugin file; the install is
+refused if it does not match. URLs cannot be installed in offline mode.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runPluginInstall,
+	}
+
+	pluginRemoveCmd = &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an installed plugin",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runPluginRemove,
+	}
+
+	pluginInfoCmd = &cobra.Command{
+		Use:   "info <name>",
+		Short: "Show details about an installed plugin",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runPluginInfo,
+	}
+)
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	pluginCmd.AddCommand(pluginInstallCmd)
+	pluginCmd.AddCommand(pluginRemoveCmd)
+	pluginCmd.AddCommand(pluginInfoCmd)
+
+	pluginInstallCmd.Flags().StringVar(&pluginInstallSHA256, "sha256", "", "expected SHA-256 checksum of the plugin file")
+}
+
+// newPluginManager creates a plugin manager for the plugin commands
+func newPluginManager() (*plugin.Manager, error) {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return nil, fmt.Errorf("configuration manager not initialized")
+	}
+	return plugin.NewManager(appContext), nil
+}
+
+func runPluginList(cmd *cobra.Command, args []string) error {
+	manager, err := newPluginManager()
+	if err != nil {
+		return err
+	}
+
+	installed, err := manager.Installed()
+	if err != nil {
+		return err
+	}
+
+	if len(installed) == 0 {
+		fmt.Printf("No plugins installed in %s\n", manager.Dir())
+		fmt.Println("Run 'comma plugin install <path|url>' to add one.")
+		return nil
+	}
+
+	fmt.Println("Installed plugins:")
+	for _, p := range installed {
+		source := p.Source
+		if source == "" {
+			source = "copied manually"
+		}
+		fmt.Printf("  %-20s %-5s %10s  %s\n", p.Name, p.Kind, formatBytes(p.Size), source)
+	}
+	return nil
+}
+
+func runPluginInstall(cmd *cobra.Command, args []string) error {
+	manager, err := newPluginManager()
+	if err != nil {
+		return err
+	}
+
+	installed, err := manager.Install(args[0], pluginInstallSHA256)
+	if err != nil {
+		return fmt.Errorf("failed to install plugin: %w", err)
+	}
+
+	fmt.Printf("✓ Plugin %s installed to %s\n", installed.Name, installed.Path)
+	return nil
+}
+
+func runPluginRemove(cmd *cobra.Command, args []string) error {
+	manager, err := newPluginManager()
+	if err != nil {
+		return err
+	}
+
+	if err := manager.Remove(args[0]); err != nil {
+		return fmt.Errorf("failed to remove plugin: %w", err)
+	}
+
+	fmt.Printf("✓ Plugin %s removed\n", args[0])
+	return nil
+}
+
+func runPluginInfo(cmd *cobra.Command, args []string) error {
+	manager, err := newPluginManager()
+	if err != nil {
+		return err
+	}
+
+	installed, err := manager.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Name: %s\n", installed.Name)
+	fmt.Printf("Kind: %s\n", installed.Kind)
+	fmt.Printf("Path: %s\n", installed.Path)
+	fmt.Printf("Size: %s\n", formatBytes(installed.Size))
+	if installed.Source != "" {
+		fmt.Printf("Source: %s\n", installed.Source)
+		fmt.Printf("SHA-256: %s\n", installed.SHA256)
+	}
+	if !installed.InstalledAt.IsZero() {
+		fmt.Printf("Installed: %s\n", installed.InstalledAt.Format("2006-01-02 15:04"))
+	}
+
+	name, version, err := manager.Describe(args[0])
+	if err != nil {
+		fmt.Printf("⚠️  Could not load plugin: %v\n", err)
+		return nil
+	}
+	fmt.Printf("Reports: %s v%s\n", name, version)
+	return nil
+}
@@ -0,0 +1,290 @@
+// cmd/pr.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/commit"
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/forge"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prBase     string
+	prRemote   string
+	prDraft    bool
+	prNoLabels bool
+	prYes      bool
+	prForge    string
+
+	prCmd = &cobra.Command{
+		Use:     "pr",
+		Aliases: []string{"mr"},
+		Short:   "Generate and publish pull and merge request descriptions",
+	}
+
+	prGenerateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a pull request title and description for the current branch",
+		Long: `Generate a pull request title and description from the commits the current
+branch adds on top of the base branch. The base defaults to the branch the
+remote's HEAD points to.`,
+		Args: cobra.NoArgs,
+		RunE: runPRGenerate,
+	}
+
+	prPublishCmd = &cobra.Command{
+		Use:   "publish",
+		Short: "Generate a description and create or update the pull request on GitHub or GitLab",
+		Long: `Generate a description and publish it through the GitHub or GitLab API.
+If the branch already has an open pull (merge) request its title and
+description are replaced; otherwise a new one is opened. Labels are inferred
+from the conventional commit types on the branch (feat adds "enhancement",
+fix adds "bug", and so on).
+
+The service is detected from the remote's host name; use --forge for
+self-hosted instances whose name mentions neither. Push the branch first.
+The token is read from GITHUB_TOKEN or GH_TOKEN for GitHub and GITLAB_TOKEN
+for GitLab, or from the credential store, where 'comma auth rotate github'
+or 'comma auth rotate gitlab' saves it.`,
+		Args: cobra.NoArgs,
+		RunE: runPRPublish,
+	}
+)
+
+func init() {
+	prCmd.AddCommand(prGenerateCmd)
+	prCmd.AddCommand(prPublishCmd)
+
+	prCmd.PersistentFlags().StringVar(&prBase, "base", "", "branch the changes will be merged into (default: the remote's default branch)")
+	prCmd.PersistentFlags().StringVar(&prRemote, "remote", "origin", "git remote hosting the repository")
+
+	prPublishCmd.Flags().StringVar(&prForge, "forge", "", "hosting service: github or gitlab (default: detected from the remote)")
+	prPublishCmd.Flags().BoolVar(&prDraft, "draft", false, "open the pull request as a draft")
+	prPublishCmd.Flags().BoolVar(&prNoLabels, "no-labels", false, "don't add labels inferred from commit types")
+	prPublishCmd.Flags().BoolVarP(&prYes, "yes", "y", false, "publish without asking for confirmation")
+}
+
+func runPRGenerate(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	_, base, err := prBranches(repo)
+	if err != nil {
+		return err
+	}
+
+	pr, err := generatePullRequest(cmd.Context(), repo, base)
+	if err != nil {
+		return err
+	}
+
+	printPullRequest(pr)
+	return nil
+}
+
+func runPRPublish(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+	if appContext.IsOffline() {
+		return fmt.Errorf("cannot publish a pull request: %w", apperrors.ErrOffline)
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	remoteURL, err := repo.RemoteURL(prRemote)
+	if err != nil {
+		return err
+	}
+	remote, err := forge.ParseRemote(remoteURL)
+	if err != nil {
+		return err
+	}
+
+	service := prForge
+	if service == "" {
+		if service = forge.DetectService(remote); service == "" {
+			return fmt.Errorf("cannot tell which service hosts %s - pass --forge github or --forge gitlab", remote.Host)
+		}
+	}
+
+	token := forgeToken(service)
+	if token == "" {
+		return fmt.Errorf("%w: set %s or store a token with 'comma auth rotate %s'",
+			apperrors.ErrAPIKeyMissing, strings.Join(forgeTokenEnv[service], " or "), service)
+	}
+
+	client, err := forge.NewClient(service, remote, token)
+	if err != nil {
+		return err
+	}
+
+	branch, base, err := prBranches(repo)
+	if err != nil {
+		return err
+	}
+
+	// Ask for a terminal before spending an LLM request
+	if !prYes && !stdinIsTerminal() {
+		return fmt.Errorf("stdin is not a terminal - pass --yes to publish without confirmation")
+	}
+
+	generated, err := generatePullRequest(cmd.Context(), repo, base)
+	if err != nil {
+		return err
+	}
+	printPullRequest(generated)
+
+	var labels []string
+	if !prNoLabels {
+		messages := make([]string, len(generated.Commits))
+		for i, c := range generated.Commits {
+			messages[i] = c.Message
+		}
+		labels = forge.LabelsForCommits(messages)
+		if len(labels) > 0 {
+			fmt.Printf("Labels: %v\n", labels)
+		}
+	}
+
+	if !prYes {
+		publish, err := promptYesNo(fmt.Sprintf("\nPublish to %s on %s?", remote.Path(), client.Name()))
+		if err != nil {
+			return err
+		}
+		if !publish {
+			fmt.Println("Publishing aborted.")
+			return nil
+		}
+	}
+
+	pr := &forge.PullRequest{
+		Title: generated.Title,
+		Body:  generated.Body,
+		Head:  branch,
+		Base:  base,
+		Draft: prDraft,
+	}
+
+	existing, err := client.FindPullRequest(remote, branch)
+	if err != nil {
+		return err
+	}
+
+	var published *forge.PullRequest
+	if existing != nil {
+		pr.Number = existing.Number
+		if published, err = client.UpdatePullRequest(remote, pr); err != nil {
+			return err
+		}
+	} else if published, err = client.CreatePullRequest(remote, pr); err != nil {
+		return err
+	}
+
+	if err := client.AddLabels(remote, published.Number, labels); err != nil {
+		// The pull request itself was published, so only warn
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+
+	kind, ref := "pull request", fmt.Sprintf("#%d", published.Number)
+	if service == forge.ServiceGitLab {
+		kind, ref = "merge request", fmt.Sprintf("!%d", published.Number)
+	}
+	if existing != nil {
+		fmt.Printf("✓ Updated %s %s: %s\n", kind, ref, published.URL)
+	} else {
+		fmt.Printf("✓ Opened %s %s: %s\n", kind, ref, published.URL)
+	}
+	return nil
+}
+
+// prBranches returns the current branch and the branch it will be merged into
+func prBranches(repo *git.Repository) (branch, base string, err error) {
+	branch, err = repo.CurrentBranch()
+	if err != nil {
+		return "", "", err
+	}
+
+	base = prBase
+	if base == "" {
+		base = repo.DefaultBranch(prRemote)
+	}
+	if branch == base {
+		return "", "", fmt.Errorf("already on %s - check out the branch with your changes", base)
+	}
+	return branch, base, nil
+}
+
+// generatePullRequest describes the commits since base, comparing against
+// the remote's copy of base when it is available
+func generatePullRequest(ctx context.Context, repo *git.Repository, base string) (*commit.PullRequest, error) {
+	commitService, err := getCommitService()
+	if err != nil {
+		return nil, err
+	}
+
+	compare := base
+	if remoteBase := prRemote + "/" + base; repo.RefExists(remoteBase) {
+		compare = remoteBase
+	} else if !repo.RefExists(base) {
+		return nil, fmt.Errorf("base branch %s not found", base)
+	}
+
+	progress := newProgress(true)
+	progress.Start("Generating pull request description")
+	ctx, stop := interruptible(ctx)
+	pr, err := commitService.GeneratePullRequest(ctx, repo, compare)
+	stop()
+	progress.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pull request description: %w", err)
+	}
+	return pr, nil
+}
+
+// printPullRequest shows a generated title and description
+func printPullRequest(pr *commit.PullRequest) {
+	fmt.Println("\nTitle:")
+	fmt.Println(pr.Title)
+	fmt.Println("\nDescription:")
+	fmt.Println("-------------------")
+	fmt.Println(pr.Body)
+	fmt.Println("-------------------")
+}
+
+// forgeTokenEnv lists the environment variables holding each service's token
+var forgeTokenEnv = map[string][]string{
+	forge.ServiceGitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	forge.ServiceGitLab: {"GITLAB_TOKEN"},
+}
+
+// forgeToken returns a hosting service token from the environment or the
+// credential store, where it is kept under the service name
+func forgeToken(service string) string {
+	for _, env := range forgeTokenEnv[service] {
+		if token := os.Getenv(env); token != "" {
+			return token
+		}
+	}
+	credMgr, err := appContext.CredentialMgr()
+	if err != nil {
+		return ""
+	}
+	token, _ := credMgr.Retrieve(service)
+	return token
+}
@@ -0,0 +1,60 @@
+// cmd/profile.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// profileFile is where --profile writes its pprof output, in the current directory
+const profileFile = "comma.%s.pprof"
+
+// stopProfile finishes the profile started by startProfile
+var stopProfile = func() {}
+
+// startProfile starts the profile selected with the hidden --profile flag.
+// CPU profiles cover the command; memory profiles are taken when it ends.
+func startProfile() error {
+	switch profile {
+	case "":
+		return nil
+	case "cpu":
+		path := fmt.Sprintf(profileFile, profile)
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		stopProfile = func() {
+			pprof.StopCPUProfile()
+			file.Close()
+			fmt.Fprintf(os.Stderr, "CPU profile written to %s\n", path)
+		}
+	case "mem":
+		path := fmt.Sprintf(profileFile, profile)
+		stopProfile = func() {
+			file, err := os.Create(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to create profile: %v\n", err)
+				return
+			}
+			defer file.Close()
+
+			// Report live objects as of the end of the command
+			runtime.GC()
+			if err := pprof.WriteHeapProfile(file); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to write memory profile: %v\n", err)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Memory profile written to %s\n", path)
+		}
+	default:
+		return fmt.Errorf("unsupported profile: %s (use cpu or mem)", profile)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// cmd/prompt.go
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// stdinIsTerminal reports whether stdin is attached to a terminal
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// requireTerminal fails fast when a question cannot be answered because
+// stdin is not a terminal, instead of hanging or reading piped input
+func requireTerminal(question string) error {
+	if !stdinIsTerminal() {
+		return fmt.Errorf("cannot ask %q: stdin is not a terminal", strings.TrimSpace(question))
+	}
+	return nil
+}
+
+// stdinReader is shared by all prompts so no input is lost to buffering
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// Helper function to prompt for yes/no
+func promptYesNo(question string) (bool, error) {
+	response, err := promptChoice(fmt.Sprintf("%s (y/n): ", question))
+	if err != nil {
+		return false, err
+	}
+	return response == "y" || response == "yes", nil
+}
+
+// promptChoice asks a question and returns the lowercased answer
+func promptChoice(question string) (string, error) {
+	response, err := promptLine(question)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(response), nil
+}
+
+// promptLine asks a question and returns the trimmed line typed in reply
+func promptLine(question string) (string, error) {
+	if err := requireTerminal(question); err != nil {
+		return "", err
+	}
+
+	fmt.Print(question)
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// promptMultiSelect lists numbered items and returns the indexes of those the
+// user picks, e.g. "1 3-5". An empty answer picks nothing.
+func promptMultiSelect(question string, items []string) ([]int, error) {
+	for i, item := range items {
+		fmt.Printf("%3d) %s\n", i+1, item)
+	}
+
+	for {
+		answer, err := promptLine(question)
+		if err != nil {
+			return nil, err
+		}
+		picked, err := parseSelection(answer, len(items))
+		if err == nil {
+			return picked, nil
+		}
+		fmt.Printf("⚠️  %v\n", err)
+	}
+}
+
+// parseSelection reads space- or comma-separated numbers and ranges from 1
+// to n, returning zero-based indexes in order without duplicates
+func parseSelection(answer string, n int) ([]int, error) {
+	seen := make(map[int]bool)
+	var picked []int
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ' ' || r == ',' }) {
+		from, to, isRange := strings.Cut(field, "-")
+		if !isRange {
+			to = from
+		}
+		first, err1 := strconv.Atoi(from)
+		last, err2 := strconv.Atoi(to)
+		if err1 != nil || err2 != nil || first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("%q is not a number or range from 1 to %d", field, n)
+		}
+		for i := first - 1; i < last; i++ {
+			if !seen[i] {
+				seen[i] = true
+				picked = append(picked, i)
+			}
+		}
+	}
+	sort.Ints(picked)
+	return picked, nil
+}
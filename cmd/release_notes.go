@@ -0,0 +1,129 @@
+// cmd/release_notes.go
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/conventional"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseTag       string
+	releaseFrom      string
+	releaseNoSummary bool
+	releaseOutput    string
+
+	releaseNotesCmd = &cobra.Command{
+		Use:   "release-notes",
+		Short: "Generate release notes from conventional commits",
+		Long: `Generate release notes for a release: highlights written by the LLM and
+grouped by area, followed by the conventional changelog of breaking
+changes, features, fixes, performance improvements and reverts.
+
+The release covers the commits since the previous tag. --tag may name a tag
+that doesn't exist yet, in which case the notes cover everything up to HEAD:
+
+  comma release-notes --tag "$(comma next-version -q)" -o NOTES.md
+
+Use --no-summary for the changelog alone, without calling the LLM.`,
+		Args: cobra.NoArgs,
+		RunE: runReleaseNotes,
+	}
+)
+
+func init() {
+	releaseNotesCmd.Flags().StringVar(&releaseTag, "tag", "", "version being released, e.g. v2.0.0 (required)")
+	releaseNotesCmd.Flags().StringVar(&releaseFrom, "from", "", "previous release tag (default: the tag before --tag)")
+	releaseNotesCmd.Flags().BoolVar(&releaseNoSummary, "no-summary", false, "only print the changelog, without LLM highlights")
+	releaseNotesCmd.Flags().StringVarP(&releaseOutput, "output", "o", "", "write the notes to a file instead of stdout")
+	releaseNotesCmd.MarkFlagRequired("tag")
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	// An existing tag ends the release; otherwise it is being prepared at HEAD
+	to, from := "HEAD", releaseFrom
+	if repo.RefExists(releaseTag) {
+		to = releaseTag
+		if from == "" {
+			from = repo.TagBefore(releaseTag)
+		}
+	} else if from == "" {
+		from = repo.LatestTag()
+	}
+
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+	commits, err := repo.GetCommitRange(revRange)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]conventional.ChangelogEntry, len(commits))
+	for i, c := range commits {
+		entries[i] = conventional.ChangelogEntry{Hash: c.Hash, Message: c.Message}
+	}
+	changelog := conventional.Changelog(entries)
+	if changelog == "" {
+		return fmt.Errorf("no features, fixes or breaking changes since %s", describeTag(from))
+	}
+
+	date := time.Now()
+	if to != "HEAD" && len(commits) > 0 {
+		date = commits[0].Date
+	}
+	notes := fmt.Sprintf("## %s (%s)\n\n", releaseTag, date.Format("2006-01-02"))
+
+	if !releaseNoSummary {
+		commitService, err := getCommitService()
+		if err != nil {
+			return err
+		}
+
+		progress := newProgress(!quiet && releaseOutput != "")
+		progress.Start("Summarizing release highlights")
+		ctx, stop := interruptible(cmd.Context())
+		highlights, err := commitService.GenerateReleaseHighlights(ctx, releaseTag, changelog)
+		stop()
+		progress.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to generate release highlights: %w", err)
+		}
+		notes += "### Highlights\n\n" + highlights + "\n\n"
+	}
+	notes += changelog + "\n"
+
+	var out io.Writer = os.Stdout
+	if releaseOutput != "" {
+		file, err := os.Create(releaseOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if _, err := io.WriteString(out, notes); err != nil {
+		return fmt.Errorf("failed to write release notes: %w", err)
+	}
+
+	if releaseOutput != "" && !quiet {
+		fmt.Printf("✓ Release notes written to %s\n", releaseOutput)
+	}
+	return nil
+}
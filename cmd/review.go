@@ -0,0 +1,183 @@
+// cmd/review.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jasonKoogler/comma/internal/audit"
+	"github.com/jasonKoogler/comma/internal/commit"
+	"github.com/jasonKoogler/comma/internal/conventional"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/llm"
+)
+
+// editMessageHelp is appended to the message opened in the editor
+const editMessageHelp = `
+# Edit the commit message above. Lines starting with '#' are ignored,
+# and an empty message keeps the previous one.
+`
+
+// editBodyHelp is appended to the body opened in the editor
+const editBodyHelp = `
+# Edit the commit message body above; the subject is kept. Lines starting
+# with '#' are ignored, and an empty body removes it. Lines are wrapped at
+# 72 columns.
+`
+
+// printGeneratedMessage shows a generated message and, for cached
+// messages, where it came from
+func printGeneratedMessage(result *commit.Result) {
+	fmt.Println("\nGenerated Commit Message:")
+	fmt.Println("-------------------")
+	fmt.Println(result.Message)
+	fmt.Println("-------------------")
+
+	if result.Cached {
+		printCacheProvenance(result)
+	} else if result.Usage != nil {
+		printUsage(result.Usage)
+	}
+}
+
+// printUsage shows the tokens a generation took and what they cost
+func printUsage(usage *llm.Usage) {
+	about := ""
+	if usage.Estimated {
+		about = "~"
+	}
+	line := fmt.Sprintf("Tokens: %s%d prompt + %s%d completion", about, usage.PromptTokens, about, usage.CompletionTokens)
+	if usage.CostUSD > 0 {
+		line += fmt.Sprintf(" (about $%.4f)", usage.CostUSD)
+	}
+	fmt.Println(line)
+}
+
+// reviewMessage lets the user accept the generated message, edit it in
+// their editor, change its subject or body alone, regenerate it with
+// optional guidance or another commit type, or abort. opts are the options
+// the message was generated with. It returns the message to commit, or ""
+// when the user aborts.
+func reviewMessage(ctx context.Context, repo *git.Repository, commitService *commit.Service, result *commit.Result, opts commit.GenerateOptions) (string, error) {
+	show := true
+	for {
+		if show {
+			printGeneratedMessage(result)
+		}
+		show = false
+
+		choice, err := promptChoice("[a]ccept, [e]dit, [s]ubject, [b]ody, [t]ype, [r]egenerate, [q]uit: ")
+		if err != nil {
+			return "", err
+		}
+
+		switch choice {
+		case "a", "accept", "y", "yes":
+			return result.Message, nil
+
+		case "e", "edit":
+			edited, err := llm.EditPrompt(result.Message + "\n" + editMessageHelp)
+			if err != nil {
+				return "", err
+			}
+			if edited = stripCommitComments(edited); edited != "" {
+				return edited, nil
+			}
+			fmt.Println("Empty message, keeping the generated one.")
+			show = true
+
+		case "s", "subject":
+			subject, body := commit.SplitMessage(result.Message)
+			fmt.Printf("Current subject (%d characters): %s\n", len(subject), subject)
+			edited, err := promptLine("New subject (empty keeps it): ")
+			if err != nil {
+				return "", err
+			}
+			if edited != "" {
+				if len(edited) > 72 {
+					fmt.Printf("⚠️  The subject is %d characters; git tools expect 72 at most.\n", len(edited))
+				}
+				result.Message = commit.JoinMessage(edited, body)
+			}
+			show = true
+
+		case "b", "body":
+			subject, body := commit.SplitMessage(result.Message)
+			edited, err := llm.EditPrompt(body + "\n" + editBodyHelp)
+			if err != nil {
+				return "", err
+			}
+			result.Message = commit.JoinMessage(subject, stripCommitComments(edited))
+			show = true
+
+		case "t", "type":
+			current := ""
+			if parsed, err := conventional.Parse(result.Message); err == nil {
+				current = typeLabel(parsed.Type, parsed.Scope)
+			}
+			if current != "" {
+				fmt.Printf("Current type: %s\n", current)
+			}
+			chosenType, chosenScope, err := chooseCommitType(nil, current)
+			if err != nil {
+				return "", err
+			}
+			if chosenType == "" || typeLabel(chosenType, chosenScope) == current {
+				continue
+			}
+
+			opts.Type, opts.Scope = chosenType, chosenScope
+			if regenerated := regenerate(ctx, repo, commitService, commit.GenerateOptions{
+				NoCache: true,
+				Issues:  opts.Issues,
+				Type:    opts.Type,
+				Scope:   opts.Scope,
+			}); regenerated != nil {
+				result = regenerated
+			}
+			show = true
+
+		case "r", "regenerate":
+			guidance, err := promptLine("Guidance for the new message (optional): ")
+			if err != nil {
+				return "", err
+			}
+
+			if regenerated := regenerate(ctx, repo, commitService, commit.GenerateOptions{
+				NoCache:  true,
+				Guidance: guidance,
+				Rejected: result.Message,
+				Issues:   opts.Issues,
+				Type:     opts.Type,
+				Scope:    opts.Scope,
+			}); regenerated != nil {
+				result = regenerated
+				show = true
+			}
+
+		case "q", "quit", "n", "no", "abort":
+			return "", nil
+
+		default:
+			fmt.Printf("Unknown choice %q, choose a, e, s, b, t, r or q.\n", choice)
+		}
+	}
+}
+
+// regenerate generates a new message from the review menu, returning nil
+// after reporting a failure
+func regenerate(ctx context.Context, repo *git.Repository, commitService *commit.Service, opts commit.GenerateOptions) *commit.Result {
+	progress := newProgress(true)
+	progress.Start("Generating a fresh commit message")
+	genCtx, stop := interruptible(ctx)
+	regenerated, err := commitService.Generate(genCtx, repo, opts)
+	stop()
+	progress.Stop()
+	if err != nil {
+		recordAuditEvent(repo, audit.Event{Action: audit.ActionGenerate, Status: "error", Error: err.Error()})
+		fmt.Printf("⚠️  Failed to regenerate: %v\n", err)
+		return nil
+	}
+	recordAuditEvent(repo, generatedEvent(regenerated))
+	return regenerated
+}
@@ -3,24 +3,34 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/conventional"
+	"github.com/jasonKoogler/comma/internal/git"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
 	cfgFile     string
-	verbose     bool
+	verbosity   int
 	llmProvider string
 	apiKey      string
 	model       string // This was missing in your original code snippet but referenced
+	offline     bool
+	quiet       bool
+	noColor     bool
+	profile     string
 	rootCmd     = &cobra.Command{
 		Use:   "comma",
 		Short: "AI-powered git commit message generator",
 		Long: `Comma analyzes your git changes and uses AI to generate meaningful commit messages.
 It integrates with various LLM providers and is highly customizable.`,
 		SilenceUsage: true,
+		// main prints the error once and sets the exit code
+		SilenceErrors: true,
 	}
 	appContext *config.AppContext
 )
@@ -29,28 +39,80 @@ It integrates with various LLM providers and is highly customizable.`,
 func Execute(ctx *config.AppContext) error {
 	appContext = ctx
 
+	var notifier *updateNotifier
+
 	// Add a post-initialization hook to check LLM setup
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := startProfile(); err != nil {
+			return err
+		}
+		configureColor()
+		configureNotices(cmd)
+		if err := configureLogging(); err != nil {
+			return err
+		}
+		git.SetMaxDiffBytes(appContext.ConfigManager.GetInt(config.MaxDiffBytesKey))
+		git.SetExcludeGenerated(appContext.ConfigManager.GetBool(config.PromptExcludeGeneratedKey))
+		git.SetExcludeGlobs(strings.Split(appContext.ConfigManager.GetString(config.PromptExcludeGlobsKey), ","))
+		git.SetIncludeUntracked(appContext.ConfigManager.GetBool(config.PromptIncludeUntrackedKey))
+		conventional.SetTypes(strings.Split(appContext.ConfigManager.GetString(config.ConventionalTypesKey), ","))
+
+		var branchPatterns []string
+		if err := appContext.ConfigManager.UnmarshalKey(config.BranchPatternsKey, &branchPatterns); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Ignoring invalid %s: %v\n", config.BranchPatternsKey, err)
+		}
+		if err := git.SetBranchPatterns(branchPatterns); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		// Offline mode uses the local model, so there is no provider to check
+		if appContext.IsOffline() {
+			appContext.ApplyOffline()
+			return nil
+		}
+
+		notifier = startUpdateNotifier(cmd)
+
 		// Skip checks for these commands that don't need LLM
 		skipCommands := map[string]bool{
 			"version": true,
 			"help":    true,
 			"config":  true,
 			"setup":   true,
+			"approve": true,
+			"auth":    true,
+			"cache":   true,
+			"analyze": true,
+			"lint":    true,
+			"plugin":  true,
+			"logs":    true,
+			// Release scripts read the output of next-version
+			"next-version": true,
+			// Completion output is read by the shell
+			"completion":                    true,
+			"docs":                          true,
+			cobra.ShellCompRequestCmd:       true,
+			cobra.ShellCompNoDescRequestCmd: true,
+			// Git hooks must stay quiet unless they reject something
+			"commit-msg":     true,
+			"pre-commit-run": true,
 		}
 
-		if _, skip := skipCommands[cmd.Name()]; !skip && cmd.Parent() != nil && cmd.Parent().Name() != "config" {
+		if _, skip := skipCommands[cmd.Name()]; !skip && cmd.Parent() != nil && !skipCommands[cmd.Parent().Name()] {
 			// Check if LLM is configured properly using ConfigManager
 			provider := appContext.ConfigManager.GetString(config.LLMProviderKey)
 			if provider == "" || provider == "none" {
-				fmt.Println("⚠️  LLM provider not configured. Some commands may not work properly.")
-				fmt.Println("   Run 'comma setup' or 'comma config set --provider openai' to configure.")
+				appContext.Logger.Warn("LLM provider not configured. Some commands may not work properly.\n" +
+					"   Run 'comma setup' or 'comma config set --provider openai' to configure.")
 			}
 		}
 		return nil
 	}
 
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	stopProfile()
+	notifier.finish()
+	return err
 }
 
 func init() {
@@ -59,16 +121,23 @@ func init() {
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.comma/config.yaml)")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "verbose output; repeat (-vv) for debug output")
 	rootCmd.PersistentFlags().StringVar(&llmProvider, "provider", "", "LLM provider to use (openai, anthropic, etc.)")
 	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "API key for the LLM provider (overrides config)")
 	rootCmd.PersistentFlags().StringVar(&model, "model", "", "LLM model to use (overrides config)")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "never access the network; use the local model only")
+	rootCmd.PersistentFlags().String("log-level", "", "minimum level written to the log file: debug, info, warn or error (default from logging.level)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress decorative output; check the exit code instead")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also set by the NO_COLOR environment variable)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "write a pprof profile of the command (cpu, mem) to comma.<kind>.pprof")
+	rootCmd.PersistentFlags().MarkHidden("profile")
 
 	// Bind flags to viper - we still need this for the flags to affect configuration
 	viper.BindPFlag(config.LLMProviderKey, rootCmd.PersistentFlags().Lookup("provider"))
 	viper.BindPFlag(config.LLMAPIKeyKey, rootCmd.PersistentFlags().Lookup("api-key"))
 	viper.BindPFlag(config.LLMModelKey, rootCmd.PersistentFlags().Lookup("model"))
-	viper.BindPFlag(config.VerboseKey, rootCmd.PersistentFlags().Lookup("verbose"))
+	viper.BindPFlag(config.LLMOfflineKey, rootCmd.PersistentFlags().Lookup("offline"))
+	viper.BindPFlag(config.LogLevelKey, rootCmd.PersistentFlags().Lookup("log-level"))
 
 	// Handle custom config file if specified
 	cobra.OnInitialize(func() {
@@ -80,19 +149,48 @@ func init() {
 	// Add commands
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(installCmd)
+	rootCmd.AddCommand(hookCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(versionCmd)
 	// rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(analyzeCmd)
 	rootCmd.AddCommand(enterpriseCmd)
+	rootCmd.AddCommand(approveCmd)
 	rootCmd.AddCommand(setupCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(cacheCmd)
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(pluginCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(docsCmd)
+	rootCmd.AddCommand(prCmd)
+	rootCmd.AddCommand(nextVersionCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(rpcCmd)
+	rootCmd.AddCommand(releaseNotesCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(describeCmd)
+	rootCmd.AddCommand(stashCmd)
+	registerCompletions()
 }
 
-// GetVerbose returns the verbose flag
+// GetVerbose reports whether verbose output is enabled
 func GetVerbose() bool {
-	if appContext != nil && appContext.ConfigManager != nil {
-		return appContext.ConfigManager.GetBool(config.VerboseKey)
+	return GetVerbosity() > 0
+}
+
+// GetVerbosity returns how many times -v was given. Setting verbose in the
+// config file counts as a single -v.
+func GetVerbosity() int {
+	if verbosity > 0 {
+		return verbosity
+	}
+	if appContext != nil && appContext.ConfigManager != nil && appContext.ConfigManager.GetBool(config.VerboseKey) {
+		return 1
+	}
+	if viper.GetBool(config.VerboseKey) {
+		return 1
 	}
-	return viper.GetBool(config.VerboseKey)
+	return 0
 }
@@ -0,0 +1,299 @@
+// cmd/rpc.go
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var rpcCmd = &cobra.Command{
+	Use:   "rpc",
+	Short: "Serve JSON-RPC requests on stdin and stdout for editor integrations",
+	Long: `Run a persistent JSON-RPC 2.0 session on stdin and stdout, framed with
+Content-Length headers like the Language Server Protocol, so VS Code and
+Neovim plugins can embed Comma without starting a process per request.
+
+Methods:
+  initialize  server name, version and the supported methods
+  generate    {"diff": "..."} or {"repo": "/path"}; same fields as generate --json
+  classify    {"diff": "..."} or {"repo": "/path"}; likely commit types, no LLM call
+  lint        {"message": "..."} or {"range": "v1.0.0..HEAD", "repo": "/path"}
+  analyze     {"repo": "/path", "days": 30}
+  shutdown    stop accepting requests; the "exit" notification ends the session
+
+Errors use code -32000 minus the CLI's exit code, e.g. -32004 for changes
+with blocking security findings, which are listed in the error data.`,
+	Args: cobra.NoArgs,
+	RunE: runRPC,
+}
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000 // Minus the CLI exit code
+)
+
+// rpcRequest is a JSON-RPC request, or a notification when it has no ID
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC response; exactly one of Result and Error is set
+type rpcResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      json.RawMessage  `json:"id"`
+	Result  *json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError        `json:"error,omitempty"`
+}
+
+// rpcError is the error member of a response
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// rpcMethods are the requests the session answers besides the lifecycle ones
+var rpcMethods = map[string]func(params json.RawMessage) (interface{}, error){
+	"generate": func(params json.RawMessage) (interface{}, error) {
+		var req generateRequest
+		if err := decodeRPCParams(params, &req); err != nil {
+			return nil, err
+		}
+		out, err := apiGenerate(context.Background(), req)
+		if err != nil && out != nil {
+			return nil, &rpcError{Code: rpcCode(err), Message: err.Error(), Data: map[string]interface{}{"findings": out.Findings}}
+		}
+		return out, err
+	},
+	"classify": func(params json.RawMessage) (interface{}, error) {
+		var req classifyRequest
+		if err := decodeRPCParams(params, &req); err != nil {
+			return nil, err
+		}
+		return apiClassify(req)
+	},
+	"lint": func(params json.RawMessage) (interface{}, error) {
+		var req lintRequest
+		if err := decodeRPCParams(params, &req); err != nil {
+			return nil, err
+		}
+		return apiLint(req)
+	},
+	"analyze": func(params json.RawMessage) (interface{}, error) {
+		var req analyzeRequest
+		if err := decodeRPCParams(params, &req); err != nil {
+			return nil, err
+		}
+		return apiAnalyze(req)
+	},
+}
+
+// Error implements the error interface so handlers can return protocol errors
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func runRPC(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	// Stdout carries the protocol; stray output from anything else goes to stderr
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = out }()
+
+	if loadActiveTeam() {
+		applyTeamSettings()
+	}
+
+	return serveRPC(os.Stdin, out)
+}
+
+// serveRPC answers requests until the client sends "exit" or closes stdin
+func serveRPC(in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	shutdown := false
+
+	for {
+		body, err := readRPCMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := writeRPCMessage(out, rpcFailure(nil, &rpcError{Code: rpcParseError, Message: err.Error()})); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		logging.WithFields(logging.WithComponent(appContext.Logger, "rpc"),
+			logging.Fields{"method": req.Method}).Debug("rpc: %s", req.Method)
+		result, err := dispatchRPC(&req, &shutdown)
+
+		// Notifications get no response
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		var resp *rpcResponse
+		if err == nil {
+			resp, err = rpcSuccess(req.ID, result)
+		}
+		if err != nil {
+			resp = rpcFailure(req.ID, err)
+		}
+		if err := writeRPCMessage(out, resp); err != nil {
+			return err
+		}
+	}
+}
+
+// dispatchRPC runs a single request
+func dispatchRPC(req *rpcRequest, shutdown *bool) (interface{}, error) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, &rpcError{Code: rpcInvalidRequest, Message: "not a JSON-RPC 2.0 request"}
+	}
+
+	switch req.Method {
+	case "initialize":
+		methods := []string{"initialize", "shutdown", "exit"}
+		for name := range rpcMethods {
+			methods = append(methods, name)
+		}
+		sort.Strings(methods)
+		return map[string]interface{}{"name": "comma", "version": version, "methods": methods}, nil
+	case "shutdown":
+		*shutdown = true
+		return nil, nil
+	}
+
+	handler, ok := rpcMethods[req.Method]
+	if !ok {
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: "unknown method " + req.Method}
+	}
+	if *shutdown {
+		return nil, &rpcError{Code: rpcInvalidRequest, Message: "the session is shutting down"}
+	}
+	return handler(req.Params)
+}
+
+// decodeRPCParams decodes request params, which may be omitted
+func decodeRPCParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// rpcCode maps an error to a JSON-RPC error code
+func rpcCode(err error) int {
+	if errors.Is(err, errInvalidRequest) {
+		return rpcInvalidParams
+	}
+	return rpcServerError - apperrors.ExitCode(err)
+}
+
+// rpcSuccess builds a response carrying a result, which may be null
+func rpcSuccess(id json.RawMessage, result interface{}) (*rpcResponse, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	raw := json.RawMessage(data)
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Result: &raw}, nil
+}
+
+// rpcFailure builds an error response
+func rpcFailure(id json.RawMessage, err error) *rpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	var rpcErr *rpcError
+	if !errors.As(err, &rpcErr) {
+		rpcErr = &rpcError{Code: rpcCode(err), Message: err.Error()}
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: rpcErr}
+}
+
+// readRPCMessage reads one message framed by a Content-Length header
+func readRPCMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length < 0 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read message header: %w", err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil || length < 0 {
+				return nil, fmt.Errorf("invalid Content-Length %q", strings.TrimSpace(value))
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("message has no Content-Length header")
+	}
+	if length > maxRequestBytes {
+		return nil, fmt.Errorf("message of %d bytes is too large", length)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("failed to read message body: %w", err)
+	}
+	return body, nil
+}
+
+// writeRPCMessage writes one message framed by a Content-Length header
+func writeRPCMessage(w io.Writer, resp *rpcResponse) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// cmd/scan.go
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/audit"
+	"github.com/jasonKoogler/comma/internal/config"
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/security"
+	"github.com/jasonKoogler/comma/internal/ui"
+)
+
+// enforceSecurityScan scans staged changes for sensitive data before they are
+// sent to the LLM or committed. Blocking findings abort unless the scan was
+// skipped with --skip-scan or the user overrides interactively; overrides are
+// recorded in the audit log. The findings are returned for JSON output.
+func enforceSecurityScan(source git.ChangeSource, changes string) ([]security.Finding, error) {
+	if !appContext.ConfigManager.GetBool(config.SecurityScanSensitiveDataKey) {
+		return nil, nil
+	}
+
+	if skipScan {
+		ui.Notify(ui.NoticeWarning, "Security scan skipped with --skip-scan")
+		recordAuditEvent(source, audit.Event{Action: audit.ActionScanOverride, Status: "skipped"})
+		return nil, nil
+	}
+
+	if repo, ok := source.(*git.Repository); ok {
+		if err := applyAllowlist(repo); err != nil {
+			return nil, err
+		}
+	}
+
+	findings, blocking, err := scanSource(source, changes)
+	if err != nil {
+		return nil, err
+	}
+	if len(findings) == 0 {
+		return findings, nil
+	}
+
+	// Findings are shown even with --quiet unless JSON reports them
+	if !generateJSON {
+		printFindings(findings)
+	}
+
+	if blocking == 0 {
+		ui.Notify(ui.NoticeWarning, "%d finding(s) below %s severity, not blocking", len(findings), blockSeverity())
+		return findings, nil
+	}
+
+	// A dry run reports what would block the commit but sends nothing
+	if dryRun {
+		if !generateJSON {
+			fmt.Printf("⚠️  %d blocking finding(s): a real run would stop here (use --skip-scan to bypass)\n", blocking)
+		}
+		return findings, nil
+	}
+
+	// Never override findings without someone to ask
+	if assumeYes || generateJSON {
+		return findings, fmt.Errorf("%w: commit aborted (use --skip-scan to bypass)", apperrors.ErrSensitiveDataFound)
+	}
+
+	override, err := promptYesNo(fmt.Sprintf("%d finding(s) at %s severity or above detected. Continue anyway?",
+		blocking, blockSeverity()))
+	if err != nil {
+		return findings, fmt.Errorf("%w: commit aborted (use --skip-scan to bypass): %v", apperrors.ErrSensitiveDataFound, err)
+	}
+	if !override {
+		return findings, fmt.Errorf("%w: commit aborted (use --skip-scan to bypass)", apperrors.ErrSensitiveDataFound)
+	}
+
+	recordAuditEvent(source, audit.Event{Action: audit.ActionScanOverride, Status: "overridden", Findings: blocking})
+	return findings, nil
+}
+
+// scanSource scans the changes a source provides. A repository's staged diff
+// is streamed in full, since changes may have been truncated for the prompt.
+func scanSource(source git.ChangeSource, changes string) ([]security.Finding, int, error) {
+	repo, ok := source.(*git.Repository)
+	if !ok {
+		return scanDiff(strings.NewReader(changes))
+	}
+
+	diff, err := repo.StagedDiffReader()
+	if err != nil {
+		return nil, 0, err
+	}
+	findings, blocking, err := scanDiff(diff)
+	if closeErr := diff.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Untracked files sent along are scanned as they appear in the prompt
+	untracked, err := repo.UntrackedDiff()
+	if err != nil || untracked == "" {
+		return findings, blocking, err
+	}
+	more, moreBlocking, err := scanDiff(strings.NewReader(untracked))
+	return append(findings, more...), blocking + moreBlocking, err
+}
+
+// scanDiff scans a diff for findings at or above security.min_severity and
+// counts those at or above security.block_severity, which block a commit
+func scanDiff(diff io.Reader) ([]security.Finding, int, error) {
+	scanner, err := appContext.Scanner()
+	if err != nil {
+		return nil, 0, err
+	}
+	found, err := scanner.ScanReader(diff)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan changes: %w", err)
+	}
+	minSeverity := appContext.ConfigManager.GetString(config.SecurityMinSeverityKey)
+	findings := security.FilterBySeverity(found, minSeverity)
+	blocking := security.FilterBySeverity(findings, appContext.ConfigManager.GetString(config.SecurityBlockSeverityKey))
+	return findings, len(blocking), nil
+}
+
+// blockSeverity names the lowest severity that blocks a commit
+func blockSeverity() string {
+	return strings.ToUpper(appContext.ConfigManager.GetString(config.SecurityBlockSeverityKey))
+}
+
+// applyAllowlist loads the repository's allowlist of known false positives
+// into the scanner
+func applyAllowlist(repo *git.Repository) error {
+	root, err := repo.Root()
+	if err != nil {
+		return nil
+	}
+	allowlist, err := security.LoadAllowlist(filepath.Join(root, security.IgnoreFileName))
+	if err != nil {
+		return err
+	}
+	scanner, err := appContext.Scanner()
+	if err != nil {
+		return err
+	}
+	scanner.SetAllowlist(allowlist)
+	return nil
+}
+
+// printFindings displays security findings with their remediation advice
+func printFindings(findings []security.Finding) {
+	fmt.Println("\n⚠️  Security scan found potentially sensitive data:")
+	for _, f := range findings {
+		content := strings.TrimSpace(f.LineContent)
+		if len(content) > 80 {
+			content = content[:77] + "..."
+		}
+		fmt.Printf("  [%s] %s in %s: %s\n", f.Severity, f.Type, f.FilePath, content)
+		fmt.Printf("         %s\n", f.Suggestion)
+		fmt.Printf("         fingerprint: %s\n", f.Fingerprint)
+	}
+	fmt.Printf("\nFalse positive? Add 'fingerprint:<id>' to %s or a '%s' comment on the line.\n\n",
+		security.IgnoreFileName, security.InlineIgnoreMarker)
+}
@@ -0,0 +1,298 @@
+// cmd/serve.go
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr  string
+	serveToken string
+
+	serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Run a local HTTP API for editor plugins and other tools",
+		Long: `Run a long-lived local server so editor plugins and other tools can reuse a
+warm process with the configuration and credentials already loaded.
+
+Endpoints (JSON in, JSON out):
+  GET  /health    server status and version
+  POST /generate  {"diff": "..."} or {"repo": "/path"} for its staged changes
+  POST /lint      {"message": "..."} or {"range": "v1.0.0..HEAD", "repo": "/path"}
+  POST /analyze   {"repo": "/path", "days": 30}
+
+/generate also accepts a raw diff sent as text/x-diff:
+
+  git diff --cached | curl -H "Authorization: Bearer $COMMA_SERVE_TOKEN" \
+    -H 'Content-Type: text/x-diff' --data-binary @- http://127.0.0.1:7797/generate
+
+Every request needs an "Authorization: Bearer <token>" header. The token is
+--token or COMMA_SERVE_TOKEN, or a random one printed at startup when neither
+is set. The server listens on localhost only unless --addr says otherwise,
+and refuses requests from web pages: any with an Origin header, a Host other
+than localhost or the listening address, or a POST body that is not JSON.`,
+		Args: cobra.NoArgs,
+		RunE: runServe,
+	}
+)
+
+// maxRequestBytes bounds request bodies; diffs larger than this are far
+// beyond what fits in a prompt anyway
+const maxRequestBytes = 10 << 20
+
+// diffContentType marks a raw diff sent to /generate. Unlike text/plain,
+// browsers cannot send it cross-origin without a preflight.
+const diffContentType = "text/x-diff"
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:7797", "address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "bearer token clients must send (default: $COMMA_SERVE_TOKEN)")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	if serveToken == "" {
+		serveToken = os.Getenv("COMMA_SERVE_TOKEN")
+	}
+	generatedToken := serveToken == ""
+	if generatedToken {
+		token, err := newServeToken()
+		if err != nil {
+			return err
+		}
+		serveToken = token
+	}
+
+	if err := validateConfig(); err != nil {
+		return fmt.Errorf("%w\nRun 'comma setup' to configure your LLM provider and API key", err)
+	}
+	if loadActiveTeam() {
+		applyTeamSettings()
+	}
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", serveAddr, err)
+	}
+
+	server := &http.Server{
+		Handler:           serveHandler(serveHosts(listener.Addr())),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Serve(listener) }()
+
+	if !quiet {
+		fmt.Printf("✓ Comma API listening on http://%s (Ctrl+C to stop)\n", listener.Addr())
+	}
+	// Clients cannot connect without the token, so it is shown even with --quiet
+	if generatedToken {
+		fmt.Printf("Token: %s (set COMMA_SERVE_TOKEN to choose one)\n", serveToken)
+	}
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to stop server: %w", err)
+	}
+	return nil
+}
+
+// newServeToken returns a random bearer token for a server started without one
+func newServeToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// serveHosts returns the Host header values the server answers to: localhost
+// and the address it listens on. Other names may be a DNS rebinding attack.
+func serveHosts(addr net.Addr) map[string]bool {
+	hosts := map[string]bool{"localhost": true, "127.0.0.1": true, "::1": true}
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		if ip := net.ParseIP(host); ip != nil && !ip.IsUnspecified() {
+			hosts[host] = true
+		}
+	}
+	return hosts
+}
+
+// checkServeRequest refuses requests a web page could have sent: those with
+// an Origin header, for a host other than ours, or with a POST body a
+// cross-origin form could produce
+func checkServeRequest(r *http.Request, hosts map[string]bool) (int, error) {
+	if r.Header.Get("Origin") != "" {
+		return http.StatusForbidden, errors.New("requests from web pages are not allowed")
+	}
+
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if !hosts[strings.Trim(host, "[]")] {
+		return http.StatusForbidden, fmt.Errorf("host %q is not allowed", r.Host)
+	}
+
+	if r.Method == http.MethodPost {
+		contentType := r.Header.Get("Content-Type")
+		if !strings.HasPrefix(contentType, "application/json") &&
+			!(r.URL.Path == "/generate" && strings.HasPrefix(contentType, diffContentType)) {
+			return http.StatusUnsupportedMediaType, errors.New("Content-Type must be application/json")
+		}
+	}
+	return 0, nil
+}
+
+// serveHandler routes the API endpoints behind the request and token checks
+func serveHandler(hosts map[string]bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeServeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": version})
+	})
+	mux.HandleFunc("POST /generate", serveGenerate)
+	mux.HandleFunc("POST /lint", func(w http.ResponseWriter, r *http.Request) {
+		var req lintRequest
+		if !decodeServeRequest(w, r, &req) {
+			return
+		}
+		resp, err := apiLint(req)
+		writeServeResult(w, resp, err)
+	})
+	mux.HandleFunc("POST /analyze", func(w http.ResponseWriter, r *http.Request) {
+		var req analyzeRequest
+		if !decodeServeRequest(w, r, &req) {
+			return
+		}
+		resp, err := apiAnalyze(req)
+		writeServeResult(w, resp, err)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(logging.TraceHeader, logging.TraceID())
+		if status, err := checkServeRequest(r, hosts); err != nil {
+			writeServeError(w, status, err, nil)
+			return
+		}
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(serveToken)) != 1 {
+			writeServeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"), nil)
+			return
+		}
+		logging.WithFields(logging.WithComponent(appContext.Logger, "serve"),
+			logging.Fields{"method": r.Method, "path": r.URL.Path, "remote": r.RemoteAddr}).Debug("serve: %s %s", r.Method, r.URL.Path)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// serveGenerate handles POST /generate with a JSON request or a raw diff,
+// the content type having been checked by checkServeRequest
+func serveGenerate(w http.ResponseWriter, r *http.Request) {
+	var req generateRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if !decodeServeRequest(w, r, &req) {
+			return
+		}
+	} else {
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+		if err != nil {
+			writeServeError(w, http.StatusBadRequest, fmt.Errorf("failed to read diff: %w", err), nil)
+			return
+		}
+		req.Diff = string(data)
+	}
+
+	// A client that hangs up cancels its request
+	resp, err := apiGenerate(r.Context(), req)
+	if err != nil && resp != nil {
+		// Security findings are reported alongside the error
+		writeServeError(w, serveStatus(err), err, resp.Findings)
+		return
+	}
+	writeServeResult(w, resp, err)
+}
+
+// decodeServeRequest reads a JSON request body, answering 400 when it is invalid
+func decodeServeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		writeServeError(w, http.StatusBadRequest, fmt.Errorf("invalid JSON: %w", err), nil)
+		return false
+	}
+	return true
+}
+
+// writeServeResult writes a successful response or the error in its place
+func writeServeResult(w http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		writeServeError(w, serveStatus(err), err, nil)
+		return
+	}
+	writeServeJSON(w, http.StatusOK, v)
+}
+
+// serveError is the body of every error response
+type serveError struct {
+	Error    string      `json:"error"`
+	Code     int         `json:"code"` // Matches the CLI's exit codes
+	Findings interface{} `json:"findings,omitempty"`
+}
+
+// writeServeError writes an error response
+func writeServeError(w http.ResponseWriter, status int, err error, findings interface{}) {
+	writeServeJSON(w, status, &serveError{Error: err.Error(), Code: apperrors.ExitCode(err), Findings: findings})
+}
+
+// serveStatus maps an error to an HTTP status code
+func serveStatus(err error) int {
+	switch {
+	case errors.Is(err, errInvalidRequest), errors.Is(err, apperrors.ErrGitNoChanges):
+		return http.StatusBadRequest
+	case errors.Is(err, apperrors.ErrSensitiveDataFound):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeServeJSON writes v as a JSON response
+func writeServeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.WithComponent(appContext.Logger, "serve").Warn("serve: failed to write response: %v", err)
+	}
+}
@@ -3,8 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/llm"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 	"golang.org/x/text/cases"
@@ -27,14 +29,31 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration manager not initialized")
 	}
 
+	if !stdinIsTerminal() {
+		return fmt.Errorf("setup is interactive but stdin is not a terminal - use 'comma config set' instead")
+	}
+
 	fmt.Println("Welcome to Comma setup!")
 	fmt.Println("Let's configure your environment.")
 	fmt.Println()
 
-	// Step 1: Choose LLM provider
+	// Step 1: Choose LLM provider; plugin providers follow the built-in ones
+	providers := []string{"openai", "anthropic", "groq", "deepseek", "xai", "local", "local-server"}
+	labels := []string{"OpenAI", "Anthropic", "Groq", "DeepSeek", "xAI (Grok)", "Local", "Local server (LM Studio, llama.cpp, LiteLLM)"}
+	plugins := map[string]llm.ProviderInfo{}
+	for _, info := range llm.RegisteredProviders() {
+		providers = append(providers, info.Name)
+		label := info.Name + " (plugin)"
+		if info.Description != "" {
+			label = fmt.Sprintf("%s - %s (plugin)", info.Name, info.Description)
+		}
+		labels = append(labels, label)
+		plugins[info.Name] = info
+	}
+
 	providerPrompt := promptui.Select{
 		Label: "Select LLM provider",
-		Items: []string{"OpenAI", "Anthropic", "Local"},
+		Items: labels,
 	}
 
 	providerIdx, _, err := providerPrompt.Run()
@@ -42,20 +61,39 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("prompt failed: %w", err)
 	}
 
-	var provider string
-	switch providerIdx {
-	case 0:
-		provider = "openai"
-	case 1:
-		provider = "anthropic"
-	case 2:
-		provider = "local"
+	provider := providers[providerIdx]
+	pluginInfo, isPlugin := plugins[provider]
+
+	// Refuse providers the team does not allow
+	if loadActiveTeam() {
+		teamMgr, err := appContext.TeamManager()
+		if err != nil {
+			return err
+		}
+		if err := teamMgr.CheckProvider(provider); err != nil {
+			return err
+		}
 	}
 
 	appContext.ConfigManager.Set(config.LLMProviderKey, provider)
 
-	// Step 2: Set API key (unless local)
-	if provider != "local" {
+	// Step 2: Set API key (unless local or a plugin provider without one),
+	// or the URL of a local server
+	if provider == "local-server" {
+		endpoint := appContext.ConfigManager.GetString(config.LLMEndpointKey)
+		if !strings.HasPrefix(endpoint, "http://localhost") && !strings.HasPrefix(endpoint, "http://127.0.0.1") {
+			endpoint = llm.DefaultLocalServerURL
+		}
+		urlPrompt := promptui.Prompt{
+			Label:   "Server base URL (e.g. http://localhost:1234/v1 for LM Studio)",
+			Default: endpoint,
+		}
+		endpoint, err := urlPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		appContext.ConfigManager.Set(config.LLMEndpointKey, strings.TrimSpace(endpoint))
+	} else if provider != "local" && (!isPlugin || pluginInfo.RequiresAPIKey) {
 		envVar := config.GetProviderAPIEnvVar(provider)
 
 		// Check if environment variable is set
@@ -86,12 +124,14 @@ func runSetup(cmd *cobra.Command, args []string) error {
 
 			if apiKey != "" {
 				// Only store in credential manager
-				if appContext.CredentialMgr != nil {
-					if err := appContext.CredentialMgr.Store(provider, apiKey); err != nil {
-						fmt.Printf("Warning: Failed to securely store API key: %v\n", err)
-					} else {
-						fmt.Println("API key securely stored in system credentials")
-					}
+				credMgr, err := appContext.CredentialMgr()
+				if err == nil {
+					err = credMgr.Store(provider, apiKey)
+				}
+				if err != nil {
+					fmt.Printf("Warning: Failed to securely store API key: %v\n", err)
+				} else {
+					fmt.Println("API key securely stored in system credentials")
 				}
 			}
 		} else {
@@ -102,6 +142,18 @@ func runSetup(cmd *cobra.Command, args []string) error {
 
 	// Step 3: Select model with comprehensive options
 	models := config.ModelOptions(provider)
+	if isPlugin {
+		models = pluginInfo.Models
+	}
+
+	if len(models) == 0 {
+		modelInput := promptui.Prompt{Label: "Model"}
+		model, err := modelInput.Run()
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		models = []string{model}
+	}
 
 	modelPrompt := promptui.Select{
 		Label: "Select model",
@@ -115,6 +167,17 @@ func runSetup(cmd *cobra.Command, args []string) error {
 
 	appContext.ConfigManager.Set(config.LLMModelKey, models[modelIdx])
 
+	// Step 4: Language of the generated messages
+	languagePrompt := promptui.Prompt{
+		Label:   "Commit message language (e.g. en, es, ja, de)",
+		Default: appContext.ConfigManager.GetString(config.LLMLanguageKey),
+	}
+	lang, err := languagePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("prompt failed: %w", err)
+	}
+	appContext.ConfigManager.Set(config.LLMLanguageKey, strings.TrimSpace(lang))
+
 	// Save the configuration
 	if err := appContext.ConfigManager.Save(); err != nil {
 		return fmt.Errorf("failed to save configuration: %w", err)
@@ -123,6 +186,7 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	fmt.Println("\n✓ Configuration saved successfully!")
 	fmt.Println("Provider:", appContext.ConfigManager.GetString(config.LLMProviderKey))
 	fmt.Println("Model:", appContext.ConfigManager.GetString(config.LLMModelKey))
+	fmt.Println("Language:", messageLanguage())
 
 	// Check if API key is configured
 	apiKey, _ := appContext.GetAPIKey(provider)
@@ -0,0 +1,207 @@
+// cmd/stash.go
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/conventional"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stashDescribeAll    bool
+	stashDescribeDryRun bool
+
+	stashCmd = &cobra.Command{
+		Use:   "stash",
+		Short: "Name and list stashes",
+	}
+
+	stashListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List stashes with their descriptions",
+		Args:  cobra.NoArgs,
+		RunE:  runStashList,
+	}
+
+	stashDescribeCmd = &cobra.Command{
+		Use:   "describe [stash...]",
+		Short: "Replace \"WIP on main\" stash names with generated descriptions",
+		Long: `Replace "WIP on main" stash names with generated descriptions.
+Summarizes each stash's diff in one line and renames the stash to
+"On <branch>: <summary>", keeping the stash list in its order. By default only
+stashes still named by git are described; name stashes (stash@{1} or 1) to
+describe those, or use --all for every stash.
+
+Examples:
+  comma stash describe
+  comma stash describe stash@{2}
+  comma stash describe --all --dry-run`,
+		RunE: runStashDescribe,
+	}
+)
+
+func init() {
+	stashCmd.AddCommand(stashListCmd)
+	stashCmd.AddCommand(stashDescribeCmd)
+
+	stashDescribeCmd.Flags().BoolVar(&stashDescribeAll, "all", false, "describe every stash, including named ones")
+	stashDescribeCmd.Flags().BoolVar(&stashDescribeDryRun, "dry-run", false, "show the descriptions without renaming the stashes")
+	stashDescribeCmd.Flags().BoolVar(&noCache, "no-cache", false, "bypass commit cache")
+	stashDescribeCmd.Flags().BoolVar(&skipScan, "skip-scan", false, "skip security scanning")
+}
+
+func runStashList(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	stashes, err := repo.Stashes()
+	if err != nil {
+		return err
+	}
+	printStashes(stashes)
+	return nil
+}
+
+func runStashDescribe(cmd *cobra.Command, args []string) error {
+	if appContext == nil || appContext.ConfigManager == nil {
+		return fmt.Errorf("configuration manager not initialized")
+	}
+
+	if err := validateConfig(); err != nil {
+		return fmt.Errorf("%w\nRun 'comma setup' to configure your LLM provider and API key", err)
+	}
+
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	stashes, err := repo.Stashes()
+	if err != nil {
+		return err
+	}
+	if len(stashes) == 0 {
+		fmt.Println("No stashes.")
+		return nil
+	}
+
+	selected, err := selectStashes(stashes, args)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		fmt.Println("Every stash is already named; use --all to describe them anyway.")
+		return nil
+	}
+
+	commitService, err := getCommitService()
+	if err != nil {
+		return err
+	}
+
+	messages := make(map[string]string)
+	for _, stash := range selected {
+		diff, err := repo.StashDiff(stash.Ref)
+		if err != nil {
+			return err
+		}
+		message, err := describeDiff(cmd, repo, commitService, stash.Ref, diff, "", true)
+		if err != nil {
+			return err
+		}
+		if message == "" {
+			continue
+		}
+
+		// A stash name is a summary, not a commit header
+		summary, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+		if parsed, err := conventional.Parse(summary); err == nil {
+			summary = parsed.Subject
+		}
+		name := "On " + stash.Branch() + ": " + summary
+		if stash.Branch() == "" {
+			name = summary
+		}
+		messages[stash.Hash] = name
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	// Show the list as it will be
+	renamed := make([]git.Stash, len(stashes))
+	for i, stash := range stashes {
+		if name, ok := messages[stash.Hash]; ok {
+			stash.Message = name
+		}
+		renamed[i] = stash
+	}
+
+	if stashDescribeDryRun {
+		printStashes(renamed)
+		fmt.Println("\nDry run: no stashes were renamed.")
+		return nil
+	}
+
+	if err := repo.RenameStashes(messages); err != nil {
+		return fmt.Errorf("failed to rename stashes: %w", err)
+	}
+	printStashes(renamed)
+	fmt.Printf("\n✓ Renamed %d stash(es)\n", len(messages))
+	return nil
+}
+
+// selectStashes picks the stashes named on the command line, or every
+// unnamed stash (every stash with --all). Stashes may be given as stash@{n}
+// or n.
+func selectStashes(stashes []git.Stash, args []string) ([]git.Stash, error) {
+	if len(args) == 0 {
+		var selected []git.Stash
+		for _, stash := range stashes {
+			if stashDescribeAll || stash.Unnamed() {
+				selected = append(selected, stash)
+			}
+		}
+		return selected, nil
+	}
+
+	byRef := make(map[string]git.Stash, len(stashes))
+	for _, stash := range stashes {
+		byRef[stash.Ref] = stash
+	}
+
+	var selected []git.Stash
+	for _, arg := range args {
+		ref := arg
+		if !strings.HasPrefix(ref, "stash@{") {
+			ref = "stash@{" + arg + "}"
+		}
+		stash, ok := byRef[ref]
+		if !ok {
+			return nil, fmt.Errorf("no stash %s", arg)
+		}
+		selected = append(selected, stash)
+	}
+	return selected, nil
+}
+
+// printStashes prints the stash list like 'git stash list'
+func printStashes(stashes []git.Stash) {
+	if len(stashes) == 0 {
+		fmt.Println("No stashes.")
+		return
+	}
+	for _, stash := range stashes {
+		fmt.Printf("%s: %s\n", stash.Ref, stash.Message)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
 	"github.com/jasonKoogler/comma/internal/update"
 	"github.com/spf13/cobra"
 )
@@ -25,12 +26,16 @@ var (
 	checkOnly   bool
 	setRepoURL  string
 	showRepo    bool
+	rollback    bool
 	updateCmd   = &cobra.Command{
 		Use:   "update",
 		Short: "Check for and install updates",
 		Long: `Check for updates to Comma and optionally install them.
 By default, this command will check for updates and install them if available.
-Use --check-only to only check for updates without installing.`,
+Use --check-only to only check for updates without installing.
+Releases are read from GitHub by default; --set-repo also accepts GitLab and
+Gitea repository or API URLs for internally mirrored releases.
+The replaced binary is kept as comma.bak; use --rollback to restore it.`,
 		RunE: runUpdate,
 	}
 )
@@ -40,6 +45,7 @@ func init() {
 	updateCmd.Flags().BoolVarP(&checkOnly, "check-only", "c", false, "Only check for updates without installing")
 	updateCmd.Flags().StringVar(&setRepoURL, "set-repo", "", "Set a custom repository URL for updates")
 	updateCmd.Flags().BoolVar(&showRepo, "show-repo", false, "Show the current repository URL for updates")
+	updateCmd.Flags().BoolVar(&rollback, "rollback", false, "Restore the version replaced by the last update")
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
@@ -64,7 +70,9 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 			if customRepo == "" {
 				fmt.Println("Using default repository URL: https://api.github.com/repos/jasonKoogler/comma/releases/latest")
 			} else {
+				source, latestURL := update.DetectSource(customRepo)
 				fmt.Printf("Current repository URL: %s\n", customRepo)
+				fmt.Printf("Release source: %s (%s)\n", source, latestURL)
 			}
 		}
 		return nil
@@ -81,10 +89,26 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to save repository URL: %w", err)
 		}
 
-		fmt.Printf("✓ Update repository URL set to: %s\n", setRepoURL)
+		source, _ := update.DetectSource(setRepoURL)
+		fmt.Printf("✓ Update repository URL set to: %s (%s releases)\n", setRepoURL, source)
 		return nil
 	}
 
+	// Rolling back only swaps local files, so it works offline
+	if rollback {
+		restored, err := update.Rollback(configDir, version)
+		if err != nil {
+			return fmt.Errorf("failed to roll back: %w", err)
+		}
+		fmt.Printf("✓ Rolled back to v%s\n", strings.TrimPrefix(restored.Version, "v"))
+		fmt.Println("Run 'comma update --rollback' again to return to the newer version.")
+		return nil
+	}
+
+	if appContext.IsOffline() {
+		return fmt.Errorf("cannot check for updates: %w", apperrors.ErrOffline)
+	}
+
 	checker := update.NewVersionChecker(version, configDir)
 
 	fmt.Println("Checking for updates...")
@@ -129,10 +153,14 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	// Confirm update
 	if !cmd.Flags().Changed("force") {
-		fmt.Print("Do you want to update now? [y/N]: ")
-		var response string
-		fmt.Scanln(&response)
-		if response != "y" && response != "Y" {
+		if err := requireTerminal("Do you want to update now?"); err != nil {
+			return fmt.Errorf("%w - use --force to update without confirmation", err)
+		}
+		confirmed, err := promptYesNo("Do you want to update now?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
 			fmt.Println("Update cancelled.")
 			return nil
 		}
@@ -162,7 +190,7 @@ func performUpdate(info *update.UpdateInfo) error {
 		// Get the download URL for the current platform
 		downloadURL := getDownloadURL(info)
 		if downloadURL != "" {
-			return selfUpdate(execPath, downloadURL)
+			return selfUpdate(execPath, downloadURL, appContext.ConfigDir)
 		}
 	}
 
@@ -233,11 +261,17 @@ func getDownloadURL(info *update.UpdateInfo) string {
 		return "" // Unsupported OS
 	}
 
+	// Mirrors on GitLab or Gitea list their own asset URLs
+	if assetURL, ok := info.Assets[binaryName]; ok {
+		return assetURL
+	}
+
 	return fmt.Sprintf("%s/%s/%s", baseURL, version, binaryName)
 }
 
-// selfUpdate downloads and replaces the current executable with a new version
-func selfUpdate(execPath, downloadURL string) error {
+// selfUpdate downloads and replaces the current executable with a new version,
+// keeping the replaced binary as a backup for 'comma update --rollback'
+func selfUpdate(execPath, downloadURL, configDir string) error {
 	fmt.Printf("Downloading update from %s...\n", downloadURL)
 
 	// Create a temporary directory for the download
@@ -342,6 +376,13 @@ func selfUpdate(execPath, downloadURL string) error {
 
 	fmt.Println("Installing update...")
 
+	backup := &update.Backup{
+		Path:       strings.TrimSuffix(execPath, ".exe") + ".bak",
+		Version:    version,
+		Executable: execPath,
+		CreatedAt:  time.Now(),
+	}
+
 	// On Windows, we can't replace a running executable directly
 	// So we need to use a different approach
 	if runtime.GOOS == "windows" {
@@ -350,8 +391,9 @@ func selfUpdate(execPath, downloadURL string) error {
 		batchContent := fmt.Sprintf(`@echo off
 ping -n 3 127.0.0.1 > nul
 copy /Y "%s" "%s"
+copy /Y "%s" "%s"
 del "%s"
-`, newBinaryPath, execPath, batchFile)
+`, execPath, backup.Path, newBinaryPath, execPath, batchFile)
 
 		if err := os.WriteFile(batchFile, []byte(batchContent), 0755); err != nil {
 			return fmt.Errorf("failed to create update script: %w", err)
@@ -363,26 +405,31 @@ del "%s"
 			return fmt.Errorf("failed to start update script: %w", err)
 		}
 
+		if err := update.SaveBackup(configDir, backup); err != nil {
+			fmt.Printf("⚠️  Rollback will not be available: %v\n", err)
+		}
+
 		fmt.Println("Update will be applied when the application exits.")
 		return nil
 	}
 
 	// On Unix systems, we can replace the binary directly
 	// First, rename the current binary as a backup
-	backupPath := execPath + ".bak"
-	if err := os.Rename(execPath, backupPath); err != nil {
+	if err := os.Rename(execPath, backup.Path); err != nil {
 		return fmt.Errorf("failed to create backup of current binary: %w", err)
 	}
 
 	// Copy the new binary to the original location
 	if err := copyFile(newBinaryPath, execPath); err != nil {
 		// Try to restore the backup if the copy fails
-		os.Rename(backupPath, execPath)
+		os.Rename(backup.Path, execPath)
 		return fmt.Errorf("failed to install new binary: %w", err)
 	}
 
-	// Remove the backup
-	os.Remove(backupPath)
+	// Keep the backup so the update can be rolled back
+	if err := update.SaveBackup(configDir, backup); err != nil {
+		fmt.Printf("⚠️  Rollback will not be available: %v\n", err)
+	}
 
 	fmt.Println("✓ Update successfully installed!")
 	fmt.Println("Please restart Comma to use the new version.")
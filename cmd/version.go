@@ -46,6 +46,7 @@ func printFeatures() {
 		{"Audit Logging", appContext.ConfigManager.GetBool(config.SecurityAuditLoggingKey)},
 		{"Team Integration", appContext.ConfigManager.GetBool(config.TeamEnabledKey)},
 		{"Local Model Fallback", appContext.ConfigManager.GetBool(config.LLMLocalFallbackKey)},
+		{"Provider Racing", appContext.ConfigManager.GetString(config.LLMRaceProvidersKey) != ""},
 	}
 
 	for _, f := range features {
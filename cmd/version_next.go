@@ -0,0 +1,91 @@
+// cmd/version_next.go
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jasonKoogler/comma/internal/conventional"
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nextVersionFrom string
+
+	nextVersionCmd = &cobra.Command{
+		Use:   "next-version",
+		Short: "Compute the next release version from conventional commits",
+		Long: `Compute the next semantic version from the commits since the latest tag,
+using semantic-release's default rules: breaking changes bump the major
+version, feat the minor version, and fix, perf and revert the patch version.
+
+With --quiet only the version is printed, for use in release scripts:
+
+  git tag "$(comma next-version -q)"`,
+		Args: cobra.NoArgs,
+		RunE: runNextVersion,
+	}
+)
+
+func init() {
+	nextVersionCmd.Flags().StringVar(&nextVersionFrom, "from", "", "tag to compute the bump from (default: the latest tag)")
+}
+
+func runNextVersion(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	from := nextVersionFrom
+	if from == "" {
+		from = repo.LatestTag()
+	}
+
+	revRange := "HEAD"
+	if from != "" {
+		revRange = from + "..HEAD"
+	}
+	commits, err := repo.GetCommitRange(revRange)
+	if err != nil {
+		return err
+	}
+
+	messages := make([]string, len(commits))
+	for i, c := range commits {
+		messages[i] = c.Message
+	}
+	bump := conventional.ComputeBump(messages)
+
+	if bump.Kind == conventional.BumpNone {
+		if !quiet {
+			fmt.Printf("No release needed: none of the %d commit(s) since %s are features, fixes or breaking changes.\n",
+				len(commits), describeTag(from))
+		}
+		return nil
+	}
+
+	next, err := conventional.NextVersion(from, bump.Kind)
+	if err != nil {
+		return err
+	}
+
+	if quiet {
+		fmt.Println(next)
+		return nil
+	}
+
+	fmt.Printf("Current version: %s\n", describeTag(from))
+	fmt.Printf("Next version:    %s (%s)\n", next, bump.Kind)
+	fmt.Printf("\n%d breaking change(s), %d feature(s), %d fix(es), %d other commit(s)\n",
+		bump.Breaking, bump.Features, bump.Fixes, bump.Other)
+	return nil
+}
+
+// describeTag names a tag for display, including the untagged case
+func describeTag(tag string) string {
+	if tag == "" {
+		return "(no tags)"
+	}
+	return tag
+}
@@ -9,109 +9,69 @@ import (
 
 // CommitType represents a classification of changes
 type CommitType struct {
-	Type        string  // feat, fix, refactor, etc
-	Scope       string  // component affected
-	Confidence  float64 // 0.0-1.0
-	Description string  // Why this classification
+	Type        string  `json:"type"`        // feat, fix, refactor, etc
+	Scope       string  `json:"scope"`       // component affected
+	Confidence  float64 `json:"confidence"`  // 0.0-1.0
+	Description string  `json:"description"` // Why this classification
 }
 
 // Classifier analyzes changes and suggests commit types
 type Classifier struct {
 	patterns      map[string][]*regexp.Regexp
 	filePatterns  map[string][]*regexp.Regexp
+	descriptions  map[string]string
 	recentCommits []string
+
+	// Learned from recentCommits
+	typeShare map[string]float64 // Share of conventional commits of each type
+	scopes    map[string]int     // How often each scope was used
+
+	packageOf packageScope // Set by UseProjectLayout
 }
 
-// NewClassifier creates a classifier with predefined patterns
+// conventionalSubject matches the "type(scope)!: " of a conventional subject
+var conventionalSubject = regexp.MustCompile(`^([a-z]+)(?:\(([^()]+)\))?!?: `)
+
+// NewClassifier creates a classifier with the built-in patterns. The
+// subjects of the repository's recent commits teach it which types the
+// project uses most and its scope names.
 func NewClassifier(recentCommits []string) *Classifier {
+	return NewClassifierWithPatterns(recentCommits, DefaultPatterns())
+}
+
+// NewClassifierWithPatterns creates a classifier with the given patterns,
+// such as those returned by LoadPatterns. Invalid patterns are skipped.
+func NewClassifierWithPatterns(recentCommits []string, patterns map[string]TypePatterns) *Classifier {
 	c := &Classifier{
 		patterns:      make(map[string][]*regexp.Regexp),
 		filePatterns:  make(map[string][]*regexp.Regexp),
+		descriptions:  make(map[string]string),
 		recentCommits: recentCommits,
+		typeShare:     make(map[string]float64),
+		scopes:        make(map[string]int),
 	}
+	c.learnHistory()
 
-	// Initialize patterns for different commit types
-	c.patterns["feat"] = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)add(ed|ing)?\s+(new|feature)`),
-		regexp.MustCompile(`(?i)(implement|create)\s+new`),
-		regexp.MustCompile(`(?i)introduce`),
-	}
-
-	c.patterns["fix"] = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)fix(ed|ing)?`),
-		regexp.MustCompile(`(?i)(correct|resolve)\s+(bug|issue|problem|error)`),
-		regexp.MustCompile(`(?i)patch`),
-	}
-
-	c.patterns["docs"] = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)document`),
-		regexp.MustCompile(`(?i)readme`),
-		regexp.MustCompile(`\.md$`),
-	}
-
-	c.patterns["style"] = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)format`),
-		regexp.MustCompile(`(?i)style`),
-		regexp.MustCompile(`(?i)whitespace`),
-		regexp.MustCompile(`(?i)indent`),
-	}
-
-	c.patterns["refactor"] = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)refactor`),
-		regexp.MustCompile(`(?i)restructure`),
-		regexp.MustCompile(`(?i)clean(up)?`),
-		regexp.MustCompile(`(?i)simplif(y|ied)`),
-	}
-
-	c.patterns["test"] = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)test`),
-		regexp.MustCompile(`(?i)spec`),
-		regexp.MustCompile(`_test\.go$`),
-		regexp.MustCompile(`test_.+\.py$`),
-	}
-
-	c.patterns["chore"] = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)chore`),
-		regexp.MustCompile(`(?i)dependency`),
-		regexp.MustCompile(`(?i)version bump`),
-		regexp.MustCompile(`(?i)upgrade`),
-		regexp.MustCompile(`package(-lock)?\.json$`),
-		regexp.MustCompile(`go\.(mod|sum)$`),
-	}
-
-	// File patterns by type
-	c.filePatterns["feat"] = []*regexp.Regexp{
-		regexp.MustCompile(`\.go$`),
-		regexp.MustCompile(`\.py$`),
-		regexp.MustCompile(`\.js$`),
-		regexp.MustCompile(`\.ts$`),
-		regexp.MustCompile(`\.rb$`),
-		regexp.MustCompile(`\.java$`),
-	}
-
-	c.filePatterns["docs"] = []*regexp.Regexp{
-		regexp.MustCompile(`\.md$`),
-		regexp.MustCompile(`docs/`),
-		regexp.MustCompile(`README`),
-		regexp.MustCompile(`CONTRIBUTING`),
+	for commitType, typePatterns := range patterns {
+		c.patterns[commitType] = mustCompilePatterns(typePatterns.Content)
+		if len(typePatterns.Files) > 0 {
+			c.filePatterns[commitType] = mustCompilePatterns(typePatterns.Files)
+		}
+		c.descriptions[commitType] = typePatterns.Description
 	}
 
-	c.filePatterns["test"] = []*regexp.Regexp{
-		regexp.MustCompile(`_test\.go$`),
-		regexp.MustCompile(`test_.+\.py$`),
-		regexp.MustCompile(`spec\.js$`),
-		regexp.MustCompile(`/tests?/`),
-	}
+	return c
+}
 
-	c.filePatterns["chore"] = []*regexp.Regexp{
-		regexp.MustCompile(`package(-lock)?\.json$`),
-		regexp.MustCompile(`go\.(mod|sum)$`),
-		regexp.MustCompile(`Makefile`),
-		regexp.MustCompile(`Dockerfile`),
-		regexp.MustCompile(`\.github/`),
+// mustCompilePatterns compiles the valid expressions among exprs
+func mustCompilePatterns(exprs []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		if re, err := regexp.Compile(expr); err == nil {
+			compiled = append(compiled, re)
+		}
 	}
-
-	return c
+	return compiled
 }
 
 // ClassifyChanges analyzes the diff and file paths to suggest commit types
@@ -159,13 +119,26 @@ func (c *Classifier) ClassifyChanges(diff string, files []string) []CommitType {
 		}
 	}
 
-	// Adjust scores based on file operations
+	// Adjust scores based on file operations, for the types in use
+	boost := func(commitType string, by float64) {
+		if _, ok := scores[commitType]; ok {
+			scores[commitType] += by
+		}
+	}
 	if addedFiles > 0 && modifiedFiles == 0 && deletedFiles == 0 {
-		scores["feat"] += 0.3
+		boost("feat", 0.3)
 	} else if deletedFiles > 0 && addedFiles == 0 {
-		scores["refactor"] += 0.3
+		boost("refactor", 0.3)
 	} else if modifiedFiles > 0 && addedFiles == 0 && deletedFiles == 0 {
-		scores["fix"] += 0.2
+		boost("fix", 0.2)
+	}
+
+	// Types the project uses often are more likely. Only types with some
+	// evidence in the changes are boosted, by up to double.
+	for commitType, share := range c.typeShare {
+		if _, ok := scores[commitType]; ok {
+			scores[commitType] *= 1 + share
+		}
 	}
 
 	// Normalize scores
@@ -205,12 +178,41 @@ func (c *Classifier) ClassifyChanges(diff string, files []string) []CommitType {
 	return result
 }
 
-// detectScope tries to determine the component scope from file paths
+// learnHistory counts the types and scopes of the recent conventional commits
+func (c *Classifier) learnHistory() {
+	counts := make(map[string]int)
+	total := 0
+	for _, subject := range c.recentCommits {
+		m := conventionalSubject.FindStringSubmatch(strings.TrimSpace(subject))
+		if m == nil {
+			continue
+		}
+		counts[m[1]]++
+		total++
+		if m[2] != "" {
+			c.scopes[strings.ToLower(m[2])]++
+		}
+	}
+	for commitType, count := range counts {
+		c.typeShare[commitType] = float64(count) / float64(total)
+	}
+}
+
+// detectScope tries to determine the component scope from file paths,
+// preferring scope names the project has used before, then the package the
+// files belong to
 func (c *Classifier) detectScope(files []string) string {
 	if len(files) == 0 {
 		return ""
 	}
 
+	if scope := c.knownScope(files); scope != "" {
+		return scope
+	}
+	if scope := c.packageScopeOf(files); scope != "" {
+		return scope
+	}
+
 	// Count directory components for frequency analysis
 	dirCounts := make(map[string]int)
 
@@ -241,24 +243,43 @@ func (c *Classifier) detectScope(files []string) string {
 	return ""
 }
 
+// knownScope returns the learned scope that names a directory or file of
+// most of the files, e.g. "auth" for internal/auth/session.go, or "" when
+// none does. More used scopes win ties.
+func (c *Classifier) knownScope(files []string) string {
+	if len(c.scopes) == 0 {
+		return ""
+	}
+
+	votes := make(map[string]int)
+	for _, file := range files {
+		seen := make(map[string]bool)
+		for _, part := range strings.Split(strings.ToLower(file), "/") {
+			name, _, _ := strings.Cut(part, ".")
+			if _, ok := c.scopes[name]; ok && !seen[name] {
+				seen[name] = true
+				votes[name]++
+			}
+		}
+	}
+
+	best := ""
+	for scope, count := range votes {
+		if best == "" || count > votes[best] || (count == votes[best] && c.scopes[scope] > c.scopes[best]) ||
+			(count == votes[best] && c.scopes[scope] == c.scopes[best] && scope < best) {
+			best = scope
+		}
+	}
+	if best != "" && float64(votes[best]) > float64(len(files))*0.5 {
+		return best
+	}
+	return ""
+}
+
 // getDescription returns a human-readable explanation for classification
 func (c *Classifier) getDescription(commitType string, confidence float64) string {
-	switch commitType {
-	case "feat":
-		return "New functionality appears to be added"
-	case "fix":
-		return "Changes look like bug fixes"
-	case "docs":
-		return "Documentation files were modified"
-	case "style":
-		return "Code style or formatting changes"
-	case "refactor":
-		return "Code restructuring without functionality change"
-	case "test":
-		return "Test files were modified"
-	case "chore":
-		return "Maintenance changes to build or dependencies"
-	default:
-		return ""
+	if description := c.descriptions[commitType]; description != "" {
+		return description
 	}
+	return "Changes match the " + commitType + " patterns"
 }
@@ -0,0 +1,38 @@
+// internal/analysis/classifier_bench_test.go
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchmarkChanges builds a diff and file list for files changed files
+func benchmarkChanges(files, lines int) (string, []string) {
+	var sb strings.Builder
+	paths := make([]string, files)
+	for f := 0; f < files; f++ {
+		paths[f] = fmt.Sprintf("internal/pkg%d/file%d.go", f%20, f)
+		fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", paths[f], paths[f])
+		for l := 0; l < lines; l++ {
+			fmt.Fprintf(&sb, "+// fix the nil check in handler %d\n", l)
+			fmt.Fprintf(&sb, "+func added%d(x int) int { return x * %d }\n", l, l)
+		}
+	}
+	return sb.String(), paths
+}
+
+func BenchmarkClassifyChanges(b *testing.B) {
+	history := []string{"feat(api): add endpoint", "fix(cli): handle empty input", "docs: update README"}
+	for _, size := range []struct{ files, lines int }{{10, 50}, {100, 100}} {
+		diff, files := benchmarkChanges(size.files, size.lines)
+		b.Run(fmt.Sprintf("files=%d/lines=%d", size.files, size.lines), func(b *testing.B) {
+			classifier := NewClassifier(history)
+			b.SetBytes(int64(len(diff)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				classifier.ClassifyChanges(diff, files)
+			}
+		})
+	}
+}
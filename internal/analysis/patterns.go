@@ -0,0 +1,147 @@
+// internal/analysis/patterns.go
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PatternsFile is the name of the classifier patterns file in the config dir
+const PatternsFile = "classifier.yaml"
+
+// TypePatterns are the regular expressions that suggest one commit type
+type TypePatterns struct {
+	Description string   `yaml:"description,omitempty"`
+	Content     []string `yaml:"content,omitempty"` // Matched against the diff
+	Files       []string `yaml:"files,omitempty"`   // Matched against changed file paths
+}
+
+// patternsFileHeader explains the file written with the default patterns
+const patternsFileHeader = `# Patterns comma uses to suggest a commit type. Each match in the diff adds
+# to a type's score, as does each changed file matching one of its files
+# patterns. Add types such as infra or db, or edit the defaults below; a type
+# listed here replaces the built-in patterns for it.
+`
+
+// DefaultPatterns returns the built-in patterns
+func DefaultPatterns() map[string]TypePatterns {
+	return map[string]TypePatterns{
+		"feat": {
+			Description: "New functionality appears to be added",
+			Content: []string{
+				`(?i)add(ed|ing)?\s+(new|feature)`,
+				`(?i)(implement|create)\s+new`,
+				`(?i)introduce`,
+			},
+			Files: []string{`\.go$`, `\.py$`, `\.js$`, `\.ts$`, `\.rb$`, `\.java$`},
+		},
+		"fix": {
+			Description: "Changes look like bug fixes",
+			Content: []string{
+				`(?i)fix(ed|ing)?`,
+				`(?i)(correct|resolve)\s+(bug|issue|problem|error)`,
+				`(?i)patch`,
+			},
+		},
+		"docs": {
+			Description: "Documentation files were modified",
+			Content:     []string{`(?i)document`, `(?i)readme`, `\.md$`},
+			Files:       []string{`\.md$`, `docs/`, `README`, `CONTRIBUTING`},
+		},
+		"style": {
+			Description: "Code style or formatting changes",
+			Content:     []string{`(?i)format`, `(?i)style`, `(?i)whitespace`, `(?i)indent`},
+		},
+		"refactor": {
+			Description: "Code restructuring without functionality change",
+			Content:     []string{`(?i)refactor`, `(?i)restructure`, `(?i)clean(up)?`, `(?i)simplif(y|ied)`},
+		},
+		"test": {
+			Description: "Test files were modified",
+			Content:     []string{`(?i)test`, `(?i)spec`, `_test\.go$`, `test_.+\.py$`},
+			Files:       []string{`_test\.go$`, `test_.+\.py$`, `spec\.js$`, `/tests?/`},
+		},
+		"chore": {
+			Description: "Maintenance changes to build or dependencies",
+			Content: []string{
+				`(?i)chore`,
+				`(?i)dependency`,
+				`(?i)version bump`,
+				`(?i)upgrade`,
+				`package(-lock)?\.json$`,
+				`go\.(mod|sum)$`,
+			},
+			Files: []string{`package(-lock)?\.json$`, `go\.(mod|sum)$`, `Makefile`, `Dockerfile`, `\.github/`},
+		},
+	}
+}
+
+// LoadPatterns reads the classifier patterns from the config dir, merged
+// over the defaults. When the file does not exist it is created with the
+// defaults, so there is something to edit.
+func LoadPatterns(configDir string) (map[string]TypePatterns, error) {
+	patterns := DefaultPatterns()
+	if configDir == "" {
+		return patterns, nil
+	}
+	path := filepath.Join(configDir, PatternsFile)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return patterns, writeDefaultPatterns(path)
+	}
+	if err != nil {
+		return patterns, fmt.Errorf("failed to read classifier patterns: %w", err)
+	}
+
+	var file struct {
+		Types map[string]TypePatterns `yaml:"types"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return patterns, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for commitType, typePatterns := range file.Types {
+		if _, err := compilePatterns(typePatterns.Content); err != nil {
+			return DefaultPatterns(), fmt.Errorf("invalid content pattern for %s in %s: %w", commitType, path, err)
+		}
+		if _, err := compilePatterns(typePatterns.Files); err != nil {
+			return DefaultPatterns(), fmt.Errorf("invalid files pattern for %s in %s: %w", commitType, path, err)
+		}
+		patterns[commitType] = typePatterns
+	}
+	return patterns, nil
+}
+
+// writeDefaultPatterns writes the default patterns to path
+func writeDefaultPatterns(path string) error {
+	data, err := yaml.Marshal(map[string]interface{}{"types": DefaultPatterns()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal classifier patterns: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append([]byte(patternsFileHeader), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write classifier patterns: %w", err)
+	}
+	return nil
+}
+
+// compilePatterns compiles regular expressions, failing on the first
+// invalid one
+func compilePatterns(exprs []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
@@ -0,0 +1,115 @@
+// internal/analysis/scope.go
+package analysis
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// packageScope returns the package a file belongs to, or "" when the
+// project's layout does not say
+type packageScope func(file string) string
+
+// UseProjectLayout lets the classifier take scopes from the packages the
+// changed files belong to: Go package clauses, JavaScript workspace packages
+// and Python packages. Files are relative to root, the top of the work tree.
+func (c *Classifier) UseProjectLayout(projectType, root string) {
+	switch projectType {
+	case "Go":
+		c.packageOf = func(file string) string { return goPackage(root, file) }
+	case "JavaScript/Node.js":
+		c.packageOf = func(file string) string { return workspacePackage(root, file) }
+	case "Python":
+		c.packageOf = func(file string) string { return pythonPackage(root, file) }
+	}
+}
+
+// packageScopeOf returns the package most of the files belong to, or ""
+func (c *Classifier) packageScopeOf(files []string) string {
+	if c.packageOf == nil {
+		return ""
+	}
+
+	votes := make(map[string]int)
+	for _, file := range files {
+		// Renames are listed as "old -> new"
+		if _, renamed, ok := strings.Cut(file, " -> "); ok {
+			file = renamed
+		}
+		if pkg := c.packageOf(file); pkg != "" {
+			votes[pkg]++
+		}
+	}
+
+	best := ""
+	for pkg, count := range votes {
+		if best == "" || count > votes[best] || (count == votes[best] && pkg < best) {
+			best = pkg
+		}
+	}
+	if best != "" && float64(votes[best]) > float64(len(files))*0.5 {
+		return best
+	}
+	return ""
+}
+
+// goPackage reads the package clause of a Go file. Commands are named after
+// their directory, since every one of them is package main, and external
+// test packages after the package they test.
+func goPackage(root, file string) string {
+	if !strings.HasSuffix(file, ".go") {
+		return ""
+	}
+	parsed, err := parser.ParseFile(token.NewFileSet(), filepath.Join(root, filepath.FromSlash(file)), nil, parser.PackageClauseOnly)
+	if err != nil {
+		// Deleted or unparsable files fall back to their directory
+		return ""
+	}
+
+	name := strings.TrimSuffix(parsed.Name.Name, "_test")
+	if name == "main" {
+		if dir := path.Base(path.Dir(file)); dir != "." {
+			return dir
+		}
+	}
+	return name
+}
+
+// workspacePackage returns the name of the nearest package.json above a
+// file, without its @scope/, when that is a workspace package rather than
+// the repository's own
+func workspacePackage(root, file string) string {
+	for dir := path.Dir(file); dir != "."; dir = path.Dir(dir) {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(dir), "package.json"))
+		if err != nil {
+			continue
+		}
+		var manifest struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(data, &manifest) != nil || manifest.Name == "" {
+			return path.Base(dir)
+		}
+		if i := strings.LastIndex(manifest.Name, "/"); i >= 0 {
+			return manifest.Name[i+1:]
+		}
+		return manifest.Name
+	}
+	return ""
+}
+
+// pythonPackage returns the innermost package, a directory with an
+// __init__.py, that holds a file
+func pythonPackage(root, file string) string {
+	for dir := path.Dir(file); dir != "."; dir = path.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(dir), "__init__.py")); err == nil {
+			return path.Base(dir)
+		}
+	}
+	return ""
+}
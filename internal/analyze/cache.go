@@ -0,0 +1,96 @@
+// internal/analyze/cache.go
+package analyze
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/git"
+)
+
+// historyCacheTTL is how long unused history cache files are kept
+const historyCacheTTL = 7 * 24 * time.Hour
+
+// historyCacheEntry stores the parsed commits for one HEAD and analysis window
+type historyCacheEntry struct {
+	Head    string       `json:"head"`
+	Window  string       `json:"window"`
+	Commits []git.Commit `json:"commits"`
+}
+
+// SetCacheDir enables caching of parsed commit history in dir. Since history
+// below a given HEAD never changes, repeated analyses of the same window are
+// served from the cache until new commits are made. An empty dir disables caching.
+func (s *Service) SetCacheDir(dir string) {
+	s.cacheDir = dir
+}
+
+// commitHistory returns the commits in the window, using the history cache when possible
+func (s *Service) commitHistory(repo *git.Repository, since, until time.Time) ([]git.Commit, error) {
+	if s.cacheDir == "" {
+		return repo.GetCommitHistoryBetween(since, until)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		// Unborn branches have no history worth caching
+		return repo.GetCommitHistoryBetween(since, until)
+	}
+
+	// Windows are matched by day, as git's --since is day-granular already
+	root, _ := repo.Root()
+	window := since.Format("2006-01-02") + ".." + until.Format("2006-01-02")
+	path := s.historyCachePath(root, head, window)
+
+	if data, err := os.ReadFile(path); err == nil {
+		var entry historyCacheEntry
+		if json.Unmarshal(data, &entry) == nil && entry.Head == head && entry.Window == window {
+			now := time.Now()
+			os.Chtimes(path, now, now)
+			return entry.Commits, nil
+		}
+	}
+
+	commits, err := repo.GetCommitHistoryBetween(since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	// Caching is best effort; a failed write only costs the next run a re-parse
+	if data, err := json.Marshal(historyCacheEntry{Head: head, Window: window, Commits: commits}); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			os.WriteFile(path, data, 0644)
+		}
+	}
+	s.pruneHistoryCache()
+
+	return commits, nil
+}
+
+// historyCachePath returns the cache file for a repository, HEAD and window
+func (s *Service) historyCachePath(root, head, window string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{root, head, window}, "\x00")))
+	return filepath.Join(s.cacheDir, "history", hex.EncodeToString(sum[:16])+".json")
+}
+
+// pruneHistoryCache removes cache files that have not been used recently,
+// since every new HEAD leaves an entry for the previous one behind
+func (s *Service) pruneHistoryCache() {
+	dir := filepath.Join(s.cacheDir, "history")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err == nil && time.Since(info.ModTime()) > historyCacheTTL {
+			os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// internal/analyze/export.go
+package analyze
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/ui"
+)
+
+// Export formats supported by Export
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+// Export writes the analysis result in a machine-readable format
+func Export(w io.Writer, result *AnalysisResult, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case FormatCSV:
+		return exportCSV(w, result)
+	case FormatMarkdown, "md":
+		return exportMarkdown(w, result)
+	default:
+		return fmt.Errorf("unsupported export format: %s (use json, csv or markdown)", format)
+	}
+}
+
+// exportCSV writes one row per statistic as section,key,value so the
+// output can be loaded into a spreadsheet or dashboard as a single table
+func exportCSV(w io.Writer, result *AnalysisResult) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"section", "key", "value"},
+		{"summary", "since", result.Since.Format("2006-01-02")},
+		{"summary", "until", result.Until.Format("2006-01-02")},
+		{"summary", "days", strconv.Itoa(result.Days)},
+		{"summary", "total_commits", strconv.Itoa(result.TotalCommits)},
+		{"summary", "contributors", strconv.Itoa(len(result.AuthorStats))},
+		{"summary", "conventional_percent", strconv.FormatFloat(result.ConventionalPercent, 'f', 1, 64)},
+	}
+	for _, author := range result.AuthorFilter {
+		rows = append(rows, []string{"filter", "author", author})
+	}
+	for _, key := range sortedKeys(result.CommitStats) {
+		rows = append(rows, []string{"type", key, strconv.Itoa(result.CommitStats[key])})
+	}
+	for _, key := range sortedKeys(result.AuthorStats) {
+		rows = append(rows, []string{"author", key, strconv.Itoa(result.AuthorStats[key])})
+	}
+	for _, key := range sortedKeys(result.ScopeStats) {
+		rows = append(rows, []string{"scope", key, strconv.Itoa(result.ScopeStats[key])})
+	}
+	for _, day := range result.DailyCommits {
+		rows = append(rows, []string{"daily", day.Date, strconv.Itoa(day.Count)})
+	}
+	for _, author := range sortedKeys(result.AuthorStats) {
+		grid := result.Heatmap[author]
+		if grid == nil {
+			continue
+		}
+		for day, hours := range grid {
+			for hour, count := range hours {
+				if count > 0 {
+					key := fmt.Sprintf("%s/%s/%02d", author, Weekdays[day], hour)
+					rows = append(rows, []string{"heatmap", key, strconv.Itoa(count)})
+				}
+			}
+		}
+	}
+	if len(result.CommitSizes) > 0 {
+		dist := result.SizeDistribution()
+		rows = append(rows,
+			[]string{"size", "median", strconv.Itoa(dist.Median)},
+			[]string{"size", "p75", strconv.Itoa(dist.P75)},
+			[]string{"size", "p90", strconv.Itoa(dist.P90)},
+			[]string{"size", "p95", strconv.Itoa(dist.P95)},
+			[]string{"size", "max", strconv.Itoa(dist.Max)},
+		)
+		for _, outlier := range dist.Outliers {
+			rows = append(rows, []string{"outlier", outlier.Hash, strconv.Itoa(outlier.Lines())})
+		}
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// exportMarkdown writes the result as a Markdown report for CI summaries
+func exportMarkdown(w io.Writer, result *AnalysisResult) error {
+	fmt.Fprintf(w, "# Repository Analysis\n\n")
+	fmt.Fprintf(w, "| Metric | Value |\n|---|---|\n")
+	fmt.Fprintf(w, "| Time period | %s |\n", result.Period())
+	if len(result.AuthorFilter) > 0 {
+		fmt.Fprintf(w, "| Authors matching | %s |\n", strings.Join(result.AuthorFilter, ", "))
+	}
+	fmt.Fprintf(w, "| Total commits | %d |\n", result.TotalCommits)
+	fmt.Fprintf(w, "| Contributors | %d |\n", len(result.AuthorStats))
+	fmt.Fprintf(w, "| Conventional commits | %.1f%% |\n", result.ConventionalPercent)
+	fmt.Fprintf(w, "| Activity | `%s` |\n", ui.Sparkline(result.Counts(), 60))
+
+	fmt.Fprintf(w, "\n## Commit Types\n\n| Type | Commits |\n|---|---|\n")
+	for _, key := range sortedKeys(result.CommitStats) {
+		fmt.Fprintf(w, "| %s | %d |\n", key, result.CommitStats[key])
+	}
+
+	if len(result.ScopeStats) > 0 {
+		fmt.Fprintf(w, "\n## Scopes\n\n| Scope | Commits |\n|---|---|\n")
+		for _, scope := range result.TopScopes(0) {
+			fmt.Fprintf(w, "| %s | %d |\n", scope.Scope, scope.Count)
+		}
+	}
+
+	if len(result.CommitSizes) > 0 {
+		dist := result.SizeDistribution()
+		fmt.Fprintf(w, "\n## Commit Sizes\n\nLines changed per commit: median %d, p75 %d, p90 %d, p95 %d, max %d\n",
+			dist.Median, dist.P75, dist.P90, dist.P95, dist.Max)
+		if len(dist.Outliers) > 0 {
+			fmt.Fprintf(w, "\n| Commit | Subject | Author | Lines |\n|---|---|---|---|\n")
+			for _, outlier := range dist.Outliers {
+				fmt.Fprintf(w, "| %s | %s | %s | +%d/-%d |\n",
+					shortHash(outlier.Hash), outlier.Subject, outlier.Author, outlier.Additions, outlier.Deletions)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\n## Authors\n\n| Author | Commits |\n|---|---|\n")
+	for _, key := range sortedKeys(result.AuthorStats) {
+		fmt.Fprintf(w, "| %s | %d |\n", key, result.AuthorStats[key])
+	}
+
+	if len(result.Heatmap) > 0 {
+		fmt.Fprintf(w, "\n## Activity Heatmap\n")
+		for _, author := range sortedKeys(result.AuthorStats) {
+			if grid := result.Heatmap[author]; grid != nil {
+				fmt.Fprintf(w, "\n### %s\n\n```\n%s```\n", author, ui.Heatmap(Weekdays, grid.Rows()))
+			}
+		}
+	}
+
+	return nil
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+// sortedKeys returns map keys ordered by descending count, then name
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
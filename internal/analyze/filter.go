@@ -0,0 +1,40 @@
+// internal/analyze/filter.go
+package analyze
+
+import (
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/git"
+)
+
+// Filter narrows an analysis to some of the commits in its window. It is
+// applied after the history is read, so filtered runs share the cache.
+type Filter struct {
+	Authors []string // Parts of author names, matched case-insensitively; empty for everyone
+}
+
+// apply returns the commits that pass the filter
+func (f Filter) apply(commits []git.Commit) []git.Commit {
+	if len(f.Authors) == 0 {
+		return commits
+	}
+
+	kept := make([]git.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if f.matchesAuthor(commit.Author) {
+			kept = append(kept, commit)
+		}
+	}
+	return kept
+}
+
+// matchesAuthor reports whether an author name contains one of the filter's
+func (f Filter) matchesAuthor(author string) bool {
+	author = strings.ToLower(author)
+	for _, want := range f.Authors {
+		if strings.Contains(author, strings.ToLower(want)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+// internal/analyze/heatmap.go
+package analyze
+
+import "time"
+
+// ActivityGrid counts commits by weekday (Monday first) and hour of day, in local time
+type ActivityGrid [7][24]int
+
+// Add records a commit made at the given time
+func (g *ActivityGrid) Add(t time.Time) {
+	t = t.Local()
+	g[(int(t.Weekday())+6)%7][t.Hour()]++
+}
+
+// Rows returns the grid as slices for charting
+func (g *ActivityGrid) Rows() [][]int {
+	rows := make([][]int, len(g))
+	for i := range g {
+		rows[i] = g[i][:]
+	}
+	return rows
+}
+
+// Weekdays labels the rows of an ActivityGrid
+var Weekdays = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
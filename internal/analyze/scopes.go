@@ -0,0 +1,114 @@
+// internal/analyze/scopes.go
+package analyze
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/team"
+)
+
+// ScopeCount is how often a conventional commit scope was used
+type ScopeCount struct {
+	Scope string `json:"scope"`
+	Count int    `json:"count"`
+}
+
+// ScopeDrift is a scope used only once, with the frequent scope it most likely meant
+type ScopeDrift struct {
+	Scope      string `json:"scope"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// TopScopes returns the most used scopes, most frequent first
+func (r *AnalysisResult) TopScopes(limit int) []ScopeCount {
+	scopes := make([]ScopeCount, 0, len(r.ScopeStats))
+	for _, scope := range sortedKeys(r.ScopeStats) {
+		scopes = append(scopes, ScopeCount{Scope: scope, Count: r.ScopeStats[scope]})
+	}
+	if limit > 0 && len(scopes) > limit {
+		scopes = scopes[:limit]
+	}
+	return scopes
+}
+
+// ScopeDrift returns scopes that appear only once. These are often typos or
+// variants of an established scope, which is suggested when one is close enough.
+func (r *AnalysisResult) ScopeDrift() []ScopeDrift {
+	canonical := r.CanonicalScopes()
+
+	var drift []ScopeDrift
+	for scope, count := range r.ScopeStats {
+		if count != 1 {
+			continue
+		}
+
+		d := ScopeDrift{Scope: scope}
+		best := 3 // Only suggest scopes within two edits
+		for _, candidate := range canonical {
+			if distance := levenshtein(strings.ToLower(scope), strings.ToLower(candidate)); distance < best {
+				best = distance
+				d.Suggestion = candidate
+			}
+		}
+		drift = append(drift, d)
+	}
+
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Scope < drift[j].Scope })
+	return drift
+}
+
+// CanonicalScopes returns the scopes used more than once, alphabetically,
+// as a starting point for a team's allowed scope list
+func (r *AnalysisResult) CanonicalScopes() []string {
+	var scopes []string
+	for scope, count := range r.ScopeStats {
+		if count > 1 {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// ScopeConventionCheck builds a team convention check that only accepts the
+// given scopes, ready to paste into a team configuration
+func ScopeConventionCheck(scopes []string) team.ConventionCheck {
+	quoted := make([]string, len(scopes))
+	for i, scope := range scopes {
+		quoted[i] = regexp.QuoteMeta(scope)
+	}
+
+	return team.ConventionCheck{
+		Name:        "allowed-scopes",
+		Description: "Conventional commit scopes must come from the team's scope list",
+		Regex:       fmt.Sprintf(`^[a-z]+(\((%s)\))?!?: `, strings.Join(quoted, "|")),
+		Required:    true,
+		ErrorMsg:    fmt.Sprintf("Scope must be one of: %s", strings.Join(scopes, ", ")),
+	}
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
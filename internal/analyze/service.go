@@ -3,24 +3,80 @@ package analyze
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/jasonKoogler/comma/internal/conventional"
 	"github.com/jasonKoogler/comma/internal/git"
 )
 
 // AnalysisResult represents the output of a repository analysis
 type AnalysisResult struct {
-	CommitStats         map[string]int // Statistics about commit types
-	AuthorStats         map[string]int // Statistics about repository authors
-	TotalCommits        int            // Total number of commits analyzed
-	ConventionalPercent float64        // Percentage of conventional commits
+	Since               time.Time                `json:"since"`                   // Start of the analysis window
+	Until               time.Time                `json:"until"`                   // End of the analysis window
+	Days                int                      `json:"days"`                    // Length of the analysis window
+	CommitStats         map[string]int           `json:"commit_types"`            // Statistics about commit types
+	AuthorStats         map[string]int           `json:"authors"`                 // Statistics about repository authors
+	ScopeStats          map[string]int           `json:"scopes"`                  // Statistics about conventional commit scopes
+	TotalCommits        int                      `json:"total_commits"`           // Total number of commits analyzed
+	ConventionalPercent float64                  `json:"conventional_percent"`    // Percentage of conventional commits
+	DailyCommits        []DailyCount             `json:"daily_commits"`           // Commits per day, oldest first
+	CommitSizes         []CommitSize             `json:"commit_sizes"`            // Lines changed per commit, newest first
+	Heatmap             map[string]*ActivityGrid `json:"heatmap"`                 // Weekday by hour activity per author
+	AuthorFilter        []string                 `json:"author_filter,omitempty"` // Only commits by these authors were analyzed
+}
+
+// DailyCount is the number of commits made on one day
+type DailyCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD in local time
+	Count int    `json:"count"`
+}
+
+// Period describes the analysis window for reports
+func (r *AnalysisResult) Period() string {
+	if time.Since(r.Until) < time.Hour {
+		return fmt.Sprintf("Last %d days", r.Days)
+	}
+	return fmt.Sprintf("%s to %s", r.Since.Format("2006-01-02"), r.Until.Format("2006-01-02"))
+}
+
+// Counts returns the daily commit counts as a plain series
+func (r *AnalysisResult) Counts() []int {
+	counts := make([]int, len(r.DailyCommits))
+	for i, day := range r.DailyCommits {
+		counts[i] = day.Count
+	}
+	return counts
+}
+
+// conventionalPattern matches the type and optional scope of a conventional
+// commit with one of the project's types. It is rebuilt when the types change.
+var (
+	conventionalPattern   *regexp.Regexp
+	conventionalPatternMu sync.Mutex
+)
+
+// typePattern returns conventionalPattern for the current types
+func typePattern() *regexp.Regexp {
+	conventionalPatternMu.Lock()
+	defer conventionalPatternMu.Unlock()
+	if pattern := conventional.TypePattern(); conventionalPattern == nil || conventionalPattern.String() != pattern {
+		conventionalPattern = regexp.MustCompile(pattern)
+	}
+	return conventionalPattern
+}
+
+// IsConventional reports whether a commit message follows the conventional commit format
+func IsConventional(message string) bool {
+	return typePattern().MatchString(message)
 }
 
 // Service provides repository analysis functionality
 type Service struct {
-	// Any dependencies can be added here
+	cacheDir string // Where parsed commit history is cached, empty to disable
 }
 
 // NewService creates a new analyze service
@@ -28,32 +84,66 @@ func NewService() *Service {
 	return &Service{}
 }
 
-// AnalyzeRepository analyzes the repository's commit history
+// AnalyzeRepository analyzes the repository's commit history for the last number of days
 func (s *Service) AnalyzeRepository(repo *git.Repository, days int) (*AnalysisResult, error) {
+	now := time.Now()
+	return s.AnalyzeRange(repo, now.AddDate(0, 0, -days), now)
+}
+
+// AnalyzeRange analyzes the repository's commit history between two times
+func (s *Service) AnalyzeRange(repo *git.Repository, since, until time.Time) (*AnalysisResult, error) {
+	return s.AnalyzeFiltered(repo, since, until, Filter{})
+}
+
+// AnalyzeFiltered analyzes the commits between two times that pass the filter
+func (s *Service) AnalyzeFiltered(repo *git.Repository, since, until time.Time, filter Filter) (*AnalysisResult, error) {
+	if !since.Before(until) {
+		return nil, fmt.Errorf("analysis window start %s is not before its end %s",
+			since.Format("2006-01-02"), until.Format("2006-01-02"))
+	}
+
 	// Get commit history for specified time period
-	since := time.Now().AddDate(0, 0, -days)
-	commits, err := repo.GetCommitHistory(since)
+	commits, err := s.commitHistory(repo, since, until)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit history: %w", err)
 	}
+	commits = filter.apply(commits)
 
 	// Initialize maps to track statistics
 	typeCounts := make(map[string]int)   // Count commits by type
 	authorsCount := make(map[string]int) // Count commits by author
+	dayCounts := make(map[string]int)    // Count commits by day
+	scopeCounts := make(map[string]int)  // Count commits by scope
 	conventionalCount := 0
-
-	// Conventional commit pattern regex
-	conventionalPattern := regexp.MustCompile(`^(feat|fix|docs|style|refactor|perf|test|build|ci|chore|revert)(\([a-zA-Z0-9_-]+\))?:`)
+	sizes := make([]CommitSize, 0, len(commits))
+	heatmap := make(map[string]*ActivityGrid)
+	commitPattern := typePattern()
 
 	// Analyze each commit for conventional commit patterns and author stats
 	for _, commit := range commits {
-		// Track commit count by author
+		// Track commit count by author and day
 		authorsCount[commit.Author]++
+		if heatmap[commit.Author] == nil {
+			heatmap[commit.Author] = &ActivityGrid{}
+		}
+		heatmap[commit.Author].Add(commit.Date)
+		dayCounts[commit.Date.Local().Format("2006-01-02")]++
+		sizes = append(sizes, CommitSize{
+			Hash:      commit.Hash,
+			Subject:   commit.Message,
+			Author:    commit.Author,
+			Additions: commit.Additions,
+			Deletions: commit.Deletions,
+		})
 
 		// Check if it follows conventional format
-		if conventionalPattern.MatchString(commit.Message) {
+		if match := commitPattern.FindStringSubmatch(commit.Message); match != nil {
 			conventionalCount++
 
+			if scope := strings.Trim(match[2], "()"); scope != "" {
+				scopeCounts[scope]++
+			}
+
 			// Extract type and scope
 			parts := strings.SplitN(commit.Message, ":", 2)
 			typeScope := parts[0]
@@ -80,9 +170,30 @@ func (s *Service) AnalyzeRepository(repo *git.Repository, days int) (*AnalysisRe
 	}
 
 	return &AnalysisResult{
+		Since:               since,
+		Until:               until,
+		Days:                int(math.Ceil(until.Sub(since).Hours() / 24)),
 		CommitStats:         typeCounts,
 		AuthorStats:         authorsCount,
+		ScopeStats:          scopeCounts,
 		TotalCommits:        len(commits),
 		ConventionalPercent: conventionalPercent,
+		DailyCommits:        dailySeries(since, until, dayCounts),
+		CommitSizes:         sizes,
+		Heatmap:             heatmap,
+		AuthorFilter:        filter.Authors,
 	}, nil
 }
+
+// dailySeries lists every day from start to end with its commit count,
+// including days without commits so the series can be charted directly
+func dailySeries(start, end time.Time, counts map[string]int) []DailyCount {
+	var series []DailyCount
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+	for !day.After(end) {
+		key := day.Format("2006-01-02")
+		series = append(series, DailyCount{Date: key, Count: counts[key]})
+		day = day.AddDate(0, 0, 1)
+	}
+	return series
+}
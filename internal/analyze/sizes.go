@@ -0,0 +1,78 @@
+// internal/analyze/sizes.go
+package analyze
+
+import (
+	"math"
+	"sort"
+)
+
+// CommitSize is the number of lines a commit changed
+type CommitSize struct {
+	Hash      string `json:"hash"`
+	Subject   string `json:"subject"`
+	Author    string `json:"author"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// Lines returns the total number of changed lines
+func (c CommitSize) Lines() int {
+	return c.Additions + c.Deletions
+}
+
+// SizeDistribution summarizes how large commits are, in changed lines
+type SizeDistribution struct {
+	Median   int          `json:"median"`
+	P75      int          `json:"p75"`
+	P90      int          `json:"p90"`
+	P95      int          `json:"p95"`
+	Max      int          `json:"max"`
+	Outliers []CommitSize `json:"outliers"` // Mega-commits, largest first
+}
+
+// minOutlierLines keeps small repositories from flagging ordinary commits as outliers
+const minOutlierLines = 500
+
+// SizeDistribution computes commit size percentiles and lists outliers.
+// A commit is an outlier when it is far above the upper quartile
+// (more than three interquartile ranges) and changes at least minOutlierLines lines.
+func (r *AnalysisResult) SizeDistribution() SizeDistribution {
+	if len(r.CommitSizes) == 0 {
+		return SizeDistribution{}
+	}
+
+	lines := make([]int, len(r.CommitSizes))
+	for i, size := range r.CommitSizes {
+		lines[i] = size.Lines()
+	}
+	sort.Ints(lines)
+
+	dist := SizeDistribution{
+		Median: percentile(lines, 50),
+		P75:    percentile(lines, 75),
+		P90:    percentile(lines, 90),
+		P95:    percentile(lines, 95),
+		Max:    lines[len(lines)-1],
+	}
+
+	fence := dist.P75 + 3*(dist.P75-percentile(lines, 25))
+	for _, size := range r.CommitSizes {
+		if size.Lines() > fence && size.Lines() >= minOutlierLines {
+			dist.Outliers = append(dist.Outliers, size)
+		}
+	}
+	sort.Slice(dist.Outliers, func(i, j int) bool {
+		return dist.Outliers[i].Lines() > dist.Outliers[j].Lines()
+	})
+
+	return dist
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []int, p float64) int {
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
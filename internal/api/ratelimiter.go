@@ -38,6 +38,18 @@ func NewRateLimiter() *RateLimiter {
 			RequestsPerMinute: 100, // 100 requests per minute
 			BurstSize:         10,  // Allow bursts of 10
 		},
+		"groq": {
+			RequestsPerMinute: 30, // Free tier limit
+			BurstSize:         5,  // Allow bursts of 5
+		},
+		"deepseek": {
+			RequestsPerMinute: 60, // No published limit, stay polite
+			BurstSize:         5,  // Allow bursts of 5
+		},
+		"xai": {
+			RequestsPerMinute: 60, // 60 requests per minute
+			BurstSize:         5,  // Allow bursts of 5
+		},
 		"local": {
 			RequestsPerMinute: 200, // Higher limit for local models
 			BurstSize:         20,  // Larger burst size
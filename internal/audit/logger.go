@@ -2,29 +2,49 @@
 package audit
 
 import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 // Event represents an audit log entry
 type Event struct {
-	Timestamp   time.Time `json:"timestamp"`
-	User        string    `json:"user"`
-	Action      string    `json:"action"`
-	Provider    string    `json:"provider,omitempty"`
-	RepoName    string    `json:"repo_name,omitempty"`
-	TokensUsed  int       `json:"tokens_used,omitempty"`
-	Status      string    `json:"status"`
-	Error       string    `json:"error,omitempty"`
-	IP          string    `json:"ip,omitempty"`
-	Environment string    `json:"environment,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+	User         string    `json:"user"`
+	Action       string    `json:"action"`
+	Provider     string    `json:"provider,omitempty"`
+	RepoName     string    `json:"repo_name,omitempty"`
+	TokensUsed   int       `json:"tokens_used,omitempty"`
+	PromptTokens int       `json:"prompt_tokens,omitempty"`
+	OutputTokens int       `json:"completion_tokens,omitempty"`
+	CostUSD      float64   `json:"cost_usd,omitempty"` // Estimated from list prices
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	Conventional bool      `json:"conventional,omitempty"`
+	Findings     int       `json:"findings,omitempty"`
+	IP           string    `json:"ip,omitempty"`
+	Environment  string    `json:"environment,omitempty"`
+	TraceID      string    `json:"trace_id,omitempty"` // Matches the invocation's log lines
 }
 
+// Audit actions
+const (
+	ActionGenerate = "generate"
+	ActionCommit   = "commit"
+
+	// ActionScanOverride records a commit that proceeded despite blocking scan findings
+	ActionScanOverride = "scan-override"
+)
+
 // Logger handles audit logging
 type Logger struct {
+	logDir  string
 	logPath string
 	enabled bool
 }
@@ -39,11 +59,17 @@ func NewLogger(configDir string) (*Logger, error) {
 	logPath := filepath.Join(logDir, fmt.Sprintf("%s-audit.log", time.Now().Format("2006-01")))
 
 	return &Logger{
+		logDir:  logDir,
 		logPath: logPath,
 		enabled: true,
 	}, nil
 }
 
+// SetEnabled turns audit logging on or off
+func (l *Logger) SetEnabled(enabled bool) {
+	l.enabled = enabled
+}
+
 // LogEvent records an audit event
 func (l *Logger) LogEvent(event Event) error {
 	if !l.enabled {
@@ -82,17 +108,186 @@ func (l *Logger) LogEvent(event Event) error {
 	return err
 }
 
-// GetUsageReport generates usage statistics
-func (l *Logger) GetUsageReport(days int) (map[string]interface{}, error) {
-	// Implementation to parse logs and generate usage report
-	return map[string]interface{}{
-		"total_requests": 120,
-		"total_tokens":   45600,
-		"avg_tokens":     380,
-		"by_provider": map[string]int{
-			"openai":    78,
-			"anthropic": 34,
-			"local":     8,
-		},
+// UsageReport summarizes audit events over a period
+type UsageReport struct {
+	Days                int            `json:"days"`
+	Members             int            `json:"members"`
+	TotalRequests       int            `json:"total_requests"`
+	TotalTokens         int            `json:"total_tokens"`
+	AvgTokens           int            `json:"avg_tokens"`
+	CostUSD             float64        `json:"cost_usd"`
+	ByProvider          map[string]int `json:"by_provider"`
+	Commits             int            `json:"commits"`
+	ConventionalCommits int            `json:"conventional_commits"`
+}
+
+// ConventionalRate returns the percentage of recorded commits that followed
+// the conventional commit format
+func (r *UsageReport) ConventionalRate() float64 {
+	if r.Commits == 0 {
+		return 0
+	}
+	return float64(r.ConventionalCommits) / float64(r.Commits) * 100
+}
+
+// UsageExport is an anonymized usage report that team members can share
+type UsageExport struct {
+	Member     string      `json:"member"` // Random per-install ID; only the newest export per member is merged
+	ExportedAt time.Time   `json:"exported_at"`
+	Report     UsageReport `json:"report"`
+}
+
+// GetUsageReport generates usage statistics from the audit logs of the last days
+func (l *Logger) GetUsageReport(days int) (*UsageReport, error) {
+	events, err := l.readEvents(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UsageReport{
+		Days:       days,
+		Members:    1,
+		ByProvider: make(map[string]int),
+	}
+
+	for _, event := range events {
+		switch event.Action {
+		case ActionGenerate:
+			// Messages served from the cache never reached the provider
+			if event.Status == "cached" {
+				continue
+			}
+			report.TotalRequests++
+			report.TotalTokens += event.TokensUsed
+			report.CostUSD += event.CostUSD
+			if event.Provider != "" {
+				report.ByProvider[event.Provider]++
+			}
+		case ActionCommit:
+			report.Commits++
+			if event.Conventional {
+				report.ConventionalCommits++
+			}
+		}
+	}
+
+	if report.TotalRequests > 0 {
+		report.AvgTokens = report.TotalTokens / report.TotalRequests
+	}
+
+	return report, nil
+}
+
+// memberIDFile holds the random ID that identifies this install in usage
+// exports
+const memberIDFile = "member-id"
+
+// ExportAnonymized produces a usage report that identifies the member only by
+// a random per-install ID, with no repository names, messages, or errors
+func (l *Logger) ExportAnonymized(days int) (*UsageExport, error) {
+	report, err := l.GetUsageReport(days)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := l.memberID()
+	if err != nil {
+		return nil, err
+	}
+	return &UsageExport{
+		Member:     member,
+		ExportedAt: time.Now(),
+		Report:     *report,
 	}, nil
 }
+
+// memberID returns this install's member ID, creating it on first use. Unlike
+// a hash of the user's email, it cannot be matched against a team roster.
+func (l *Logger) memberID() (string, error) {
+	path := filepath.Join(l.logDir, memberIDFile)
+	if data, err := os.ReadFile(path); err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate member ID: %w", err)
+	}
+	id := hex.EncodeToString(buf)
+	if err := os.WriteFile(path, []byte(id+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write member ID: %w", err)
+	}
+	return id, nil
+}
+
+// MergeUsageExports combines member exports into a team-wide report. Only
+// the newest export from each member is counted, since exports cover
+// overlapping periods.
+func MergeUsageExports(exports []UsageExport) *UsageReport {
+	latest := make(map[string]UsageExport)
+	for _, export := range exports {
+		if current, ok := latest[export.Member]; !ok || export.ExportedAt.After(current.ExportedAt) {
+			latest[export.Member] = export
+		}
+	}
+
+	team := &UsageReport{ByProvider: make(map[string]int)}
+	for _, export := range latest {
+		if export.Report.Days > team.Days {
+			team.Days = export.Report.Days
+		}
+		team.TotalRequests += export.Report.TotalRequests
+		team.TotalTokens += export.Report.TotalTokens
+		team.CostUSD += export.Report.CostUSD
+		team.Commits += export.Report.Commits
+		team.ConventionalCommits += export.Report.ConventionalCommits
+		for provider, count := range export.Report.ByProvider {
+			team.ByProvider[provider] += count
+		}
+	}
+
+	team.Members = len(latest)
+	if team.TotalRequests > 0 {
+		team.AvgTokens = team.TotalTokens / team.TotalRequests
+	}
+
+	return team
+}
+
+// readEvents reads all audit events recorded since the given time
+func (l *Logger) readEvents(since time.Time) ([]Event, error) {
+	files, err := filepath.Glob(filepath.Join(l.logDir, "*-audit.log"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	var events []Event
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var event Event
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				// Skip malformed lines rather than failing the whole report
+				continue
+			}
+			if event.Timestamp.Before(since) {
+				continue
+			}
+			events = append(events, event)
+		}
+		f.Close()
+
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+	}
+
+	return events, nil
+}
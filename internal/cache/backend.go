@@ -0,0 +1,82 @@
+// internal/cache/backend.go
+package cache
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// Cache backend names accepted by cache.backend
+const (
+	BackendFile   = "file"
+	BackendSQLite = "sqlite"
+	BackendRedis  = "redis"
+)
+
+// Backend stores cache entries by key
+type Backend interface {
+	// Get returns the entry for a key, or nil if there is none
+	Get(key string) (*CacheEntry, error)
+	// Put stores an entry. Backends with native expiry may use ttl.
+	Put(key string, entry *CacheEntry, ttl time.Duration) error
+	Delete(key string) error
+	List() ([]StoredEntry, error)
+	// Remote reports whether the backend needs network access
+	Remote() bool
+	Close() error
+}
+
+// StoredEntry is a cache entry with its key and approximate storage size
+type StoredEntry struct {
+	Key   string
+	Entry *CacheEntry
+	Size  int64
+}
+
+// BackendOptions configures the optional cache backends
+type BackendOptions struct {
+	SQLitePath string
+	RedisURL   string
+}
+
+// UseBackend switches the cache to the named backend. The file backend,
+// one JSON file per entry in the cache directory, is the default.
+func (c *CommitCache) UseBackend(name string, opts BackendOptions) error {
+	var backend Backend
+
+	switch name {
+	case "", BackendFile:
+		backend = &fileBackend{dir: c.cacheDir}
+	case BackendSQLite:
+		path := opts.SQLitePath
+		if path == "" {
+			path = filepath.Join(c.cacheDir, "cache.db")
+		}
+		sqlite, err := newSQLiteBackend(path)
+		if err != nil {
+			return err
+		}
+		backend = sqlite
+	case BackendRedis:
+		redis, err := newRedisBackend(opts.RedisURL)
+		if err != nil {
+			return err
+		}
+		backend = redis
+	default:
+		return fmt.Errorf("unknown cache backend: %s (supported: %s, %s, %s)",
+			name, BackendFile, BackendSQLite, BackendRedis)
+	}
+
+	if c.backend != nil {
+		c.backend.Close()
+	}
+	c.backend = backend
+	return nil
+}
+
+// Close releases the backend's resources
+func (c *CommitCache) Close() error {
+	return c.backend.Close()
+}
@@ -4,7 +4,6 @@ package cache
 import (
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,6 +15,8 @@ type CommitCache struct {
 	cacheDir string
 	maxAge   time.Duration
 	enabled  bool
+	backend  Backend
+	offline  bool
 }
 
 // CacheEntry represents a cached commit message
@@ -28,6 +29,7 @@ type CacheEntry struct {
 		Additions    int `json:"additions"`
 		Deletions    int `json:"deletions"`
 	} `json:"stats"`
+	Fingerprint []uint32 `json:"fingerprint,omitempty"`
 }
 
 // NewCommitCache creates a new commit message cache
@@ -41,45 +43,59 @@ func NewCommitCache(configDir string) (*CommitCache, error) {
 		cacheDir: cacheDir,
 		maxAge:   24 * time.Hour, // Cache entries expire after 24 hours
 		enabled:  true,
+		backend:  &fileBackend{dir: cacheDir},
 	}, nil
 }
 
+// SetEnabled turns caching on or off
+func (c *CommitCache) SetEnabled(enabled bool) {
+	c.enabled = enabled
+}
+
+// SetMaxAge sets how long cache entries remain valid
+func (c *CommitCache) SetMaxAge(maxAge time.Duration) {
+	if maxAge > 0 {
+		c.maxAge = maxAge
+	}
+}
+
+// SetOffline disables a networked cache backend so that no lookups leave the machine
+func (c *CommitCache) SetOffline(offline bool) {
+	c.offline = offline
+}
+
+// active reports whether the cache should be consulted
+func (c *CommitCache) active() bool {
+	return c.enabled && !(c.offline && c.backend.Remote())
+}
+
 // Get retrieves a cached commit message if available
 func (c *CommitCache) Get(changes string) (*CacheEntry, error) {
-	if !c.enabled {
+	if !c.active() {
 		return nil, nil
 	}
 
 	key := c.generateKey(changes)
-	cachePath := filepath.Join(c.cacheDir, key+".json")
 
-	// Check if cache file exists and is not expired
-	info, err := os.Stat(cachePath)
-	if os.IsNotExist(err) {
+	entry, err := c.backend.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		c.recordLookup(false)
 		return nil, nil
-	} else if err != nil {
-		return nil, fmt.Errorf("failed to check cache file: %w", err)
 	}
 
 	// Check if cache is expired
-	if time.Since(info.ModTime()) > c.maxAge {
+	if c.expired(entry) {
 		// Clean up expired cache entry
-		os.Remove(cachePath)
+		c.backend.Delete(key)
+		c.recordLookup(false)
 		return nil, nil
 	}
 
-	// Read cache file
-	data, err := os.ReadFile(cachePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read cache file: %w", err)
-	}
-
-	var entry CacheEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
-		return nil, fmt.Errorf("failed to parse cache entry: %w", err)
-	}
-
-	return &entry, nil
+	c.recordLookup(true)
+	return entry, nil
 }
 
 // Set stores a commit message in cache
@@ -88,17 +104,15 @@ func (c *CommitCache) Set(changes string, message string, provider string, stats
 	Additions    int
 	Deletions    int
 }) error {
-	if !c.enabled {
+	if !c.active() {
 		return nil
 	}
 
-	key := c.generateKey(changes)
-	cachePath := filepath.Join(c.cacheDir, key+".json")
-
 	entry := CacheEntry{
-		Message:   message,
-		CreatedAt: time.Now(),
-		Provider:  provider,
+		Message:     message,
+		CreatedAt:   time.Now(),
+		Provider:    provider,
+		Fingerprint: diffFingerprint(changes),
 		Stats: struct {
 			ChangedFiles int `json:"changed_files"`
 			Additions    int `json:"additions"`
@@ -110,43 +124,38 @@ func (c *CommitCache) Set(changes string, message string, provider string, stats
 		},
 	}
 
-	data, err := json.MarshalIndent(entry, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal cache entry: %w", err)
-	}
-
-	if err := os.WriteFile(cachePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write cache file: %w", err)
-	}
-
-	return nil
+	return c.backend.Put(c.generateKey(changes), &entry, c.maxAge)
 }
 
 // Cleanup removes expired cache entries
 func (c *CommitCache) Cleanup() error {
-	entries, err := os.ReadDir(c.cacheDir)
+	_, err := c.removeEntries(true)
+	return err
+}
+
+// removeEntries deletes cache entries, optionally only expired ones,
+// and returns how many were removed
+func (c *CommitCache) removeEntries(expiredOnly bool) (int, error) {
+	entries, err := c.backend.List()
 	if err != nil {
-		return fmt.Errorf("failed to read cache directory: %w", err)
+		return 0, err
 	}
 
-	now := time.Now()
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
-
-		if now.Sub(info.ModTime()) > c.maxAge {
-			cachePath := filepath.Join(c.cacheDir, entry.Name())
-			os.Remove(cachePath)
+	removed := 0
+	for _, stored := range entries {
+		if !expiredOnly || c.expired(stored.Entry) {
+			if err := c.backend.Delete(stored.Key); err == nil {
+				removed++
+			}
 		}
 	}
 
-	return nil
+	return removed, nil
+}
+
+// expired reports whether an entry is older than the cache's maximum age
+func (c *CommitCache) expired(entry *CacheEntry) bool {
+	return time.Since(entry.CreatedAt) > c.maxAge
 }
 
 // generateKey creates a cache key from changes
@@ -0,0 +1,97 @@
+// internal/cache/file.go
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fileBackend stores each entry as a JSON file in the cache directory
+type fileBackend struct {
+	dir string
+}
+
+// Get reads the entry file for a key
+func (b *fileBackend) Get(key string) (*CacheEntry, error) {
+	data, err := os.ReadFile(b.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse cache entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// Put writes the entry file for a key
+func (b *fileBackend) Put(key string, entry *CacheEntry, ttl time.Duration) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := os.WriteFile(b.path(key), data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the entry file for a key
+func (b *fileBackend) Delete(key string) error {
+	return os.Remove(b.path(key))
+}
+
+// List reads every entry file in the cache directory
+func (b *fileBackend) List() ([]StoredEntry, error) {
+	files, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []StoredEntry
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		key := strings.TrimSuffix(file.Name(), ".json")
+		entry, err := b.Get(key)
+		if err != nil || entry == nil {
+			continue
+		}
+
+		var size int64
+		if info, err := file.Info(); err == nil {
+			size = info.Size()
+		}
+
+		entries = append(entries, StoredEntry{Key: key, Entry: entry, Size: size})
+	}
+
+	return entries, nil
+}
+
+// Remote reports that the file backend is local
+func (b *fileBackend) Remote() bool {
+	return false
+}
+
+// Close is a no-op for the file backend
+func (b *fileBackend) Close() error {
+	return nil
+}
+
+// path returns the file path for a key
+func (b *fileBackend) path(key string) string {
+	return filepath.Join(b.dir, key+".json")
+}
@@ -0,0 +1,119 @@
+// internal/cache/redis.go
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix namespaces cache keys in a shared Redis instance
+const redisKeyPrefix = "comma:cache:"
+
+// redisBackend stores entries in Redis so runners sharing an instance share
+// cached messages. Entries carry a TTL so Redis expires them on its own.
+type redisBackend struct {
+	client *redis.Client
+}
+
+// newRedisBackend connects to Redis using a redis:// or rediss:// URL
+func newRedisBackend(url string) (*redisBackend, error) {
+	if url == "" {
+		return nil, fmt.Errorf("redis cache backend requires cache.redis_url")
+	}
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	return &redisBackend{client: redis.NewClient(opts)}, nil
+}
+
+// Get reads the entry for a key
+func (b *redisBackend) Get(key string) (*CacheEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := b.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry from redis: %w", err)
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse cache entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// Put stores the entry for a key with the given TTL
+func (b *redisBackend) Put(key string, entry *CacheEntry, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := b.client.Set(ctx, redisKeyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to write cache entry to redis: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the entry for a key
+func (b *redisBackend) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	return b.client.Del(ctx, redisKeyPrefix+key).Err()
+}
+
+// List scans for every cache entry in the instance
+func (b *redisBackend) List() ([]StoredEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var entries []StoredEntry
+	iter := b.client.Scan(ctx, 0, redisKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		data, err := b.client.Get(ctx, iter.Val()).Bytes()
+		if err != nil {
+			continue
+		}
+
+		var entry CacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+
+		key := strings.TrimPrefix(iter.Val(), redisKeyPrefix)
+		entries = append(entries, StoredEntry{Key: key, Entry: &entry, Size: int64(len(data))})
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list cache entries in redis: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Remote reports that Redis is reached over the network
+func (b *redisBackend) Remote() bool {
+	return true
+}
+
+// Close closes the Redis connection
+func (b *redisBackend) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,122 @@
+// internal/cache/similarity.go
+package cache
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// Similar is a cached entry whose diff resembles the one being looked up
+type Similar struct {
+	Entry      *CacheEntry
+	Similarity float64
+}
+
+// FindSimilar returns the unexpired cached entry whose normalized diff is most
+// similar to the given changes, if its similarity is at least threshold (0-1).
+// Similarity is the Jaccard index of the sets of changed lines, so small edits
+// to a previously seen diff still match.
+func (c *CommitCache) FindSimilar(changes string, threshold float64) (*Similar, error) {
+	matches, err := c.SimilarEntries(changes, threshold, 1)
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	return &matches[0], nil
+}
+
+// SimilarEntries returns up to limit entries at or above the threshold, most
+// similar first. Near matches that are not close enough to reuse can still
+// serve as examples for the LLM.
+func (c *CommitCache) SimilarEntries(changes string, threshold float64, limit int) ([]Similar, error) {
+	target := diffFingerprint(changes)
+	if len(target) == 0 {
+		return nil, nil
+	}
+
+	if !c.active() {
+		return nil, nil
+	}
+
+	entries, err := c.backend.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Similar
+	for _, stored := range entries {
+		entry := stored.Entry
+		if c.expired(entry) || len(entry.Fingerprint) == 0 {
+			continue
+		}
+
+		if similarity := jaccard(target, entry.Fingerprint); similarity >= threshold {
+			matches = append(matches, Similar{Entry: entry, Similarity: similarity})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches, nil
+}
+
+// diffFingerprint hashes each changed line of a diff after normalizing
+// whitespace, ignoring file headers and context lines. Only hashes are
+// stored, so the fingerprint never contains source code.
+func diffFingerprint(changes string) []uint32 {
+	seen := make(map[uint32]bool)
+	var fingerprint []uint32
+
+	for _, line := range strings.Split(changes, "\n") {
+		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+			continue
+		}
+		if !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		normalized := line[:1] + strings.Join(strings.Fields(line[1:]), " ")
+		if len(normalized) == 1 {
+			continue
+		}
+
+		h := fnv.New32a()
+		h.Write([]byte(normalized))
+		sum := h.Sum32()
+		if !seen[sum] {
+			seen[sum] = true
+			fingerprint = append(fingerprint, sum)
+		}
+	}
+
+	return fingerprint
+}
+
+// jaccard returns the Jaccard index of two fingerprints
+func jaccard(a, b []uint32) float64 {
+	set := make(map[uint32]bool, len(a))
+	for _, h := range a {
+		set[h] = true
+	}
+
+	intersection := 0
+	union := len(set)
+	for _, h := range b {
+		if set[h] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
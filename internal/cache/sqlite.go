@@ -0,0 +1,116 @@
+// internal/cache/sqlite.go
+package cache
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteBackend stores entries in a single SQLite database. Message, provider
+// and creation time are kept in their own columns so the cache can be queried
+// directly, e.g. with the sqlite3 shell.
+type sqliteBackend struct {
+	db *sql.DB
+}
+
+// newSQLiteBackend opens (and if needed creates) the cache database
+func newSQLiteBackend(path string) (*sqliteBackend, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS entries (
+		key        TEXT PRIMARY KEY,
+		message    TEXT NOT NULL,
+		provider   TEXT,
+		created_at TIMESTAMP NOT NULL,
+		data       TEXT NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize cache database: %w", err)
+	}
+
+	return &sqliteBackend{db: db}, nil
+}
+
+// Get reads the entry for a key
+func (b *sqliteBackend) Get(key string) (*CacheEntry, error) {
+	var data string
+	err := b.db.QueryRow(`SELECT data FROM entries WHERE key = ?`, key).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse cache entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// Put inserts or replaces the entry for a key
+func (b *sqliteBackend) Put(key string, entry *CacheEntry, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	_, err = b.db.Exec(`INSERT OR REPLACE INTO entries (key, message, provider, created_at, data)
+		VALUES (?, ?, ?, ?, ?)`, key, entry.Message, entry.Provider, entry.CreatedAt, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the entry for a key
+func (b *sqliteBackend) Delete(key string) error {
+	_, err := b.db.Exec(`DELETE FROM entries WHERE key = ?`, key)
+	return err
+}
+
+// List returns every entry in the database
+func (b *sqliteBackend) List() ([]StoredEntry, error) {
+	rows, err := b.db.Query(`SELECT key, data FROM entries`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []StoredEntry
+	for rows.Next() {
+		var key, data string
+		if err := rows.Scan(&key, &data); err != nil {
+			continue
+		}
+
+		var entry CacheEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			continue
+		}
+
+		entries = append(entries, StoredEntry{Key: key, Entry: &entry, Size: int64(len(key) + len(data))})
+	}
+
+	return entries, rows.Err()
+}
+
+// Remote reports that SQLite is a local file
+func (b *sqliteBackend) Remote() bool {
+	return false
+}
+
+// Close closes the database
+func (b *sqliteBackend) Close() error {
+	return b.db.Close()
+}
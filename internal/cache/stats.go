@@ -0,0 +1,96 @@
+// internal/cache/stats.go
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// statsFile holds the lookup counters inside the cache directory
+const statsFile = "stats.meta"
+
+// Stats summarizes cache usage and contents
+type Stats struct {
+	Hits        int       `json:"hits"`
+	Misses      int       `json:"misses"`
+	Entries     int       `json:"-"`
+	Expired     int       `json:"-"`
+	DiskUsage   int64     `json:"-"`
+	OldestEntry time.Time `json:"-"`
+}
+
+// HitRate returns the fraction of lookups that were served from the cache
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Stats returns lookup counters and a summary of the cached entries
+func (c *CommitCache) Stats() (*Stats, error) {
+	stats := c.loadCounters()
+
+	entries, err := c.backend.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stored := range entries {
+		stats.Entries++
+		stats.DiskUsage += stored.Size
+		if c.expired(stored.Entry) {
+			stats.Expired++
+		}
+		if stats.OldestEntry.IsZero() || stored.Entry.CreatedAt.Before(stats.OldestEntry) {
+			stats.OldestEntry = stored.Entry.CreatedAt
+		}
+	}
+
+	return &stats, nil
+}
+
+// Clear removes all cache entries, or only expired ones, and returns how many
+// were removed. Clearing everything also resets the hit and miss counters.
+func (c *CommitCache) Clear(expiredOnly bool) (int, error) {
+	removed, err := c.removeEntries(expiredOnly)
+	if err != nil {
+		return removed, err
+	}
+
+	if !expiredOnly {
+		os.Remove(filepath.Join(c.cacheDir, statsFile))
+	}
+
+	return removed, nil
+}
+
+// recordLookup updates the hit or miss counter. Counter failures are ignored
+// since they must never break message generation.
+func (c *CommitCache) recordLookup(hit bool) {
+	stats := c.loadCounters()
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+	os.WriteFile(filepath.Join(c.cacheDir, statsFile), data, 0644)
+}
+
+// loadCounters reads the persisted hit and miss counters
+func (c *CommitCache) loadCounters() Stats {
+	var stats Stats
+	data, err := os.ReadFile(filepath.Join(c.cacheDir, statsFile))
+	if err == nil {
+		json.Unmarshal(data, &stats)
+	}
+	return stats
+}
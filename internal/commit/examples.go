@@ -0,0 +1,67 @@
+// internal/commit/examples.go
+package commit
+
+import (
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/git"
+)
+
+// fewShotExamplesKey sets how many earlier messages for the same files are
+// shown to the model as examples of the project's voice; 0 turns them off
+const fewShotExamplesKey = "llm.few_shot_examples"
+
+// maxExampleLength keeps long commit bodies from crowding out the diff
+const maxExampleLength = 600
+
+// fewShotExamples returns recent commit messages for the files being
+// committed, formatted for the prompt, or "" when there are none. Patches
+// have no history to draw on.
+func (s *Service) fewShotExamples(source git.ChangeSource) string {
+	n := s.configProvider.GetInt(fewShotExamplesKey)
+	repo, ok := source.(*git.Repository)
+	if n <= 0 || !ok {
+		return ""
+	}
+
+	files, err := repo.GetChangedFiles()
+	if err != nil || len(files) == 0 {
+		return ""
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+
+	// Read extra commits so skipped ones don't leave too few examples
+	messages, err := repo.RecentMessages(n*2, paths...)
+	if err != nil {
+		s.logger.Debug("No commit history for few-shot examples: %v", err)
+		return ""
+	}
+
+	var examples []string
+	seen := make(map[string]bool)
+	for _, message := range messages {
+		subject, _, _ := strings.Cut(message, "\n")
+		if seen[subject] || strings.HasPrefix(subject, "fixup!") || strings.HasPrefix(subject, "squash!") {
+			continue
+		}
+		seen[subject] = true
+
+		if len(message) > maxExampleLength {
+			message = strings.TrimSpace(message[:maxExampleLength]) + "\n[...]"
+		}
+		examples = append(examples, message)
+		if len(examples) == n {
+			break
+		}
+	}
+	if len(examples) == 0 {
+		return ""
+	}
+
+	s.logger.Debug("Including %d earlier commit message(s) as examples", len(examples))
+	return "Earlier commit messages for these files, as examples of the project's style:\n\n" +
+		strings.Join(examples, "\n\n---\n\n")
+}
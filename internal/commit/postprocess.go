@@ -0,0 +1,211 @@
+// internal/commit/postprocess.go
+package commit
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// postProcessKey turns off the clean-up of generated messages
+const postProcessKey = "llm.post_process"
+
+// bodyWidth is the column commit bodies are wrapped at
+const bodyWidth = 72
+
+// imperativeVerbs maps the past tense and third person forms models like to
+// start subjects with to the imperative
+var imperativeVerbs = map[string]string{
+	"added": "add", "adds": "add", "adding": "add",
+	"allowed": "allow", "allows": "allow",
+	"built": "build", "builds": "build",
+	"bumped": "bump", "bumps": "bump",
+	"changed": "change", "changes": "change",
+	"cleaned": "clean", "cleans": "clean",
+	"corrected": "correct", "corrects": "correct",
+	"created": "create", "creates": "create",
+	"deleted": "delete", "deletes": "delete",
+	"disabled": "disable", "disables": "disable",
+	"documented": "document", "documents": "document",
+	"dropped": "drop", "drops": "drop",
+	"enabled": "enable", "enables": "enable",
+	"ensured": "ensure", "ensures": "ensure",
+	"extracted": "extract", "extracts": "extract",
+	"fixed": "fix", "fixes": "fix", "fixing": "fix",
+	"handled": "handle", "handles": "handle",
+	"implemented": "implement", "implements": "implement",
+	"improved": "improve", "improves": "improve",
+	"introduced": "introduce", "introduces": "introduce",
+	"made": "make", "makes": "make",
+	"merged": "merge", "merges": "merge",
+	"moved": "move", "moves": "move",
+	"optimized": "optimize", "optimizes": "optimize",
+	"prevented": "prevent", "prevents": "prevent",
+	"refactored": "refactor", "refactors": "refactor",
+	"removed": "remove", "removes": "remove", "removing": "remove",
+	"renamed": "rename", "renames": "rename",
+	"replaced": "replace", "replaces": "replace",
+	"reverted": "revert", "reverts": "revert",
+	"simplified": "simplify", "simplifies": "simplify",
+	"supported": "support", "supports": "support",
+	"updated": "update", "updates": "update", "updating": "update",
+	"upgraded": "upgrade", "upgrades": "upgrade",
+	"used": "use", "uses": "use",
+	"wrote": "write", "writes": "write",
+}
+
+// subjectPrefix matches the "type(scope)!: " before a conventional subject
+var subjectPrefix = regexp.MustCompile(`^[A-Za-z]+(?:\([^()]+\))?!?: `)
+
+// footerLine matches git trailers such as "BREAKING CHANGE: ..." or
+// "Refs #12", which must stay on one line
+var footerLine = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z][\w-]*)(: | #)`)
+
+// listItem matches the marker of a bullet or numbered list item
+var listItem = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)]) )`)
+
+// postProcess tidies a generated message unless llm.post_process is off
+func (s *Service) postProcess(message string) string {
+	if !s.configProvider.GetBool(postProcessKey) {
+		return message
+	}
+	return tidyMessage(message)
+}
+
+// tidyMessage cleans up a generated message deterministically: Markdown
+// code fences and wrapping quotes are removed, runs of blank lines are
+// collapsed, the subject is put in the imperative mood and the body is
+// wrapped at 72 columns
+func tidyMessage(message string) string {
+	message = stripFences(strings.TrimSpace(message))
+	message = stripQuotes(message)
+
+	lines := strings.Split(message, "\n")
+	var out []string
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blank = true
+			continue
+		}
+		if blank && len(out) > 0 {
+			out = append(out, "")
+		}
+		blank = false
+		out = append(out, line)
+	}
+	if len(out) == 0 {
+		return ""
+	}
+
+	return JoinMessage(imperativeSubject(stripQuotes(out[0])), strings.Join(out[1:], "\n"))
+}
+
+// SplitMessage splits a commit message into its subject, the first line,
+// and its body, without the blank lines around it
+func SplitMessage(message string) (subject, body string) {
+	subject, body, _ = strings.Cut(strings.TrimSpace(message), "\n")
+	return strings.TrimSpace(subject), strings.Trim(body, "\n")
+}
+
+// JoinMessage puts a subject and body together as git expects them: one
+// blank line between them and body lines wrapped at 72 columns
+func JoinMessage(subject, body string) string {
+	subject = strings.TrimSpace(subject)
+	body = strings.Trim(body, "\n")
+	if strings.TrimSpace(body) == "" {
+		return subject
+	}
+
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		lines = append(lines, wrapLine(strings.TrimRight(line, " \t\r"), bodyWidth)...)
+	}
+	return subject + "\n\n" + strings.Join(lines, "\n")
+}
+
+// stripFences removes Markdown code fence lines, keeping what they enclose
+func stripFences(message string) string {
+	if !strings.Contains(message, "```") {
+		return message
+	}
+	var kept []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// stripQuotes removes quotes or backticks wrapped around the whole text
+func stripQuotes(text string) string {
+	for _, q := range []string{`"`, "'", "`", "“"} {
+		closing := q
+		if q == "“" {
+			closing = "”"
+		}
+		if len(text) > len(q)+len(closing) && strings.HasPrefix(text, q) && strings.HasSuffix(text, closing) &&
+			!strings.Contains(text[len(q):len(text)-len(closing)], q) {
+			return strings.TrimSpace(text[len(q) : len(text)-len(closing)])
+		}
+	}
+	return text
+}
+
+// imperativeSubject rewrites the first word of a subject such as "Added
+// caching" or "feat: adds caching" to the imperative, keeping its case
+func imperativeSubject(subject string) string {
+	prefix := subjectPrefix.FindString(subject)
+	description := subject[len(prefix):]
+
+	word, rest, _ := strings.Cut(description, " ")
+	verb, ok := imperativeVerbs[strings.ToLower(word)]
+	if !ok {
+		return subject
+	}
+
+	if r, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(r) {
+		verb = strings.ToUpper(verb[:1]) + verb[1:]
+	}
+	if rest != "" {
+		verb += " " + rest
+	}
+	return prefix + verb
+}
+
+// wrapLine breaks a body line at width columns. List items continue under
+// their text; indented lines, such as code, and trailers are left alone.
+func wrapLine(line string, width int) []string {
+	if len(line) <= width || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || footerLine.MatchString(line) {
+		return []string{line}
+	}
+
+	indent := ""
+	if marker := listItem.FindString(line); marker != "" {
+		indent = strings.Repeat(" ", len(marker))
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(line[len(indent):]) {
+		if current == "" {
+			current = line[:len(indent)] + word
+			if len(lines) > 0 {
+				current = indent + word
+			}
+			continue
+		}
+		// Words longer than the width, such as URLs, get a line of their own
+		if len(current)+1+len(word) > width {
+			lines = append(lines, current)
+			current = indent + word
+			continue
+		}
+		current += " " + word
+	}
+	return append(lines, current)
+}
@@ -0,0 +1,97 @@
+// internal/commit/pr.go
+package commit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/llm"
+)
+
+// PullRequest is a generated pull or merge request title and description
+type PullRequest struct {
+	Title   string
+	Body    string
+	Commits []git.Commit
+}
+
+// longFormMinTokens leaves room for pull request descriptions and release
+// notes, which are longer than the commit messages max_tokens is usually
+// tuned for
+const longFormMinTokens = 1000
+
+// GeneratePullRequest writes a title and Markdown description for the
+// commits the current branch adds on top of base
+func (s *Service) GeneratePullRequest(ctx context.Context, repo *git.Repository, base string) (*PullRequest, error) {
+	commits, err := repo.GetCommitRange(base + "..HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits on this branch since %s", base)
+	}
+
+	stat, err := repo.GetRangeDiffStat(base)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureClient(); err != nil {
+		return nil, fmt.Errorf("LLM service is not configured. Please run 'comma setup' to configure a provider")
+	}
+
+	text, err := s.llmClient.GenerateCommitMessage(ctx, pullRequestPrompt(commits, stat, s.configProvider.GetString(llm.LLMLanguageKey)), s.longFormMaxTokens())
+	if err != nil {
+		return nil, err
+	}
+
+	pr := parsePullRequest(text)
+	pr.Commits = commits
+	return pr, nil
+}
+
+// longFormMaxTokens returns llm.max_tokens, raised to longFormMinTokens
+func (s *Service) longFormMaxTokens() int {
+	maxTokens := s.configProvider.GetInt(llm.LLMMaxTokensKey)
+	if maxTokens < longFormMinTokens {
+		maxTokens = longFormMinTokens
+	}
+	return maxTokens
+}
+
+// pullRequestPrompt asks for a title line followed by a Markdown description
+func pullRequestPrompt(commits []git.Commit, stat, language string) string {
+	var sb strings.Builder
+	sb.WriteString("Write a pull request title and description for the commits below.\n\n")
+	sb.WriteString("Rules:\n")
+	sb.WriteString("1. The first line is the title: a short summary (max 72 chars), no Markdown\n")
+	sb.WriteString("2. Leave a blank line, then write the description in Markdown\n")
+	sb.WriteString("3. Start the description with a summary paragraph, then list the notable changes\n")
+	sb.WriteString("4. Mention breaking changes and anything reviewers should test\n")
+
+	if instruction := llm.LanguageInstruction(language); instruction != "" {
+		sb.WriteString("\n" + instruction + "\n")
+	}
+
+	sb.WriteString("\nCommits (newest first):\n")
+	for _, c := range commits {
+		sb.WriteString("- " + strings.ReplaceAll(c.Message, "\n", "\n  ") + "\n")
+	}
+
+	sb.WriteString("\nFiles changed:\n")
+	sb.WriteString(stat)
+	return sb.String()
+}
+
+// parsePullRequest splits a response into the title line and the description
+func parsePullRequest(text string) *PullRequest {
+	text = strings.TrimSpace(text)
+	title, body, _ := strings.Cut(text, "\n")
+
+	title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+	title = strings.TrimSpace(strings.TrimPrefix(title, "Title:"))
+
+	return &PullRequest{Title: title, Body: strings.TrimSpace(body)}
+}
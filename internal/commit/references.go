@@ -0,0 +1,122 @@
+// internal/commit/references.go
+package commit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/ticket"
+)
+
+// Config keys for ticket and issue references
+const (
+	jiraEnabledKey   = "jira.enabled"
+	jiraPlacementKey = "jira.placement"
+	jiraFormatKey    = "jira.format"
+	jiraURLKey       = "jira.url"
+	jiraProjectsKey  = "jira.projects"
+
+	issuesEnabledKey = "issues.enabled"
+	issuesTrackerKey = "issues.tracker"
+	issuesKeywordKey = "issues.keyword"
+
+	branchPrefixKey = "branch.prefix"
+)
+
+// addReferences adds the branch prefix, the Jira ticket and the issues the
+// commit closes to a message. Issues come from the caller and, when
+// issues.enabled is set, from the branch name.
+func (s *Service) addReferences(repo git.ChangeSource, message string, issues []string) (string, error) {
+	jiraEnabled := s.configProvider.GetBool(jiraEnabledKey)
+	branchIssues := s.configProvider.GetBool(issuesEnabledKey)
+	prefix := s.configProvider.GetString(branchPrefixKey)
+	if !jiraEnabled && !branchIssues && prefix == "" && len(issues) == 0 {
+		return message, nil
+	}
+
+	context, err := repo.GetRepositoryContext()
+	if err != nil {
+		context = &git.RepositoryContext{}
+	}
+
+	if prefix != "" {
+		message = s.addBranchPrefix(context, message, prefix)
+	}
+
+	if jiraEnabled {
+		if message, err = s.addTicket(context, message); err != nil {
+			return "", err
+		}
+	}
+
+	linker := &ticket.Issues{
+		Tracker: s.configProvider.GetString(issuesTrackerKey),
+		Keyword: s.configProvider.GetString(issuesKeywordKey),
+	}
+
+	refs := make([]string, 0, len(issues)+1)
+	for _, issue := range issues {
+		refs = append(refs, linker.Normalize(issue))
+	}
+	if len(refs) == 0 && branchIssues {
+		if ref := linker.FromBranch(context.CurrentBranch); ref != "" {
+			s.logger.Info("Linking issue %s from branch %s", ref, context.CurrentBranch)
+			refs = append(refs, ref)
+		}
+	}
+
+	return linker.Apply(message, refs), nil
+}
+
+// addTicket inserts the Jira ticket found in the branch name or recent commits
+func (s *Service) addTicket(context *git.RepositoryContext, message string) (string, error) {
+	jira := &ticket.Jira{
+		Placement: s.configProvider.GetString(jiraPlacementKey),
+		Format:    s.configProvider.GetString(jiraFormatKey),
+		URL:       s.configProvider.GetString(jiraURLKey),
+	}
+	if jira.URL != "" {
+		if err := ticket.ValidateURL(jira.URL); err != nil {
+			return "", fmt.Errorf("invalid jira.url: %w", err)
+		}
+	}
+	if projects := s.configProvider.GetString(jiraProjectsKey); projects != "" {
+		jira.Projects = strings.Split(projects, ",")
+	}
+
+	key := jira.Find(context.CurrentBranch, context.CommitHistory)
+	if key == "" {
+		s.logger.Debug("No Jira ticket found in branch %q or recent commits", context.CurrentBranch)
+		return message, nil
+	}
+
+	s.logger.Info("Adding Jira ticket %s", key)
+	return jira.Apply(message, key), nil
+}
+
+// addBranchPrefix inserts branch.prefix, with {name} replaced by the
+// variables captured from the branch name, at the start of the subject. The
+// prefix is skipped when a variable it uses wasn't captured, and when the
+// subject already has it.
+func (s *Service) addBranchPrefix(context *git.RepositoryContext, message, prefix string) string {
+	replacements := make([]string, 0, 2*len(context.BranchVars))
+	for name, value := range context.BranchVars {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	text := strings.NewReplacer(replacements...).Replace(prefix)
+	if branchPlaceholder.MatchString(text) {
+		s.logger.Debug("Branch %q doesn't give every variable in branch.prefix %q", context.CurrentBranch, prefix)
+		return message
+	}
+
+	subject, _, _ := strings.Cut(message, "\n")
+	if strings.Contains(subject, strings.TrimSpace(text)) {
+		return message
+	}
+	return ticket.InsertPrefix(message, text)
+}
+
+// branchPlaceholder matches a {name} left in branch.prefix
+var branchPlaceholder = regexp.MustCompile(`\{\w+\}`)
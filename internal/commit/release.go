@@ -0,0 +1,45 @@
+// internal/commit/release.go
+package commit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/llm"
+)
+
+// GenerateReleaseHighlights summarizes a conventional changelog into
+// Markdown release highlights for users, grouped by area
+func (s *Service) GenerateReleaseHighlights(ctx context.Context, version, changelog string) (string, error) {
+	if err := s.ensureClient(); err != nil {
+		return "", fmt.Errorf("LLM service is not configured. Please run 'comma setup' to configure a provider")
+	}
+
+	prompt := releaseNotesPrompt(version, changelog, s.configProvider.GetString(llm.LLMLanguageKey))
+	text, err := s.llmClient.GenerateCommitMessage(ctx, prompt, s.longFormMaxTokens())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// releaseNotesPrompt asks for highlights grouped under one heading per area
+func releaseNotesPrompt(version, changelog, language string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Write the highlights of release %s for its users from the changelog below.\n\n", version))
+	sb.WriteString("Rules:\n")
+	sb.WriteString("1. Group the highlights by area, with a '#### <Area>' heading for each; scopes in bold name the areas\n")
+	sb.WriteString("2. Put breaking changes first and say what users must do to upgrade\n")
+	sb.WriteString("3. Write one short sentence per highlight, as a Markdown bullet, explaining the benefit rather than the implementation\n")
+	sb.WriteString("4. Merge related entries and leave out minor ones; never mention changes that are not in the changelog\n")
+	sb.WriteString("5. Output only the highlights: no title, introduction or commit hashes\n")
+
+	if instruction := llm.LanguageInstruction(language); instruction != "" {
+		sb.WriteString("\n" + instruction + "\n")
+	}
+
+	sb.WriteString("\nChangelog:\n")
+	sb.WriteString(changelog)
+	return sb.String()
+}
@@ -0,0 +1,108 @@
+// internal/commit/sequencer.go
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/analysis"
+	"github.com/jasonKoogler/comma/internal/git"
+)
+
+// Types of staged changes whose messages come from history
+const (
+	typeRevert = "revert"
+	typeMerge  = "merge"
+)
+
+// historyChange is a staged revert or merge, described by the commits it
+// involves rather than by its diff
+type historyChange struct {
+	Type        string
+	Message     string
+	Description string
+}
+
+// detectHistoryChange recognizes an unfinished merge, an unfinished revert
+// or staged changes that exactly undo a recent commit. It returns nil for
+// anything else, including changes that are not from a repository.
+func detectHistoryChange(repo git.ChangeSource) (*historyChange, error) {
+	r, ok := repo.(*git.Repository)
+	if !ok {
+		return nil, nil
+	}
+
+	merge, err := r.MergeInProgress()
+	if err != nil {
+		return nil, err
+	}
+	if merge != nil {
+		message := merge.Message
+		if message == "" {
+			message = "Merge " + strings.Join(shortHashes(merge.Heads), ", ")
+		}
+		return &historyChange{
+			Type:        typeMerge,
+			Message:     message,
+			Description: "A merge of " + strings.Join(shortHashes(merge.Heads), ", ") + " is in progress",
+		}, nil
+	}
+
+	reverted, err := r.RevertedCommit()
+	if err != nil || reverted == "" {
+		return nil, err
+	}
+	subject, err := r.CommitSubject(reverted)
+	if err != nil {
+		return nil, err
+	}
+	return &historyChange{
+		Type:        typeRevert,
+		Message:     fmt.Sprintf("revert: %s\n\nThis reverts commit %s.", subject, reverted),
+		Description: fmt.Sprintf("The changes undo commit %s (%s)", shortHashes([]string{reverted})[0], subject),
+	}, nil
+}
+
+// historyResult returns the message for a staged merge or revert, or nil
+// when the changes are neither or the user asked for something else, such
+// as another type or a rewrite with guidance
+func (s *Service) historyResult(repo git.ChangeSource, opts GenerateOptions) *Result {
+	if opts.Guidance != "" || opts.Rejected != "" || opts.Original != "" {
+		return nil
+	}
+
+	change, err := detectHistoryChange(repo)
+	if err != nil {
+		// The LLM can still describe the changes
+		s.logger.Debug("Failed to check for a merge or revert: %v", err)
+		return nil
+	}
+	if change == nil || (opts.Type != "" && opts.Type != change.Type) {
+		return nil
+	}
+
+	s.logger.Info("%s", change.Description)
+	return &Result{Message: change.Message, Type: change.Type, Confidence: 1}
+}
+
+// historySuggestion returns the classification of a staged merge or revert,
+// or nil
+func historySuggestion(repo git.ChangeSource) []analysis.CommitType {
+	change, err := detectHistoryChange(repo)
+	if err != nil || change == nil {
+		return nil
+	}
+	return []analysis.CommitType{{Type: change.Type, Confidence: 1, Description: change.Description}}
+}
+
+// shortHashes abbreviates commit hashes for messages
+func shortHashes(hashes []string) []string {
+	short := make([]string, len(hashes))
+	for i, hash := range hashes {
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		short[i] = hash
+	}
+	return short
+}
@@ -2,11 +2,18 @@
 package commit
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/jasonKoogler/comma/internal/analysis"
+	"github.com/jasonKoogler/comma/internal/cache"
+	"github.com/jasonKoogler/comma/internal/conventional"
 	"github.com/jasonKoogler/comma/internal/git"
 	"github.com/jasonKoogler/comma/internal/llm"
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/jasonKoogler/comma/internal/plugin"
+	"github.com/jasonKoogler/comma/internal/ui"
 	"github.com/jasonKoogler/comma/internal/vault"
 )
 
@@ -15,16 +22,91 @@ type Service struct {
 	llmClient         *llm.Client
 	credManager       *vault.CredentialManager
 	configProvider    llm.ConfigProvider
+	providerPolicy    llm.ProviderPolicy
+	cache             *cache.CommitCache
+	hooks             *plugin.Hooks
+	logger            logging.Logger
 	clientInitialized bool
 }
 
+// GenerateOptions controls a single generation
+type GenerateOptions struct {
+	NoCache  bool     // Always call the LLM, ignoring cached messages
+	Guidance string   // Extra instructions from the user, e.g. when regenerating
+	Rejected string   // A previous message the user rejected
+	Issues   []string // Issues the commit closes, e.g. "42" or "ENG-123"
+	Original string   // The current message of an existing commit being described
+
+	// The commit type and scope the user chose, used instead of the
+	// classifier's suggestion. Scope may be empty.
+	Type  string
+	Scope string
+}
+
+// Result is a generated commit message and where it came from
+type Result struct {
+	Message    string
+	Cached     bool
+	CacheEntry *cache.CacheEntry
+	Similarity float64 // 1 for exact cache hits, lower for similar diffs
+
+	// Classification of the changes when smart detection is enabled
+	Type       string
+	Scope      string
+	Confidence float64
+
+	// The provider and model that wrote the message; empty for cached messages
+	Provider string
+	Model    string
+
+	Usage *llm.Usage // Nil for cached messages
+}
+
+// ConfidenceThreshold is how confident the classifier must be before its
+// type and scope are suggested to the LLM
+const ConfidenceThreshold = 0.6
+
+// Cache match modes for cache.match_mode
+const (
+	CacheMatchExact   = "exact"
+	CacheMatchSimilar = "similar"
+)
+
+// Config keys read by the service that the llm package doesn't define
+const (
+	cacheMatchModeKey           = "cache.match_mode"
+	cacheSimilarityThresholdKey = "cache.similarity_threshold"
+)
+
+// SetProviderPolicy restricts which LLM providers the service may use
+func (s *Service) SetProviderPolicy(policy llm.ProviderPolicy) {
+	s.providerPolicy = policy
+	s.clientInitialized = false
+}
+
+// SetCache enables reuse of previously generated messages for identical
+// (or, in similar match mode, nearly identical) diffs
+func (s *Service) SetCache(c *cache.CommitCache) {
+	s.cache = c
+}
+
+// SetHooks runs user hook scripts around generation and commit
+func (s *Service) SetHooks(hooks *plugin.Hooks) {
+	s.hooks = hooks
+}
+
+// SetLogger reports cache lookups and generation steps to the logger
+func (s *Service) SetLogger(logger logging.Logger) {
+	s.logger = logger
+}
+
 // ensureClient ensures the LLM client is initialized
 func (s *Service) ensureClient() error {
 	if s.clientInitialized && s.llmClient != nil {
 		return nil
 	}
 
-	client, err := llm.NewClient(s.credManager, s.configProvider)
+	client, err := llm.NewClient(s.credManager, s.configProvider, s.providerPolicy)
 	if err != nil {
 		return err
 	}
@@ -35,18 +117,121 @@ func (s *Service) ensureClient() error {
 }
 
 // GenerateCommitMessage generates a commit message for the given repository
-func (s *Service) GenerateCommitMessage(repo *git.Repository) (string, error) {
+func (s *Service) GenerateCommitMessage(ctx context.Context, repo git.ChangeSource) (string, error) {
+	result, err := s.Generate(ctx, repo, GenerateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return result.Message, nil
+}
+
+// Generate generates a commit message for the given repository or patch,
+// serving it from the cache when a matching diff was seen before
+func (s *Service) Generate(ctx context.Context, repo git.ChangeSource, opts GenerateOptions) (*Result, error) {
+	provider := llm.ActiveProvider(s.configProvider)
+
+	// Refuse providers disallowed by policy with the policy's own explanation
+	if s.providerPolicy != nil {
+		if err := s.providerPolicy.CheckProvider(provider); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get staged changes to analyze
+	changes, err := repo.GetStagedChanges()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged changes: %w", err)
+	}
+
+	// Merges and reverts are described by the commits they involve
+	if result := s.historyResult(repo, opts); result != nil {
+		return s.postGenerate(repo, changes, result, opts)
+	}
+
+	if !opts.NoCache {
+		if result := s.lookupCache(changes); result != nil && s.acceptCached(result) && hasType(result.Message, opts) {
+			s.logger.Info("Using cached commit message (similarity %.2f)", result.Similarity)
+			ui.Notify(ui.NoticeInfo, "Using a cached message, no LLM request made (--no-cache for a new one)")
+			return s.postGenerate(repo, changes, result, opts)
+		}
+	} else {
+		s.logger.Debug("Cache bypassed with --no-cache")
+	}
+
 	// Initialize client if needed - THIS IS KEY
 	if err := s.ensureClient(); err != nil {
-		return "", fmt.Errorf("LLM service is not configured. Please run 'comma setup' to configure a provider")
+		return nil, fmt.Errorf("LLM service is not configured. Please run 'comma setup' to configure a provider")
+	}
+
+	prepared, err := s.buildPrompt(ctx, repo, changes, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	message, usage, err := s.requestMessage(ctx, prepared.Text, prepared.MaxTokens)
+	if err != nil {
+		return nil, err
+	}
+	provider, model := s.llmClient.Responder()
+
+	if s.configProvider.GetBool(conventionalStrictKey) {
+		if message, err = s.strictMessage(ctx, prepared, message, usage); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.cache != nil {
+		// A failed cache write only costs a future LLM call
+		if err := s.cache.Set(changes, message, provider, diffStats(changes)); err != nil {
+			s.logger.Debug("Failed to cache commit message: %v", err)
+		}
+	}
+
+	return s.postGenerate(repo, changes, &Result{
+		Message:    message,
+		Type:       prepared.Type,
+		Scope:      prepared.Scope,
+		Confidence: prepared.Confidence,
+		Provider:   provider,
+		Model:      model,
+		Usage:      usage,
+	}, opts)
+}
+
+// Prompt is a fully prepared prompt, as it would be sent to the LLM
+type Prompt struct {
+	Text string
+
+	// Classification of the changes when smart detection is enabled
+	Type       string
+	Scope      string
+	Confidence float64
+
+	EstimatedTokens int
+	MaxTokens       int
+}
+
+// PreparePrompt runs the generation pipeline up to the LLM call and returns
+// the prompt that would be sent. No request is made and no API key is needed.
+func (s *Service) PreparePrompt(repo git.ChangeSource, opts GenerateOptions) (*Prompt, error) {
+	provider := llm.ActiveProvider(s.configProvider)
+	if s.providerPolicy != nil {
+		if err := s.providerPolicy.CheckProvider(provider); err != nil {
+			return nil, err
+		}
 	}
 
-	// Get staged changes to analyze
 	changes, err := repo.GetStagedChanges()
 	if err != nil {
-		return "", fmt.Errorf("failed to get staged changes: %w", err)
+		return nil, fmt.Errorf("failed to get staged changes: %w", err)
 	}
 
+	return s.buildPrompt(context.Background(), repo, changes, opts)
+}
+
+// buildPrompt classifies the changes, renders the template and lets the
+// pre-generate hooks rewrite or veto the prompt
+func (s *Service) buildPrompt(ctx context.Context, repo git.ChangeSource, changes string, opts GenerateOptions) (*Prompt, error) {
 	// Get repository context (commit history, etc.)
 	context, err := repo.GetRepositoryContext()
 	if err != nil {
@@ -63,22 +248,18 @@ func (s *Service) GenerateCommitMessage(repo *git.Repository) (string, error) {
 
 	// Optional: Detect commit type if smart detection is enabled
 	var commitType, commitScope string
-	if s.configProvider.GetBool(llm.AnalysisSmartDetectionKey) {
-		// Get file list for analysis
-		changedFiles, _ := repo.GetChangedFiles()
-		filePaths := make([]string, len(changedFiles))
-		for i, cf := range changedFiles {
-			filePaths[i] = cf.Path
+	var detected analysis.CommitType
+	if opts.Type != "" {
+		commitType, commitScope = opts.Type, opts.Scope
+		detected = analysis.CommitType{Type: opts.Type, Scope: opts.Scope, Confidence: 1}
+	} else if s.configProvider.GetBool(llm.AnalysisSmartDetectionKey) {
+		suggestions := s.classify(repo, context, changes)
+		if len(suggestions) > 0 {
+			detected = suggestions[0]
 		}
 
-		// Create classifier with repository commit history
-		classifier := analysis.NewClassifier(context.CommitHistory)
-
-		// Analyze changes to suggest commit type and scope
-		suggestions := classifier.ClassifyChanges(changes, filePaths)
-
 		// Use suggestion if confidence is high enough
-		if len(suggestions) > 0 && suggestions[0].Confidence > 0.6 {
+		if len(suggestions) > 0 && suggestions[0].Confidence > ConfidenceThreshold {
 			commitType = suggestions[0].Type
 			commitScope = suggestions[0].Scope
 		}
@@ -87,14 +268,250 @@ func (s *Service) GenerateCommitMessage(repo *git.Repository) (string, error) {
 	// Prepare prompt with proper template and detected type/scope
 	withDiff := s.configProvider.GetBool(llm.IncludeDiffKey)
 	prompt := llm.PreparePrompt(tmplText, changes, withDiff, context, commitType, commitScope)
+	if context.InitialCommit {
+		prompt += "\n\nThis is the first commit in the repository."
+	}
+
+	guide, err := s.styleGuide(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	if guide != "" {
+		prompt += "\n\nFollow the project's commit message guidelines:\n" + guide
+	}
+
+	if examples := s.fewShotExamples(repo); examples != "" {
+		prompt += "\n\n" + examples
+	}
+	if similar := s.similarCommits(repo, changes); similar != "" {
+		prompt += "\n\n" + similar
+	}
+
+	if s.configProvider.GetBool(conventionalStrictKey) {
+		prompt += "\n\n" + conventional.PromptRules()
+	} else if conventional.CustomTypes() && !strings.Contains(tmplText, ".Types") {
+		// Templates written before types were configurable list the defaults
+		prompt += "\n\nUse one of the project's commit types: " + strings.Join(conventional.Types, ", ") + "."
+	}
+
+	if instruction := llm.LanguageInstruction(s.configProvider.GetString(llm.LLMLanguageKey)); instruction != "" {
+		prompt += "\n\n" + instruction
+	}
+
+	if opts.Type != "" {
+		header := opts.Type
+		if opts.Scope != "" {
+			header += "(" + opts.Scope + ")"
+		}
+		prompt += "\n\nThe author chose the type for this commit: start the message with \"" + header + ": \"."
+	}
+	if opts.Original != "" {
+		prompt += "\n\nThis commit already exists. Its current message, which may be vague or wrong, is:\n" + opts.Original +
+			"\nWrite a better one, keeping what it says that the diff confirms."
+	}
+	if opts.Rejected != "" {
+		prompt += "\n\nThis message was rejected, so write a different one:\n" + opts.Rejected
+	}
+	if opts.Guidance != "" {
+		prompt += "\n\nAdditional guidance from the author: " + opts.Guidance
+	}
+	if s.configProvider.GetBool(structuredOutputKey) {
+		prompt += "\n\n" + llm.StructuredInstructions
+	}
+
+	// Hooks may rewrite the prompt or refuse to send it
+	hookCtx := &plugin.HookContext{
+		Repository: context.RepoName,
+		Branch:     context.CurrentBranch,
+		Changes:    changes,
+		Prompt:     prompt,
+	}
+	if err := s.hooks.Run(plugin.HookPreGenerate, hookCtx); err != nil {
+		return nil, err
+	}
 
-	// Generate commit message using LLM
 	maxTokens := s.configProvider.GetInt(llm.LLMMaxTokensKey)
 	if maxTokens <= 0 {
 		maxTokens = 500 // Default if not set
 	}
 
-	return s.llmClient.GenerateCommitMessage(prompt, maxTokens)
+	return &Prompt{
+		Text:            hookCtx.Prompt,
+		Type:            detected.Type,
+		Scope:           detected.Scope,
+		Confidence:      detected.Confidence,
+		EstimatedTokens: llm.EstimateTokens(hookCtx.Prompt),
+		MaxTokens:       maxTokens,
+	}, nil
+}
+
+// Classify suggests commit types and scopes for changes, most likely first
+func (s *Service) Classify(repo git.ChangeSource, changes string) []analysis.CommitType {
+	context, err := repo.GetRepositoryContext()
+	if err != nil {
+		context = &git.RepositoryContext{}
+	}
+	return s.classify(repo, context, changes)
+}
+
+// classifierHistory is how many recent commits the classifier learns from
+const classifierHistory = 100
+
+// classify runs the classifier over the changes, using the repository's
+// commit history to learn its types and scopes and the patterns in the
+// config dir
+func (s *Service) classify(repo git.ChangeSource, context *git.RepositoryContext, changes string) []analysis.CommitType {
+	if suggestions := historySuggestion(repo); suggestions != nil {
+		return suggestions
+	}
+
+	changedFiles, _ := repo.GetChangedFiles()
+	filePaths := make([]string, len(changedFiles))
+	for i, cf := range changedFiles {
+		filePaths[i] = cf.Path
+	}
+
+	// The context only has the last few subjects; a repository has more
+	history := context.CommitHistory
+	r, isRepo := repo.(*git.Repository)
+	if isRepo && !context.InitialCommit {
+		if messages, err := r.RecentMessages(classifierHistory); err == nil {
+			history = make([]string, len(messages))
+			for i, message := range messages {
+				history[i], _, _ = strings.Cut(message, "\n")
+			}
+		}
+	}
+
+	patterns, err := analysis.LoadPatterns(s.configProvider.GetString(llm.ConfigDirKey))
+	if err != nil {
+		s.logger.Warn("Using the built-in classifier patterns: %v", err)
+	}
+	// Only suggest types the project uses
+	for commitType := range patterns {
+		if !conventional.IsType(commitType) {
+			delete(patterns, commitType)
+		}
+	}
+	classifier := analysis.NewClassifierWithPatterns(history, patterns)
+	if isRepo {
+		if root, err := r.Root(); err == nil {
+			classifier.UseProjectLayout(context.ProjectType, root)
+		}
+	}
+	return classifier.ClassifyChanges(changes, filePaths)
+}
+
+// hasType reports whether a message has the type and scope the user chose,
+// if any
+func hasType(message string, opts GenerateOptions) bool {
+	if opts.Type == "" {
+		return true
+	}
+	parsed, err := conventional.Parse(message)
+	return err == nil && parsed.Type == opts.Type && (opts.Scope == "" || parsed.Scope == opts.Scope)
+}
+
+// postGenerate lets hooks rewrite or reject a generated or cached message
+func (s *Service) postGenerate(repo git.ChangeSource, changes string, result *Result, opts GenerateOptions) (*Result, error) {
+	message, err := s.addReferences(repo, result.Message, opts.Issues)
+	if err != nil {
+		return nil, err
+	}
+	result.Message = message
+
+	hookCtx := &plugin.HookContext{Repository: repo.Name(), Changes: changes, Message: result.Message}
+	if err := s.hooks.Run(plugin.HookPostGenerate, hookCtx); err != nil {
+		return nil, err
+	}
+	result.Message = hookCtx.Message
+	return result, nil
+}
+
+// Commit commits the staged changes with the message after running the
+// pre-commit hooks, which may rewrite or veto it. check, if set, sees the
+// message the hooks settled on and can still refuse the commit. It returns
+// the message that was committed.
+func (s *Service) Commit(repo *git.Repository, message string, check func(message string) error) (string, error) {
+	hookCtx := &plugin.HookContext{Repository: repo.Name(), Message: message}
+	if err := s.hooks.Run(plugin.HookPreCommit, hookCtx); err != nil {
+		return "", err
+	}
+	if check != nil {
+		if err := check(hookCtx.Message); err != nil {
+			return "", err
+		}
+	}
+
+	if err := repo.Commit(hookCtx.Message); err != nil {
+		return "", err
+	}
+	return hookCtx.Message, nil
+}
+
+// AfterCommit runs the post-commit hooks for the commit just made.
+// The commit already exists, so hook failures cannot undo it.
+func (s *Service) AfterCommit(repo *git.Repository, message string) error {
+	hash, _ := repo.Head()
+	hookCtx := &plugin.HookContext{Repository: repo.Name(), Message: message, CommitHash: hash}
+	return s.hooks.Run(plugin.HookPostCommit, hookCtx)
+}
+
+// lookupCache returns a cached result for the changes, or nil on a miss
+func (s *Service) lookupCache(changes string) *Result {
+	if s.cache == nil {
+		return nil
+	}
+
+	if entry, err := s.cache.Get(changes); err == nil && entry != nil {
+		s.logger.Debug("Cache hit for diff")
+		return &Result{Message: entry.Message, Cached: true, CacheEntry: entry, Similarity: 1}
+	}
+
+	if s.configProvider.GetString(cacheMatchModeKey) != CacheMatchSimilar {
+		s.logger.Debug("Cache miss")
+		return nil
+	}
+
+	threshold := s.configProvider.GetFloat64(cacheSimilarityThresholdKey)
+	if threshold <= 0 || threshold > 1 {
+		threshold = 0.85
+	}
+
+	similar, err := s.cache.FindSimilar(changes, threshold)
+	if err != nil || similar == nil {
+		s.logger.Debug("Cache miss (no entry above similarity %.2f)", threshold)
+		return nil
+	}
+
+	return &Result{Message: similar.Entry.Message, Cached: true, CacheEntry: similar.Entry, Similarity: similar.Similarity}
+}
+
+// diffStats counts changed files and added/removed lines in a unified diff
+func diffStats(changes string) struct {
+	ChangedFiles int
+	Additions    int
+	Deletions    int
+} {
+	var stats struct {
+		ChangedFiles int
+		Additions    int
+		Deletions    int
+	}
+
+	for _, line := range strings.Split(changes, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			stats.ChangedFiles++
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			stats.Additions++
+		case strings.HasPrefix(line, "-"):
+			stats.Deletions++
+		}
+	}
+
+	return stats
 }
 
 // NewService creates a new commit service
@@ -102,6 +519,7 @@ func NewService(credManager *vault.CredentialManager, configProvider llm.ConfigP
 	return &Service{
 		credManager:       credManager,
 		configProvider:    configProvider,
+		logger:            logging.NewNullLogger(),
 		clientInitialized: false,
 	}
 }
@@ -0,0 +1,240 @@
+// internal/commit/similar.go
+package commit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/llm"
+)
+
+// similarCommitsKey sets how many past commits with the most similar diffs
+// are shown to the model; 0 turns the lookup off
+const similarCommitsKey = "llm.similar_commits"
+
+const (
+	// maxIndexedCommits is how much history the similarity index covers
+	maxIndexedCommits = 500
+
+	// maxIndexedDiff bounds the diff read per commit, so huge generated
+	// changes don't dominate indexing time
+	maxIndexedDiff = 64 << 10
+
+	// maxDocumentTerms keeps the index small; rarer terms add little
+	maxDocumentTerms = 200
+
+	// minSimilarity is the cosine similarity below which a past commit is
+	// not considered the same kind of change
+	minSimilarity = 0.2
+)
+
+// termPattern matches identifiers and words in diffs and paths
+var termPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]{2,}`)
+
+// historyIndex holds the term counts of a repository's recent commits,
+// newest first
+type historyIndex struct {
+	Head    string          `json:"head"`
+	Commits []indexedCommit `json:"commits"`
+}
+
+// indexedCommit is one commit in the history index
+type indexedCommit struct {
+	Hash    string         `json:"hash"`
+	Message string         `json:"message"`
+	Terms   map[string]int `json:"terms"`
+}
+
+// similarCommits returns the messages of past commits whose changes most
+// resemble the staged diff, formatted for the prompt, or "" when nothing is
+// close enough. The index is kept in the cache directory and only the
+// commits made since it was last updated are read.
+func (s *Service) similarCommits(source git.ChangeSource, changes string) string {
+	n := s.configProvider.GetInt(similarCommitsKey)
+	repo, ok := source.(*git.Repository)
+	if n <= 0 || !ok {
+		return ""
+	}
+
+	index, err := s.historyIndex(repo)
+	if err != nil {
+		s.logger.Debug("No history index for similar commits: %v", err)
+		return ""
+	}
+
+	query := diffTerms(changes)
+	if len(query) == 0 || len(index.Commits) == 0 {
+		return ""
+	}
+
+	// Inverse document frequencies over the indexed history
+	df := make(map[string]int)
+	for _, c := range index.Commits {
+		for term := range c.Terms {
+			df[term]++
+		}
+	}
+	idf := func(term string) float64 {
+		return math.Log(float64(len(index.Commits)+1) / float64(df[term]+1))
+	}
+
+	type match struct {
+		commit indexedCommit
+		score  float64
+	}
+	target := tfidf(query, idf)
+	var matches []match
+	for _, c := range index.Commits {
+		if score := cosine(target, tfidf(c.Terms, idf)); score >= minSimilarity {
+			matches = append(matches, match{commit: c, score: score})
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+
+	messages := make([]string, len(matches))
+	for i, m := range matches {
+		s.logger.Debug("Similar past commit %.8s (similarity %.2f)", m.commit.Hash, m.score)
+		messages[i] = m.commit.Message
+		if len(messages[i]) > maxExampleLength {
+			messages[i] = strings.TrimSpace(messages[i][:maxExampleLength]) + "\n[...]"
+		}
+	}
+	return "Past commits with similar changes, described like this:\n\n" + strings.Join(messages, "\n\n---\n\n")
+}
+
+// historyIndex loads the repository's index and brings it up to date with
+// HEAD, rebuilding it when history was rewritten
+func (s *Service) historyIndex(repo *git.Repository) (*historyIndex, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	root, err := repo.Root()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(root))
+	path := filepath.Join(s.configProvider.GetString(llm.ConfigDirKey), "cache", "history", hex.EncodeToString(sum[:8])+".json")
+
+	index := &historyIndex{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, index); err != nil {
+			index = &historyIndex{}
+		}
+	}
+	if index.Head == head {
+		return index, nil
+	}
+
+	var patches []git.CommitPatch
+	if index.Head != "" {
+		patches, err = repo.CommitPatches(index.Head+"..HEAD", maxIndexedCommits, maxIndexedDiff)
+	}
+	if index.Head == "" || err != nil {
+		// No index yet, or the indexed head is gone after a rebase
+		index.Commits = nil
+		if patches, err = repo.CommitPatches("HEAD", maxIndexedCommits, maxIndexedDiff); err != nil {
+			return nil, err
+		}
+	}
+	s.logger.Debug("Indexing %d commit(s) for similar commit lookup", len(patches))
+
+	fresh := make([]indexedCommit, 0, len(patches)+len(index.Commits))
+	for _, p := range patches {
+		fresh = append(fresh, indexedCommit{Hash: p.Hash, Message: p.Message, Terms: diffTerms(p.Diff)})
+	}
+	index.Commits = append(fresh, index.Commits...)
+	if len(index.Commits) > maxIndexedCommits {
+		index.Commits = index.Commits[:maxIndexedCommits]
+	}
+	index.Head = head
+
+	// A lost index is rebuilt on the next run
+	if data, err := json.Marshal(index); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				s.logger.Debug("Failed to save history index: %v", err)
+			}
+		}
+	}
+	return index, nil
+}
+
+// diffTerms counts the identifiers in a diff's changed lines and file paths,
+// keeping the most frequent ones
+func diffTerms(diff string) map[string]int {
+	terms := make(map[string]int)
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			continue
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+		default:
+			continue
+		}
+		for _, term := range termPattern.FindAllString(line, -1) {
+			terms[strings.ToLower(term)]++
+		}
+	}
+	delete(terms, "diff")
+	delete(terms, "git")
+
+	if len(terms) <= maxDocumentTerms {
+		return terms
+	}
+	ranked := make([]string, 0, len(terms))
+	for term := range terms {
+		ranked = append(ranked, term)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if terms[ranked[i]] != terms[ranked[j]] {
+			return terms[ranked[i]] > terms[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	kept := make(map[string]int, maxDocumentTerms)
+	for _, term := range ranked[:maxDocumentTerms] {
+		kept[term] = terms[term]
+	}
+	return kept
+}
+
+// tfidf weights term counts by how rare the terms are in the history
+func tfidf(terms map[string]int, idf func(string) float64) map[string]float64 {
+	vector := make(map[string]float64, len(terms))
+	for term, count := range terms {
+		vector[term] = (1 + math.Log(float64(count))) * idf(term)
+	}
+	return vector
+}
+
+// cosine returns the cosine similarity of two sparse vectors
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, weight := range a {
+		normA += weight * weight
+		dot += weight * b[term]
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
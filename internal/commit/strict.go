@@ -0,0 +1,54 @@
+// internal/commit/strict.go
+package commit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jasonKoogler/comma/internal/conventional"
+	"github.com/jasonKoogler/comma/internal/llm"
+)
+
+// conventionalStrictKey makes every message parseable by semantic-release
+// and commitizen
+const conventionalStrictKey = "conventional.strict"
+
+// strictMessage normalizes a generated message for release tooling. A
+// message that cannot be fixed is regenerated once with the problem
+// explained to the model.
+func (s *Service) strictMessage(ctx context.Context, prepared *Prompt, message string, usage *llm.Usage) (string, error) {
+	normalized, err := conventional.Normalize(message)
+	if err == nil {
+		return normalized, nil
+	}
+
+	s.logger.Info("Generated message is not a strict conventional commit (%v), retrying", err)
+	retry := fmt.Sprintf("%s\n\nThis message was rejected because %v, so write a different one:\n%s", prepared.Text, err, message)
+	message, retryUsage, genErr := s.requestMessage(ctx, retry, prepared.MaxTokens)
+	if genErr != nil {
+		return "", genErr
+	}
+	usage.Add(retryUsage)
+
+	normalized, err = conventional.Normalize(message)
+	if err != nil {
+		return "", fmt.Errorf("generated message is not a valid conventional commit: %w", err)
+	}
+	return normalized, nil
+}
+
+// acceptCached reports whether a cached message can be used. In strict mode
+// it is normalized, and messages that don't parse count as a cache miss.
+func (s *Service) acceptCached(result *Result) bool {
+	if !s.configProvider.GetBool(conventionalStrictKey) {
+		return true
+	}
+
+	normalized, err := conventional.Normalize(result.Message)
+	if err != nil {
+		s.logger.Debug("Ignoring cached message that is not a strict conventional commit: %v", err)
+		return false
+	}
+	result.Message = normalized
+	return true
+}
@@ -0,0 +1,141 @@
+// internal/commit/structured.go
+package commit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/conventional"
+	"github.com/jasonKoogler/comma/internal/llm"
+)
+
+// structuredOutputKey has the model answer with a JSON object that is
+// assembled into the message locally, so the format is always right
+const structuredOutputKey = "llm.structured_output"
+
+// structuredMessage is the JSON object asked for with llm.structured_output
+type structuredMessage struct {
+	Type     string `json:"type"`
+	Scope    string `json:"scope"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+	Breaking bool   `json:"breaking"`
+}
+
+// errNotJSON means the model answered in plain text rather than with the
+// requested JSON object, as providers without a JSON mode may do
+var errNotJSON = errors.New("answer is not a JSON object")
+
+// requestMessage asks the LLM for a message, as JSON with
+// llm.structured_output, and returns the post-processed message with the
+// tokens it took. A JSON answer that cannot be used is asked for once more
+// rather than committed as is.
+func (s *Service) requestMessage(ctx context.Context, prompt string, maxTokens int) (string, *llm.Usage, error) {
+	structured := s.configProvider.GetBool(structuredOutputKey)
+
+	raw, usage, err := s.ask(ctx, prompt, maxTokens, structured)
+	if err != nil {
+		return "", nil, err
+	}
+	if !structured {
+		return s.postProcess(raw), usage, nil
+	}
+
+	message, err := assembleMessage(raw)
+	if err != nil && !errors.Is(err, errNotJSON) {
+		s.logger.Warn("Asking again after an unusable structured answer: %v", err)
+		retry, retryUsage, retryErr := s.ask(ctx, prompt, maxTokens, structured)
+		if retryErr != nil {
+			return "", nil, retryErr
+		}
+		usage.Add(retryUsage)
+		raw = retry
+		message, err = assembleMessage(raw)
+	}
+
+	switch {
+	case errors.Is(err, errNotJSON):
+		s.logger.Warn("Using the model's plain text answer as is")
+		message = raw
+	case err != nil:
+		return "", nil, fmt.Errorf("model did not return a usable structured answer: %w", err)
+	}
+	return s.postProcess(message), usage, nil
+}
+
+// ask sends one request to the LLM and returns its raw answer with the
+// tokens it took
+func (s *Service) ask(ctx context.Context, prompt string, maxTokens int, structured bool) (string, *llm.Usage, error) {
+	var raw string
+	var err error
+	if structured {
+		raw, err = s.llmClient.GenerateStructured(ctx, prompt, maxTokens)
+	} else {
+		raw, err = s.llmClient.GenerateCommitMessage(ctx, prompt, maxTokens)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	usage := s.llmClient.Usage()
+	if usage == nil {
+		usage = llm.EstimateUsage(prompt, raw)
+	}
+	// Models on the user's own machine cost nothing, whatever their name
+	if provider, model := s.llmClient.Responder(); provider != "local" && provider != "local-server" {
+		usage.CostUSD, _ = llm.EstimateCost(model, *usage)
+	}
+	return raw, usage, nil
+}
+
+// assembleMessage builds a commit message from the model's JSON answer,
+// which may be wrapped in a code fence. Answers that do not start with the
+// object are plain text.
+func assembleMessage(raw string) (string, error) {
+	answer := strings.TrimSpace(raw)
+	if strings.HasPrefix(answer, "```") {
+		// Drop the fence line, which may name the language, and the closing fence
+		if i := strings.IndexByte(answer, '\n'); i >= 0 {
+			answer = answer[i+1:]
+		} else {
+			answer = ""
+		}
+		answer = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(answer), "```"))
+	}
+	// A plain-text message may still contain braces, e.g. map[string]struct{}
+	if !strings.HasPrefix(answer, "{") {
+		return "", errNotJSON
+	}
+	end := strings.LastIndex(answer, "}")
+	if end < 0 {
+		return "", fmt.Errorf("structured answer is incomplete")
+	}
+
+	var m structuredMessage
+	if err := json.Unmarshal([]byte(answer[:end+1]), &m); err != nil {
+		return "", fmt.Errorf("failed to parse structured answer: %w", err)
+	}
+	m.Subject = strings.TrimSpace(m.Subject)
+	if m.Subject == "" {
+		return "", fmt.Errorf("structured answer has no subject")
+	}
+
+	c := conventional.Commit{
+		Type:     strings.ToLower(strings.TrimSpace(m.Type)),
+		Scope:    strings.TrimSpace(m.Scope),
+		Breaking: m.Breaking,
+		Subject:  m.Subject,
+		Body:     strings.TrimSpace(m.Body),
+	}
+	if c.Type == "" {
+		// Without a type the subject stands alone
+		if c.Body == "" {
+			return c.Subject, nil
+		}
+		return c.Subject + "\n\n" + c.Body, nil
+	}
+	return c.String(), nil
+}
@@ -0,0 +1,133 @@
+// internal/commit/styleguide.go
+package commit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/git"
+	"github.com/jasonKoogler/comma/internal/llm"
+)
+
+// styleGuideFileKey points at a Markdown document with the project's commit
+// conventions, optionally narrowed to one section, e.g.
+// "CONTRIBUTING.md#Commit Messages"
+const styleGuideFileKey = "llm.style_guide_file"
+
+// maxStyleGuideLength is the longest style guide put in the prompt as is;
+// longer ones are summarized first
+const maxStyleGuideLength = 1500
+
+// styleGuide returns the project's commit conventions for the prompt, or ""
+// when no style guide is configured. Long guides are summarized by the LLM
+// once and the summary is kept until the document changes.
+func (s *Service) styleGuide(ctx context.Context, source git.ChangeSource) (string, error) {
+	setting := strings.TrimSpace(s.configProvider.GetString(styleGuideFileKey))
+	if setting == "" {
+		return "", nil
+	}
+	path, section, _ := strings.Cut(setting, "#")
+
+	// Relative paths are relative to the repository, or to the working
+	// directory for patches
+	if !filepath.IsAbs(path) {
+		if repo, ok := source.(*git.Repository); ok {
+			if root, err := repo.Root(); err == nil {
+				path = filepath.Join(root, path)
+			}
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read style guide: %w", err)
+	}
+
+	guide := string(data)
+	if section != "" {
+		var ok bool
+		if guide, ok = markdownSection(guide, section); !ok {
+			return "", fmt.Errorf("style guide %s has no %q section", path, section)
+		}
+	}
+	guide = strings.TrimSpace(guide)
+	if len(guide) <= maxStyleGuideLength {
+		return guide, nil
+	}
+
+	return s.summarizeStyleGuide(ctx, guide), nil
+}
+
+// summarizeStyleGuide condenses a long style guide, reusing the summary made
+// for the same text before. Without an LLM client, e.g. for a dry run, or
+// when summarizing fails, the guide is cut to length instead.
+func (s *Service) summarizeStyleGuide(ctx context.Context, guide string) string {
+	sum := sha256.Sum256([]byte(guide))
+	cachePath := filepath.Join(s.configProvider.GetString(llm.ConfigDirKey), "style_guides", hex.EncodeToString(sum[:8])+".md")
+	if summary, err := os.ReadFile(cachePath); err == nil {
+		return string(summary)
+	}
+
+	truncated := strings.TrimSpace(guide[:maxStyleGuideLength]) + "\n[...]"
+	if !s.clientInitialized || s.llmClient == nil {
+		return truncated
+	}
+
+	s.logger.Info("Summarizing the style guide (once per version of the document)")
+	prompt := "Summarize the rules for writing git commit messages in the project guidelines below as a " +
+		"short bulleted list. Keep every concrete requirement (format, types, scopes, length limits, " +
+		"references) and leave out everything unrelated to commit messages.\n\n" + guide
+	summary, err := s.llmClient.GenerateCommitMessage(ctx, prompt, s.longFormMaxTokens())
+	if err != nil {
+		s.logger.Warn("Failed to summarize the style guide, using the start of it: %v", err)
+		return truncated
+	}
+	summary = strings.TrimSpace(summary)
+
+	// A lost summary only costs another LLM call
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+		if err := os.WriteFile(cachePath, []byte(summary), 0644); err != nil {
+			s.logger.Debug("Failed to save style guide summary: %v", err)
+		}
+	}
+	return summary
+}
+
+// markdownSection returns the body of the section with the given heading,
+// up to the next heading of the same or a higher level
+func markdownSection(doc, heading string) (string, bool) {
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		level := headingLevel(line)
+		if level == 0 || !strings.EqualFold(strings.TrimSpace(line[level:]), strings.TrimSpace(heading)) {
+			continue
+		}
+
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if next := headingLevel(lines[j]); next > 0 && next <= level {
+				end = j
+				break
+			}
+		}
+		return strings.Join(lines[i+1:end], "\n"), true
+	}
+	return "", false
+}
+
+// headingLevel returns the level of a Markdown ATX heading, or 0
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/jasonKoogler/comma/internal/analyze"
 	"github.com/jasonKoogler/comma/internal/audit"
@@ -14,6 +16,7 @@ import (
 	"github.com/jasonKoogler/comma/internal/security"
 	"github.com/jasonKoogler/comma/internal/team"
 	"github.com/jasonKoogler/comma/internal/vault"
+	"github.com/mitchellh/go-homedir"
 )
 
 // ConfigProvider is an interface for accessing configuration
@@ -25,19 +28,39 @@ type ConfigProvider interface {
 	Set(key string, value interface{})
 }
 
-// AppContext holds application-wide components and services
+// AppContext holds application-wide components and services. Services are
+// built on first use by their accessors, so commands that don't need a
+// service start faster and are unaffected if it cannot be initialized.
 type AppContext struct {
-	ConfigDir      string
-	ConfigManager  *Manager
-	Renderer       *diff.CodeRenderer
-	Scanner        *security.Scanner
-	AuditLogger    *audit.Logger
-	Cache          *cache.CommitCache
-	CredentialMgr  *vault.CredentialManager
-	TeamManager    *team.Manager
-	Logger         logging.Logger
-	CommitService  interface{}
-	AnalyzeService *analyze.Service
+	ConfigDir     string
+	ConfigManager *Manager
+	Logger        logging.Logger
+
+	offline bool
+
+	rendererOnce       sync.Once
+	renderer           *diff.CodeRenderer
+	scannerOnce        sync.Once
+	scanner            *security.Scanner
+	scannerErr         error
+	auditLoggerOnce    sync.Once
+	auditLogger        *audit.Logger
+	auditLoggerErr     error
+	cacheOnce          sync.Once
+	cache              *cache.CommitCache
+	cacheErr           error
+	credentialMgrOnce  sync.Once
+	credentialMgr      *vault.CredentialManager
+	credentialMgrErr   error
+	teamManagerOnce    sync.Once
+	teamManager        *team.Manager
+	teamManagerErr     error
+	analyzeServiceOnce sync.Once
+	analyzeService     *analyze.Service
+	commitServiceOnce  sync.Once
+	commitService      interface{}
+	commitServiceErr   error
+	newCommitService   func() (interface{}, error)
 }
 
 // InitAppContext initializes the global application context
@@ -69,50 +92,211 @@ func InitAppContext(configDir string) (*AppContext, error) {
 	var logger logging.Logger
 	logger, err = logging.NewFileLogger("comma")
 	if err != nil {
-		logger = logging.NewConsoleLogger()
+		// Console output is added per command, according to -v
+		logger = logging.NewNullLogger()
 	}
 
-	// Initialize components
-	renderer := diff.NewCodeRenderer("")
-	scanner := security.NewScanner()
+	return &AppContext{
+		ConfigDir:     configDir,
+		ConfigManager: configManager,
+		Logger:        logger,
+	}, nil
+}
 
-	auditLogger, err := audit.NewLogger(configDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize audit logger: %w", err)
+// Renderer returns the diff renderer
+func (app *AppContext) Renderer() *diff.CodeRenderer {
+	app.rendererOnce.Do(func() {
+		app.renderer = diff.NewCodeRenderer("")
+	})
+	return app.renderer
+}
+
+// Scanner returns the security scanner for sensitive data. A scanner that
+// cannot be built keeps failing with the same error, so long-running
+// commands can report it per request.
+func (app *AppContext) Scanner() (*security.Scanner, error) {
+	app.scannerOnce.Do(func() {
+		app.scanner, app.scannerErr = newScanner(app.ConfigManager)
+	})
+	return app.scanner, app.scannerErr
+}
+
+// AuditLogger returns the audit logger
+func (app *AppContext) AuditLogger() (*audit.Logger, error) {
+	app.auditLoggerOnce.Do(func() {
+		auditLogger, err := audit.NewLogger(app.ConfigDir)
+		if err != nil {
+			app.auditLoggerErr = fmt.Errorf("failed to initialize audit logger: %w", err)
+			return
+		}
+		auditLogger.SetEnabled(app.ConfigManager.GetBool(SecurityAuditLoggingKey))
+		app.auditLogger = auditLogger
+	})
+	return app.auditLogger, app.auditLoggerErr
+}
+
+// Cache returns the commit message cache
+func (app *AppContext) Cache() (*cache.CommitCache, error) {
+	app.cacheOnce.Do(func() {
+		configManager := app.ConfigManager
+		commitCache, err := cache.NewCommitCache(app.ConfigDir)
+		if err != nil {
+			app.cacheErr = fmt.Errorf("failed to initialize commit cache: %w", err)
+			return
+		}
+		commitCache.SetEnabled(configManager.GetBool(CacheEnabledKey))
+		commitCache.SetMaxAge(time.Duration(configManager.GetInt(CacheMaxAgeKey)) * time.Hour)
+
+		cacheOpts := cache.BackendOptions{
+			SQLitePath: configManager.GetString(CacheSQLitePathKey),
+			RedisURL:   configManager.GetString(CacheRedisURLKey),
+		}
+		if err := commitCache.UseBackend(configManager.GetString(CacheBackendKey), cacheOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Using file cache: %v\n", err)
+		}
+		commitCache.SetOffline(app.offline)
+		app.cache = commitCache
+	})
+	return app.cache, app.cacheErr
+}
+
+// CredentialMgr returns the credential manager for API keys and tokens
+func (app *AppContext) CredentialMgr() (*vault.CredentialManager, error) {
+	app.credentialMgrOnce.Do(func() {
+		configManager := app.ConfigManager
+		credMgr, err := vault.NewCredentialManager(app.ConfigDir)
+		if err != nil {
+			app.credentialMgrErr = fmt.Errorf("failed to initialize credential manager: %w", err)
+			return
+		}
+
+		// Fall back to the system keyring if the configured backend is unusable,
+		// so a misconfiguration can still be fixed with 'comma config set'
+		backendOpts := vault.BackendOptions{
+			OnePasswordVault: configManager.GetString(SecurityOnePasswordVaultKey),
+			VaultAddress:     configManager.GetString(SecurityVaultAddressKey),
+			VaultMount:       configManager.GetString(SecurityVaultMountKey),
+		}
+		if err := credMgr.UseBackend(configManager.GetString(SecurityCredentialBackendKey), backendOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Using system keyring for credentials: %v\n", err)
+		}
+		if err := credMgr.SetKeyringMode(configManager.GetString(SecurityKeyringBackendKey)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Using %s keyring backend: %v\n", vault.KeyringAuto, err)
+		}
+		if err := credMgr.SetPassphraseCache(configManager.GetString(SecurityPassphraseCacheKey)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Caching the credentials passphrase with the %s: %v\n", vault.PassphraseCacheAgent, err)
+		}
+		credMgr.SetOffline(app.offline)
+		app.credentialMgr = credMgr
+	})
+	return app.credentialMgr, app.credentialMgrErr
+}
+
+// TeamManager returns the team configuration manager
+func (app *AppContext) TeamManager() (*team.Manager, error) {
+	app.teamManagerOnce.Do(func() {
+		teamMgr, err := team.NewManager(app.ConfigDir)
+		if err != nil {
+			app.teamManagerErr = fmt.Errorf("failed to initialize team manager: %w", err)
+			return
+		}
+
+		// Use the central team server when one is configured
+		if serverURL := app.ConfigManager.GetString(TeamServerURLKey); serverURL != "" {
+			token := func() string {
+				credMgr, err := app.CredentialMgr()
+				if err != nil {
+					return ""
+				}
+				token, _ := credMgr.Retrieve(team.TeamServerCredential)
+				return token
+			}
+			if err := teamMgr.ConfigureRemote(serverURL, token); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Ignoring team server configuration: %v\n", err)
+			}
+		}
+		teamMgr.SetOffline(app.offline)
+		app.teamManager = teamMgr
+	})
+	return app.teamManager, app.teamManagerErr
+}
+
+// AnalyzeService returns the commit history analysis service
+func (app *AppContext) AnalyzeService() *analyze.Service {
+	app.analyzeServiceOnce.Do(func() {
+		app.analyzeService = analyze.NewService()
+		if app.ConfigManager.GetBool(CacheEnabledKey) {
+			app.analyzeService.SetCacheDir(filepath.Join(app.ConfigDir, "cache", "analysis"))
+		}
+	})
+	return app.analyzeService
+}
+
+// SetCommitServiceFactory sets how the commit service is built. It is
+// created in main to avoid import cycles.
+func (app *AppContext) SetCommitServiceFactory(factory func() (interface{}, error)) {
+	app.newCommitService = factory
+}
+
+// CommitService returns the commit service, or nil when no factory is set
+func (app *AppContext) CommitService() (interface{}, error) {
+	app.commitServiceOnce.Do(func() {
+		if app.newCommitService != nil {
+			app.commitService, app.commitServiceErr = app.newCommitService()
+		}
+	})
+	return app.commitService, app.commitServiceErr
+}
+
+// newScanner creates the security scanner with any gitleaks rules and custom patterns
+// from configuration. Invalid patterns are reported and skipped so scanning is never
+// silently disabled. Custom patterns are added last so they can override imported rules.
+func newScanner(configManager *Manager) (*security.Scanner, error) {
+	var custom []security.CustomPattern
+
+	if rulesPath := configManager.GetString(SecurityGitleaksConfigKey); rulesPath != "" {
+		if expanded, err := homedir.Expand(rulesPath); err == nil {
+			rulesPath = expanded
+		}
+		rules, err := security.LoadGitleaksRules(rulesPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Ignoring %s: %v\n", SecurityGitleaksConfigKey, err)
+		}
+		custom = append(custom, rules...)
 	}
 
-	commitCache, err := cache.NewCommitCache(configDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize commit cache: %w", err)
+	var configured []security.CustomPattern
+	if err := configManager.UnmarshalKey(SecurityCustomPatternsKey, &configured); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Ignoring invalid %s: %v\n", SecurityCustomPatternsKey, err)
 	}
+	custom = append(custom, configured...)
 
-	credMgr, err := vault.NewCredentialManager(configDir)
+	scanner, err := security.NewScanner(nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize credential manager: %w", err)
+		return nil, fmt.Errorf("failed to initialize security scanner: %w", err)
 	}
 
-	teamMgr, err := team.NewManager(configDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize team manager: %w", err)
+	if configManager.GetBool(SecurityScanPIIKey) {
+		scanner.EnablePII()
 	}
 
-	// Create the app context first
-	appContext := &AppContext{
-		ConfigDir:      configDir,
-		ConfigManager:  configManager,
-		Renderer:       renderer,
-		Scanner:        scanner,
-		AuditLogger:    auditLogger,
-		Cache:          commitCache,
-		CredentialMgr:  credMgr,
-		TeamManager:    teamMgr,
-		Logger:         logger,
-		AnalyzeService: analyze.NewService(),
+	for _, pattern := range custom {
+		if err := scanner.AddPattern(pattern); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Skipping custom security pattern: %v\n", err)
+		}
 	}
 
-	// The commit service will be initialized in main.go to avoid import cycles
+	var overrides map[string]string
+	if err := configManager.UnmarshalKey(SecuritySeverityOverridesKey, &overrides); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Ignoring invalid %s: %v\n", SecuritySeverityOverridesKey, err)
+	}
+	for pattern, severity := range overrides {
+		if err := scanner.SetSeverityOverride(pattern, severity); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Skipping %v\n", err)
+		}
+	}
 
-	return appContext, nil
+	return scanner, nil
 }
 
 // ensureDir creates a directory if it doesn't exist
@@ -120,6 +304,26 @@ func ensureDir(path string) error {
 	return os.MkdirAll(path, 0755)
 }
 
+// IsOffline reports whether strict offline mode is enabled
+func (app *AppContext) IsOffline() bool {
+	return app.ConfigManager.GetBool(LLMOfflineKey)
+}
+
+// ApplyOffline stops every component that could reach the network from
+// doing so, including those that have not been built yet
+func (app *AppContext) ApplyOffline() {
+	app.offline = true
+	if app.teamManager != nil {
+		app.teamManager.SetOffline(true)
+	}
+	if app.credentialMgr != nil {
+		app.credentialMgr.SetOffline(true)
+	}
+	if app.cache != nil {
+		app.cache.SetOffline(true)
+	}
+}
+
 // GetAPIKey retrieves an API key with proper precedence:
 // 1. Command-line argument
 // 2. Environment variable
@@ -132,7 +336,11 @@ func (app *AppContext) GetAPIKey(provider string) (string, error) {
 	}
 
 	// Then try credential manager
-	return app.CredentialMgr.Retrieve(provider)
+	credMgr, err := app.CredentialMgr()
+	if err != nil {
+		return "", err
+	}
+	return credMgr.Retrieve(provider)
 }
 
 // GetString implements the ConfigProvider interface
@@ -11,6 +11,16 @@ const (
 	LLMModelKey         = "llm.model"
 	LLMAPIKeyKey        = "llm.api_key"
 	LLMLocalFallbackKey = "llm.use_local_fallback"
+	LLMOfflineKey       = "llm.offline"
+	LLMLanguageKey      = "llm.language"
+	LLMRaceProvidersKey = "llm.race_providers"
+	LLMSystemPromptKey  = "llm.system_prompt"
+	LLMFewShotKey       = "llm.few_shot_examples"
+	LLMStyleGuideKey    = "llm.style_guide_file"
+	LLMPostProcessKey   = "llm.post_process"
+	LLMSimilarKey       = "llm.similar_commits"
+	LLMStructuredKey    = "llm.structured_output"
+	LLMTimeoutKey       = "llm.timeout"
 
 	// Analysis Settings
 	AnalysisSmartDetectionKey = "analysis.enable_smart_detection"
@@ -19,26 +29,86 @@ const (
 	// Security Settings
 	SecurityScanSensitiveDataKey = "security.scan_for_sensitive_data"
 	SecurityAuditLoggingKey      = "security.enable_audit_logging"
+	SecurityCustomPatternsKey    = "security.custom_patterns"
+	SecurityScanPIIKey           = "security.scan_pii"
+	SecurityGitleaksConfigKey    = "security.gitleaks_config"
+	SecuritySeverityOverridesKey = "security.severity_overrides"
+	SecurityMinSeverityKey       = "security.min_severity"
+	SecurityBlockSeverityKey     = "security.block_severity"
+	SecurityCredentialBackendKey = "security.credential_backend"
+	SecurityKeyringBackendKey    = "security.keyring_backend"  // "auto", "keyring", "file" or "none"
+	SecurityPassphraseCacheKey   = "security.passphrase_cache" // "agent", "keyring" or "none"
+	SecurityOnePasswordVaultKey  = "security.onepassword_vault"
+	SecurityVaultAddressKey      = "security.vault_address"
+	SecurityVaultMountKey        = "security.vault_mount"
+	SecurityKeyMaxAgeKey         = "security.key_max_age_days"
+	SecurityKeyExpiryPolicyKey   = "security.key_expiry_policy"
 
 	// Cache Settings
-	CacheEnabledKey = "cache.enabled"
-	CacheMaxAgeKey  = "cache.max_age_hours"
+	CacheEnabledKey             = "cache.enabled"
+	CacheMaxAgeKey              = "cache.max_age_hours"
+	CacheMatchModeKey           = "cache.match_mode" // "exact" or "similar"
+	CacheSimilarityThresholdKey = "cache.similarity_threshold"
+	CacheBackendKey             = "cache.backend"
+	CacheSQLitePathKey          = "cache.sqlite_path"
+	CacheRedisURLKey            = "cache.redis_url"
 
 	// Team Settings
-	TeamEnabledKey = "team.enabled"
-	TeamNameKey    = "team.name"
+	TeamEnabledKey   = "team.enabled"
+	TeamNameKey      = "team.name"
+	TeamServerURLKey = "team.server_url"
+
+	// Conventional Commit Settings
+	ConventionalStrictKey = "conventional.strict" // semantic-release compatible output
+	ConventionalTypesKey  = "conventional.types"  // Comma-separated commit types
+
+	// Jira Settings
+	JiraEnabledKey   = "jira.enabled"
+	JiraPlacementKey = "jira.placement" // "footer" or "prefix"
+	JiraFormatKey    = "jira.format"
+	JiraURLKey       = "jira.url"
+	JiraProjectsKey  = "jira.projects" // Comma-separated project keys
+
+	// Issue Linking Settings
+	IssuesEnabledKey = "issues.enabled" // Link issues named in the branch
+	IssuesTrackerKey = "issues.tracker" // "github" or "linear"
+	IssuesKeywordKey = "issues.keyword"
+
+	// Branch Name Settings
+	BranchPatternsKey = "branch.patterns" // Regexes whose named groups become template variables
+	BranchPrefixKey   = "branch.prefix"   // Subject prefix such as "{ticket}: "
+
+	// Update Settings
+	UpdateNotifyKey = "update.notify"
+
+	// Logging Settings
+	LogLevelKey   = "logging.level"
+	LogFormatKey  = "logging.format" // "text" or "json"
+	LogMaxAgeKey  = "logging.max_age_days"
+	LogMaxSizeKey = "logging.max_size_mb"
 
 	// UI Settings
 	UISyntaxHighlightKey = "ui.syntax_highlight"
 	UIThemeKey           = "ui.theme"
+	UIFileTreeKey        = "ui.file_tree" // Show changed files as a directory tree in --select
+
+	// Prompt Settings
+	PromptExcludeGeneratedKey = "prompt.exclude_generated" // Omit diffs of generated files per .gitattributes
+	PromptExcludeGlobsKey     = "prompt.exclude_globs"     // Comma-separated files whose diffs are omitted
+	PromptIncludeUntrackedKey = "prompt.include_untracked" // Add untracked files' contents to the prompt
 
 	// Template and Behavior
-	TemplateKey    = "template"
-	IncludeDiffKey = "include_diff"
-	VerboseKey     = "verbose"
-	ConfigDirKey   = "config_dir"
+	TemplateKey     = "template"
+	IncludeDiffKey  = "include_diff"
+	MaxDiffBytesKey = "max_diff_bytes"
+	VerboseKey      = "verbose"
+	ConfigDirKey    = "config_dir"
 )
 
+// DefaultExcludeGlobs are lockfiles, vendored dependencies and minified
+// assets, whose diffs cost many tokens and say little a message needs
+const DefaultExcludeGlobs = "package-lock.json,yarn.lock,pnpm-lock.yaml,go.sum,Cargo.lock,poetry.lock,Gemfile.lock,composer.lock,vendor/,node_modules/,*.min.js,*.min.css"
+
 // EnvVarNames defines all environment variable names
 const (
 	// Common prefix for all env vars
@@ -51,6 +121,10 @@ const (
 	ClaudeAPIKeyEnv    = "CLAUDE_API_KEY"
 	CohereBPIKeyEnv    = "COHERE_API_KEY"
 	MistralAPIKeyEnv   = "MISTRAL_API_KEY"
+	GroqAPIKeyEnv      = "GROQ_API_KEY"
+	DeepSeekAPIKeyEnv  = "DEEPSEEK_API_KEY"
+	XAIAPIKeyEnv       = "XAI_API_KEY"
+	LocalServerKeyEnv  = "LOCAL_SERVER_API_KEY"
 )
 
 // DefaultValues contains default values for configuration
@@ -61,27 +135,83 @@ var DefaultValues = map[string]interface{}{
 	LLMTemperatureKey:   0.7,
 	LLMModelKey:         "gpt-4",
 	LLMLocalFallbackKey: false,
+	LLMOfflineKey:       false,
+	LLMLanguageKey:      "",
+	LLMRaceProvidersKey: "",
+	LLMSystemPromptKey:  "",
+	LLMFewShotKey:       3,
+	LLMStyleGuideKey:    "",
+	LLMPostProcessKey:   true,
+	LLMSimilarKey:       1,
+	LLMStructuredKey:    false,
+	LLMTimeoutKey:       60,
 
 	AnalysisSmartDetectionKey: true,
 	AnalysisSuggestScopesKey:  true,
 
 	SecurityScanSensitiveDataKey: true,
 	SecurityAuditLoggingKey:      true,
+	SecurityGitleaksConfigKey:    "",
+	SecurityScanPIIKey:           false,
+	SecurityMinSeverityKey:       "LOW",
+	SecurityBlockSeverityKey:     "HIGH",
+	SecurityCredentialBackendKey: "keyring",
+	SecurityKeyringBackendKey:    "auto",
+	SecurityPassphraseCacheKey:   "agent",
+	SecurityOnePasswordVaultKey:  "",
+	SecurityVaultAddressKey:      "",
+	SecurityVaultMountKey:        "secret",
+	SecurityKeyMaxAgeKey:         90,
+	SecurityKeyExpiryPolicyKey:   "warn",
+
+	CacheEnabledKey:             true,
+	CacheMaxAgeKey:              24,
+	CacheMatchModeKey:           "exact",
+	CacheSimilarityThresholdKey: 0.85,
+	CacheBackendKey:             "file",
+	CacheSQLitePathKey:          "",
+	CacheRedisURLKey:            "",
+
+	TeamEnabledKey:   false,
+	TeamNameKey:      "",
+	TeamServerURLKey: "",
+
+	ConventionalStrictKey: false,
+	ConventionalTypesKey:  "build,chore,ci,docs,feat,fix,perf,refactor,revert,style,test",
 
-	CacheEnabledKey: true,
-	CacheMaxAgeKey:  24,
+	JiraEnabledKey:   false,
+	JiraPlacementKey: "footer",
+	JiraFormatKey:    "",
+	JiraURLKey:       "",
+	JiraProjectsKey:  "",
 
-	TeamEnabledKey: false,
-	TeamNameKey:    "",
+	IssuesEnabledKey: true,
+	IssuesTrackerKey: "github",
+	IssuesKeywordKey: "Closes",
+
+	BranchPatternsKey: []string{},
+	BranchPrefixKey:   "",
+
+	UpdateNotifyKey: true,
+
+	LogLevelKey:   "info",
+	LogFormatKey:  "text",
+	LogMaxAgeKey:  30,
+	LogMaxSizeKey: 100,
 
 	UISyntaxHighlightKey: true,
 	UIThemeKey:           "monokai",
+	UIFileTreeKey:        false,
+
+	PromptExcludeGeneratedKey: true,
+	PromptExcludeGlobsKey:     DefaultExcludeGlobs,
+	PromptIncludeUntrackedKey: false,
 
 	TemplateKey: `
 Generate a concise and meaningful git commit message for the changes.
 Follow the conventional commit format: <type>(<scope>): <subject>
 
-Types: feat, fix, docs, style, refactor, test, chore
+Types: {{ .Types }}
 
 Rules:
 1. First line should be a short summary (max 72 chars)
@@ -92,7 +222,8 @@ Rules:
 Changes: 
 {{ .Changes }}`,
 
-	IncludeDiffKey: false,
+	IncludeDiffKey:  false,
+	MaxDiffBytesKey: 1 << 20,
 }
 
 // GetProviderAPIEnvVar returns the environment variable name for a given provider
@@ -110,6 +241,14 @@ func GetProviderAPIEnvVar(provider string) string {
 		return CohereBPIKeyEnv
 	case "mistral":
 		return MistralAPIKeyEnv
+	case "groq":
+		return GroqAPIKeyEnv
+	case "deepseek":
+		return DeepSeekAPIKeyEnv
+	case "xai":
+		return XAIAPIKeyEnv
+	case "local-server":
+		return LocalServerKeyEnv
 	default:
 		return EnvPrefix + "_" + provider + "_API_KEY"
 	}
@@ -136,6 +275,24 @@ func ModelOptions(provider string) []string {
 			"claude-3",
 			"claude-2",
 		}
+	case "groq":
+		return []string{
+			"llama-3.3-70b-versatile",
+			"llama-3.1-8b-instant",
+			"gemma2-9b-it",
+			"mixtral-8x7b-32768",
+		}
+	case "deepseek":
+		return []string{
+			"deepseek-chat",
+			"deepseek-reasoner",
+		}
+	case "xai":
+		return []string{
+			"grok-3-mini",
+			"grok-3",
+			"grok-2-1212",
+		}
 	case "local":
 		return []string{
 			"llama3",
@@ -145,6 +302,9 @@ func ModelOptions(provider string) []string {
 			"phi3",
 			"custom",
 		}
+	case "local-server":
+		// Whatever the server has loaded; asked for during setup
+		return nil
 	default:
 		return []string{"default"}
 	}
@@ -0,0 +1,182 @@
+// internal/config/edit.go
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Unset removes a key, or a whole section such as "jira", from the config
+// file so its default applies again. It reports whether the key was in the
+// file.
+func (m *Manager) Unset(key string) (bool, error) {
+	settings, err := m.readFile()
+	if err != nil {
+		return false, err
+	}
+
+	parts := strings.Split(strings.ToLower(key), ".")
+	parent := settings
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := parent[part].(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		parent = child
+	}
+	last := parts[len(parts)-1]
+	if _, ok := parent[last]; !ok {
+		return false, nil
+	}
+	delete(parent, last)
+	pruneEmpty(settings)
+
+	return true, m.writeFile(settings)
+}
+
+// Reset replaces the config file, or one of its sections, with the
+// defaults. The old file is copied next to it first; the copy's path is
+// returned.
+func (m *Manager) Reset(section string) (string, error) {
+	section = strings.ToLower(strings.TrimSpace(section))
+
+	settings := map[string]interface{}{}
+	if section != "" {
+		var err error
+		if settings, err = m.readFile(); err != nil {
+			return "", err
+		}
+		if !IsSection(section) {
+			return "", fmt.Errorf("unknown config section %q (sections: %s)", section, strings.Join(Sections(), ", "))
+		}
+		delete(settings, section)
+	}
+
+	for key, value := range DefaultValues {
+		if section == "" || strings.HasPrefix(key, section+".") {
+			setNested(settings, strings.Split(key, "."), value)
+		}
+	}
+
+	backup, err := m.Backup()
+	if err != nil {
+		return "", err
+	}
+	return backup, m.writeFile(settings)
+}
+
+// Backup copies the config file to a timestamped file beside it and returns
+// the copy's path, or "" when there is no config file yet
+func (m *Manager) Backup() (string, error) {
+	data, err := os.ReadFile(m.ConfigFile)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	backup := fmt.Sprintf("%s.%s.bak", m.ConfigFile, time.Now().Format("20060102-150405"))
+	// The file may hold an API key
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to back up config file: %w", err)
+	}
+	return backup, nil
+}
+
+// Sections returns the top-level sections that have defaults, sorted
+func Sections() []string {
+	seen := make(map[string]bool)
+	var sections []string
+	for key := range DefaultValues {
+		if section, _, nested := strings.Cut(key, "."); nested && !seen[section] {
+			seen[section] = true
+			sections = append(sections, section)
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// IsSection reports whether name is one of Sections
+func IsSection(name string) bool {
+	for _, section := range Sections() {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readFile reads the config file as nested maps
+func (m *Manager) readFile() (map[string]interface{}, error) {
+	data, err := os.ReadFile(m.ConfigFile)
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	settings := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return settings, nil
+}
+
+// writeFile writes the settings to the config file and reloads it
+func (m *Manager) writeFile(settings map[string]interface{}) error {
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config data: %w", err)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(m.ConfigFile); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(m.ConfigFile), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(m.ConfigFile, data, mode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to reload config file: %w", err)
+	}
+	return nil
+}
+
+// setNested sets a value at a dotted path, creating sections as needed
+func setNested(settings map[string]interface{}, path []string, value interface{}) {
+	for _, part := range path[:len(path)-1] {
+		child, ok := settings[part].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			settings[part] = child
+		}
+		settings = child
+	}
+	settings[path[len(path)-1]] = value
+}
+
+// pruneEmpty removes sections left empty by Unset
+func pruneEmpty(settings map[string]interface{}) {
+	for key, value := range settings {
+		if child, ok := value.(map[string]interface{}); ok {
+			pruneEmpty(child)
+			if len(child) == 0 {
+				delete(settings, key)
+			}
+		}
+	}
+}
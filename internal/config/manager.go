@@ -92,6 +92,11 @@ func (m *Manager) GetFloat64(key string) float64 {
 	return viper.GetFloat64(key)
 }
 
+// UnmarshalKey decodes a configuration section into a struct
+func (m *Manager) UnmarshalKey(key string, rawVal interface{}) error {
+	return viper.UnmarshalKey(key, rawVal)
+}
+
 // Set updates a configuration value
 func (m *Manager) Set(key string, value interface{}) {
 	viper.Set(key, value)
@@ -132,6 +137,16 @@ func (m *Manager) ExportConfig() (map[string]interface{}, error) {
 			"max_tokens":         viper.GetInt(LLMMaxTokensKey),
 			"temperature":        viper.GetFloat64(LLMTemperatureKey),
 			"use_local_fallback": viper.GetBool(LLMLocalFallbackKey),
+			"offline":            viper.GetBool(LLMOfflineKey),
+			"language":           viper.GetString(LLMLanguageKey),
+			"race_providers":     viper.GetString(LLMRaceProvidersKey),
+			"system_prompt":      viper.GetString(LLMSystemPromptKey),
+			"few_shot_examples":  viper.GetInt(LLMFewShotKey),
+			"style_guide_file":   viper.GetString(LLMStyleGuideKey),
+			"post_process":       viper.GetBool(LLMPostProcessKey),
+			"similar_commits":    viper.GetInt(LLMSimilarKey),
+			"structured_output":  viper.GetBool(LLMStructuredKey),
+			"timeout":            viper.GetInt(LLMTimeoutKey),
 		},
 		"analysis": map[string]interface{}{
 			"enable_smart_detection": viper.GetBool(AnalysisSmartDetectionKey),
@@ -140,22 +155,76 @@ func (m *Manager) ExportConfig() (map[string]interface{}, error) {
 		"security": map[string]interface{}{
 			"scan_for_sensitive_data": viper.GetBool(SecurityScanSensitiveDataKey),
 			"enable_audit_logging":    viper.GetBool(SecurityAuditLoggingKey),
+			"gitleaks_config":         viper.GetString(SecurityGitleaksConfigKey),
+			"scan_pii":                viper.GetBool(SecurityScanPIIKey),
+			"min_severity":            viper.GetString(SecurityMinSeverityKey),
+			"block_severity":          viper.GetString(SecurityBlockSeverityKey),
+			"credential_backend":      viper.GetString(SecurityCredentialBackendKey),
+			"keyring_backend":         viper.GetString(SecurityKeyringBackendKey),
+			"passphrase_cache":        viper.GetString(SecurityPassphraseCacheKey),
+			"onepassword_vault":       viper.GetString(SecurityOnePasswordVaultKey),
+			"vault_address":           viper.GetString(SecurityVaultAddressKey),
+			"vault_mount":             viper.GetString(SecurityVaultMountKey),
+			"key_max_age_days":        viper.GetInt(SecurityKeyMaxAgeKey),
+			"key_expiry_policy":       viper.GetString(SecurityKeyExpiryPolicyKey),
 		},
 		"cache": map[string]interface{}{
-			"enabled":       viper.GetBool(CacheEnabledKey),
-			"max_age_hours": viper.GetInt(CacheMaxAgeKey),
+			"enabled":              viper.GetBool(CacheEnabledKey),
+			"max_age_hours":        viper.GetInt(CacheMaxAgeKey),
+			"match_mode":           viper.GetString(CacheMatchModeKey),
+			"similarity_threshold": viper.GetFloat64(CacheSimilarityThresholdKey),
+			"backend":              viper.GetString(CacheBackendKey),
+			"sqlite_path":          viper.GetString(CacheSQLitePathKey),
+			"redis_url":            viper.GetString(CacheRedisURLKey),
 		},
 		"team": map[string]interface{}{
-			"enabled": viper.GetBool(TeamEnabledKey),
-			"name":    viper.GetString(TeamNameKey),
+			"enabled":    viper.GetBool(TeamEnabledKey),
+			"name":       viper.GetString(TeamNameKey),
+			"server_url": viper.GetString(TeamServerURLKey),
+		},
+		"conventional": map[string]interface{}{
+			"strict": viper.GetBool(ConventionalStrictKey),
+			"types":  viper.GetString(ConventionalTypesKey),
+		},
+		"jira": map[string]interface{}{
+			"enabled":   viper.GetBool(JiraEnabledKey),
+			"placement": viper.GetString(JiraPlacementKey),
+			"format":    viper.GetString(JiraFormatKey),
+			"url":       viper.GetString(JiraURLKey),
+			"projects":  viper.GetString(JiraProjectsKey),
+		},
+		"issues": map[string]interface{}{
+			"enabled": viper.GetBool(IssuesEnabledKey),
+			"tracker": viper.GetString(IssuesTrackerKey),
+			"keyword": viper.GetString(IssuesKeywordKey),
+		},
+		"branch": map[string]interface{}{
+			"patterns": viper.GetStringSlice(BranchPatternsKey),
+			"prefix":   viper.GetString(BranchPrefixKey),
+		},
+		"update": map[string]interface{}{
+			"notify": viper.GetBool(UpdateNotifyKey),
+		},
+		"logging": map[string]interface{}{
+			"level":        viper.GetString(LogLevelKey),
+			"format":       viper.GetString(LogFormatKey),
+			"max_age_days": viper.GetInt(LogMaxAgeKey),
+			"max_size_mb":  viper.GetInt(LogMaxSizeKey),
 		},
 		"ui": map[string]interface{}{
 			"syntax_highlight": viper.GetBool(UISyntaxHighlightKey),
 			"theme":            viper.GetString(UIThemeKey),
+			"file_tree":        viper.GetBool(UIFileTreeKey),
+		},
+		"prompt": map[string]interface{}{
+			"exclude_generated": viper.GetBool(PromptExcludeGeneratedKey),
+			"exclude_globs":     viper.GetString(PromptExcludeGlobsKey),
+			"include_untracked": viper.GetBool(PromptIncludeUntrackedKey),
 		},
-		"template":     viper.GetString(TemplateKey),
-		"include_diff": viper.GetBool(IncludeDiffKey),
-		"verbose":      viper.GetBool(VerboseKey),
+		"template":       viper.GetString(TemplateKey),
+		"include_diff":   viper.GetBool(IncludeDiffKey),
+		"max_diff_bytes": viper.GetInt(MaxDiffBytesKey),
+		"verbose":        viper.GetBool(VerboseKey),
 	}
 
 	return config, nil
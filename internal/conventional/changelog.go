@@ -0,0 +1,94 @@
+// internal/conventional/changelog.go
+package conventional
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangelogEntry is a commit to list in a changelog
+type ChangelogEntry struct {
+	Hash    string
+	Message string
+}
+
+// changelogSections are the commit types a changelog lists, in order.
+// Like conventional-changelog, other types are left out.
+var changelogSections = []struct {
+	Type  string
+	Title string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"revert", "Reverts"},
+}
+
+// Changelog renders the entries as Markdown sections in the
+// conventional-changelog style, breaking changes first. It returns "" when
+// no entry belongs in a changelog.
+func Changelog(entries []ChangelogEntry) string {
+	var breaking []string
+	sections := make(map[string][]string)
+
+	for _, e := range entries {
+		c, err := Parse(e.Message)
+		if err != nil {
+			continue
+		}
+		c.Type = strings.ToLower(c.Type)
+
+		if c.Breaking {
+			breaking = append(breaking, changelogLine(c.Scope, breakingNote(c), e.Hash))
+		}
+		sections[c.Type] = append(sections[c.Type], changelogLine(c.Scope, c.Subject, e.Hash))
+	}
+
+	var sb strings.Builder
+	if len(breaking) > 0 {
+		writeChangelogSection(&sb, "⚠ BREAKING CHANGES", breaking)
+	}
+	for _, section := range changelogSections {
+		if lines := sections[section.Type]; len(lines) > 0 {
+			writeChangelogSection(&sb, section.Title, lines)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// breakingNote returns the text of a BREAKING CHANGE footer, falling back
+// to the subject for commits only marked with "!"
+func breakingNote(c *Commit) string {
+	loc := breakingFooter.FindStringIndex(c.Body)
+	if loc == nil {
+		return c.Subject
+	}
+	note, _, _ := strings.Cut(strings.TrimSpace(c.Body[loc[1]:]), "\n\n")
+	if note == "" {
+		return c.Subject
+	}
+	return strings.Join(strings.Fields(note), " ")
+}
+
+// changelogLine formats one changelog bullet
+func changelogLine(scope, text, hash string) string {
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+	line := "* "
+	if scope != "" {
+		line += fmt.Sprintf("**%s:** ", scope)
+	}
+	line += text
+	if hash != "" {
+		line += fmt.Sprintf(" (%s)", hash)
+	}
+	return line
+}
+
+// writeChangelogSection writes a section heading and its bullets
+func writeChangelogSection(sb *strings.Builder, title string, lines []string) {
+	sb.WriteString("### " + title + "\n\n")
+	sb.WriteString(strings.Join(lines, "\n"))
+	sb.WriteString("\n\n")
+}
@@ -0,0 +1,164 @@
+// internal/conventional/conventional.go
+package conventional
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTypes are the commit types accepted by the Angular convention that
+// semantic-release and commitizen parse by default
+var DefaultTypes = []string{"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}
+
+// Types are the commit types the project uses, DefaultTypes unless set by
+// SetTypes
+var Types = DefaultTypes
+
+// SetTypes sets the project's commit types, e.g. to add hotfix or infra.
+// Types are lowercased and duplicates dropped; an empty list restores
+// DefaultTypes.
+func SetTypes(types []string) {
+	seen := make(map[string]bool)
+	var set []string
+	for _, t := range types {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" && !seen[t] {
+			seen[t] = true
+			set = append(set, t)
+		}
+	}
+	if len(set) == 0 {
+		set = DefaultTypes
+	}
+	Types = set
+}
+
+// CustomTypes reports whether Types differ from DefaultTypes
+func CustomTypes() bool {
+	if len(Types) != len(DefaultTypes) {
+		return true
+	}
+	for _, t := range Types {
+		if !IsDefaultType(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDefaultType reports whether t is one of DefaultTypes
+func IsDefaultType(t string) bool {
+	for _, known := range DefaultTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// TypePattern returns a regular expression matching "type(scope):" at the
+// start of a message for one of Types, capturing the type and the
+// parenthesized scope
+func TypePattern() string {
+	quoted := make([]string, len(Types))
+	for i, t := range Types {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return `^(` + strings.Join(quoted, "|") + `)(\([a-zA-Z0-9_-]+\))?:`
+}
+
+// header matches "type(scope)!: subject"
+var header = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()\r\n]+)\))?(!)?: (\S.*)$`)
+
+// breakingFooter matches the footers parsers treat as breaking changes,
+// including common misspellings that Normalize rewrites
+var breakingFooter = regexp.MustCompile(`(?im)^BREAKING[ -]CHANGES?:\s*`)
+
+// Commit is a parsed conventional commit message
+type Commit struct {
+	Type     string
+	Scope    string
+	Breaking bool
+	Subject  string
+	Body     string // Everything after the header, including footers
+}
+
+// Parse splits a message into its conventional commit parts
+func Parse(message string) (*Commit, error) {
+	first, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	m := header.FindStringSubmatch(strings.TrimSpace(first))
+	if m == nil {
+		return nil, fmt.Errorf("subject %q is not of the form type(scope): description", first)
+	}
+
+	body = strings.TrimSpace(body)
+	return &Commit{
+		Type:     m[1],
+		Scope:    m[2],
+		Breaking: m[3] == "!" || breakingFooter.MatchString(body),
+		Subject:  m[4],
+		Body:     body,
+	}, nil
+}
+
+// IsType reports whether t is one of the project's Types
+func IsType(t string) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize makes a message safe for semantic-release and commitizen: the
+// type is lowercased and must be one of Types, breaking changes get both
+// the "!" marker and a "BREAKING CHANGE:" footer, and footer spellings such
+// as "Breaking change:" are corrected. Messages that cannot be fixed return
+// an error describing the problem.
+func Normalize(message string) (string, error) {
+	c, err := Parse(message)
+	if err != nil {
+		return "", err
+	}
+
+	c.Type = strings.ToLower(c.Type)
+	if !IsType(c.Type) {
+		return "", fmt.Errorf("type %q is not one of %s", c.Type, strings.Join(Types, ", "))
+	}
+
+	body := breakingFooter.ReplaceAllString(c.Body, "BREAKING CHANGE: ")
+	if c.Breaking && !strings.Contains(body, "BREAKING CHANGE: ") {
+		if body != "" {
+			body += "\n\n"
+		}
+		body += "BREAKING CHANGE: " + c.Subject
+	}
+
+	c.Body = body
+	return c.String(), nil
+}
+
+// String formats the commit as a message
+func (c *Commit) String() string {
+	var sb strings.Builder
+	sb.WriteString(c.Type)
+	if c.Scope != "" {
+		sb.WriteString("(" + c.Scope + ")")
+	}
+	if c.Breaking {
+		sb.WriteString("!")
+	}
+	sb.WriteString(": " + c.Subject)
+	if c.Body != "" {
+		sb.WriteString("\n\n" + c.Body)
+	}
+	return sb.String()
+}
+
+// PromptRules tells the model to follow the strict convention
+func PromptRules() string {
+	return fmt.Sprintf("Use exactly one of these types: %s. For a breaking change, "+
+		"add ! after the type or scope and a footer starting with \"BREAKING CHANGE: \" "+
+		"that describes it.", strings.Join(Types, ", "))
+}
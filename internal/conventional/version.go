@@ -0,0 +1,91 @@
+// internal/conventional/version.go
+package conventional
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Release types, from most to least significant
+const (
+	BumpMajor = "major"
+	BumpMinor = "minor"
+	BumpPatch = "patch"
+	BumpNone  = ""
+)
+
+// Bump is the version bump a set of commits calls for, and why
+type Bump struct {
+	Kind     string // BumpMajor, BumpMinor, BumpPatch or BumpNone
+	Breaking int
+	Features int
+	Fixes    int // fix, perf and revert commits
+	Other    int // Commits that don't trigger a release
+}
+
+// ComputeBump applies semantic-release's default rules to commit messages:
+// breaking changes are major, feat is minor, and fix, perf and revert are
+// patch releases. Messages that are not conventional commits are ignored.
+func ComputeBump(messages []string) *Bump {
+	bump := &Bump{}
+	for _, message := range messages {
+		c, err := Parse(message)
+		if err != nil {
+			bump.Other++
+			continue
+		}
+
+		switch {
+		case c.Breaking:
+			bump.Breaking++
+		case c.Type == "feat":
+			bump.Features++
+		case c.Type == "fix" || c.Type == "perf" || c.Type == "revert":
+			bump.Fixes++
+		default:
+			bump.Other++
+		}
+	}
+
+	switch {
+	case bump.Breaking > 0:
+		bump.Kind = BumpMajor
+	case bump.Features > 0:
+		bump.Kind = BumpMinor
+	case bump.Fixes > 0:
+		bump.Kind = BumpPatch
+	}
+	return bump
+}
+
+// NextVersion applies a bump to a version such as "v1.2.3", keeping a "v"
+// prefix if there is one. An empty current version starts at 1.0.0.
+func NextVersion(current, kind string) (string, error) {
+	if current == "" {
+		return "1.0.0", nil
+	}
+
+	v, err := semver.NewVersion(current)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %s: %w", current, err)
+	}
+
+	var next semver.Version
+	switch kind {
+	case BumpMajor:
+		next = v.IncMajor()
+	case BumpMinor:
+		next = v.IncMinor()
+	case BumpPatch:
+		next = v.IncPatch()
+	default:
+		return current, nil
+	}
+
+	if strings.HasPrefix(current, "v") {
+		return "v" + next.String(), nil
+	}
+	return next.String(), nil
+}
@@ -2,6 +2,9 @@
 package diff
 
 import (
+	"bufio"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -13,20 +16,28 @@ import (
 // CodeRenderer handles syntax highlighting and diff formatting
 type CodeRenderer struct {
 	style string
+	color bool
 }
 
-// NewCodeRenderer creates a new syntax highlighting renderer
+// NewCodeRenderer creates a new syntax highlighting renderer. Highlighting
+// is off when the NO_COLOR environment variable is set.
 func NewCodeRenderer(style string) *CodeRenderer {
 	if style == "" {
 		style = "monokai"
 	}
-	return &CodeRenderer{style: style}
+	return &CodeRenderer{style: style, color: os.Getenv("NO_COLOR") == ""}
 }
 
-// RenderDiff highlights a git diff with syntax coloring
+// SetColor turns syntax highlighting on or off
+func (r *CodeRenderer) SetColor(enabled bool) {
+	r.color = enabled
+}
+
+// RenderDiff highlights a git diff with syntax coloring, or returns it
+// unchanged when color is off
 func (r *CodeRenderer) RenderDiff(diff string, filePath string) string {
 	// Don't try to highlight empty diffs
-	if diff == "" {
+	if diff == "" || !r.color {
 		return diff
 	}
 
@@ -131,3 +142,42 @@ func (r *CodeRenderer) RenderDiff(diff string, filePath string) string {
 
 	return result.String()
 }
+
+// RenderDiffTo highlights a diff as it is read, one hunk at a time, so large
+// diffs are rendered without holding them in memory
+func (r *CodeRenderer) RenderDiffTo(w io.Writer, diff io.Reader, filePath string) error {
+	reader := bufio.NewReader(diff)
+	var hunk strings.Builder
+
+	flush := func() error {
+		if hunk.Len() == 0 {
+			return nil
+		}
+		rendered := hunk.String()
+		if r.color {
+			// RenderDiff ends every line with a newline, including the last
+			rendered = r.RenderDiff(strings.TrimSuffix(rendered, "\n"), filePath)
+		}
+		hunk.Reset()
+		_, err := io.WriteString(w, rendered)
+		return err
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.HasPrefix(line, "@@") {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		hunk.WriteString(line)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
@@ -0,0 +1,37 @@
+// internal/diff/renderer_bench_test.go
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchmarkFileDiff builds a diff of one Go file with lines added lines
+func benchmarkFileDiff(lines int) string {
+	var sb strings.Builder
+	sb.WriteString("--- a/main.go\n+++ b/main.go\n")
+	fmt.Fprintf(&sb, "@@ -1,%d +1,%d @@\n", lines, lines*2)
+	for l := 0; l < lines; l++ {
+		fmt.Fprintf(&sb, " func existing%d() {}\n", l)
+		fmt.Fprintf(&sb, "+func added%d(x int) int { return x * %d }\n", l, l)
+	}
+	return sb.String()
+}
+
+func BenchmarkRenderDiff(b *testing.B) {
+	for _, lines := range []int{100, 2000} {
+		diff := benchmarkFileDiff(lines)
+		for _, color := range []bool{true, false} {
+			b.Run(fmt.Sprintf("lines=%d/color=%t", lines, color), func(b *testing.B) {
+				renderer := NewCodeRenderer("")
+				renderer.SetColor(color)
+				b.SetBytes(int64(len(diff)))
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					renderer.RenderDiff(diff, "main.go")
+				}
+			})
+		}
+	}
+}
@@ -17,18 +17,47 @@ var (
 	ErrAPIKeyInvalid  = errors.New("API key is invalid")
 	ErrAPIRateLimit   = errors.New("API rate limit exceeded")
 	ErrAPIUnavailable = errors.New("API service unavailable")
+	ErrOffline        = errors.New("network access is disabled in offline mode")
 
 	// Git errors
 	ErrGitNotInitialized = errors.New("git repository not initialized")
 	ErrGitNoChanges      = errors.New("no changes to commit")
 	ErrGitUncommitted    = errors.New("uncommitted changes present")
 
+	// Team errors
+	ErrLintFailed = errors.New("commits do not follow team conventions")
+
 	// Security errors
 	ErrSensitiveDataFound = errors.New("sensitive data detected in changes")
 	ErrEncryptionFailed   = errors.New("failed to encrypt data")
 	ErrCredentialAccess   = errors.New("failed to access credentials")
 )
 
+// Exit codes of the comma binary, so scripts and hooks can branch on results
+const (
+	ExitOK               = 0 // Success, e.g. the changes were committed
+	ExitError            = 1 // Any other error
+	ExitNoChanges        = 2 // Nothing was staged
+	ExitLintFailure      = 3 // Commit messages violate team conventions
+	ExitSecurityFindings = 4 // Sensitive data blocked the commit
+)
+
+// ExitCode maps an error returned by a command to the process exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrGitNoChanges):
+		return ExitNoChanges
+	case errors.Is(err, ErrLintFailed):
+		return ExitLintFailure
+	case errors.Is(err, ErrSensitiveDataFound):
+		return ExitSecurityFindings
+	default:
+		return ExitError
+	}
+}
+
 // AppError represents an application-specific error with context
 type AppError struct {
 	Err       error
@@ -0,0 +1,238 @@
+// internal/forge/forge.go
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Remote identifies a repository on a code hosting service
+type Remote struct {
+	Host  string
+	Owner string // User, organization or group path
+	Repo  string
+}
+
+// Path returns the owner/repo path of the repository
+func (r *Remote) Path() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// PullRequest is a pull request as created or found on the hosting service
+type PullRequest struct {
+	Number int
+	Title  string
+	Body   string
+	Head   string // Branch with the changes
+	Base   string // Branch the changes are merged into
+	URL    string
+	Draft  bool
+}
+
+// Client creates and updates pull requests on a code hosting service.
+// GitLab calls them merge requests; Number is then the MR's iid.
+type Client interface {
+	// Name is the service name shown to users, e.g. "GitHub"
+	Name() string
+
+	// FindPullRequest returns the open pull request for a branch, or nil
+	FindPullRequest(remote *Remote, head string) (*PullRequest, error)
+
+	// CreatePullRequest opens a pull request
+	CreatePullRequest(remote *Remote, pr *PullRequest) (*PullRequest, error)
+
+	// UpdatePullRequest replaces the title and description of a pull request
+	UpdatePullRequest(remote *Remote, pr *PullRequest) (*PullRequest, error)
+
+	// AddLabels adds labels to a pull request
+	AddLabels(remote *Remote, number int, labels []string) error
+}
+
+// Hosting services Comma can publish to
+const (
+	ServiceGitHub = "github"
+	ServiceGitLab = "gitlab"
+)
+
+// DetectService works out which hosting service a remote belongs to from its
+// host name. Self-hosted instances whose name mentions neither service are
+// reported as unknown (an empty string).
+func DetectService(remote *Remote) string {
+	switch {
+	case strings.Contains(remote.Host, "github"):
+		return ServiceGitHub
+	case strings.Contains(remote.Host, "gitlab"):
+		return ServiceGitLab
+	default:
+		return ""
+	}
+}
+
+// NewClient creates a client for a hosting service. The token is used as is;
+// GitHubCredential and GitLabCredential are the credential store keys for them.
+func NewClient(service string, remote *Remote, token string) (Client, error) {
+	switch service {
+	case ServiceGitHub:
+		return NewGitHubClient(remote, token), nil
+	case ServiceGitLab:
+		return NewGitLabClient(remote, token), nil
+	default:
+		return nil, fmt.Errorf("unsupported hosting service %q (use github or gitlab)", service)
+	}
+}
+
+// scpRemote matches scp-style remotes such as git@github.com:owner/repo.git
+var scpRemote = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// ParseRemote extracts the host and repository path from a git remote URL.
+// HTTPS, SSH and scp-style URLs are understood.
+func ParseRemote(remoteURL string) (*Remote, error) {
+	var host, path string
+
+	if strings.Contains(remoteURL, "://") {
+		parsed, err := url.Parse(remoteURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote URL %s: %w", remoteURL, err)
+		}
+		host, path = parsed.Hostname(), parsed.Path
+	} else if m := scpRemote.FindStringSubmatch(remoteURL); m != nil {
+		host, path = m[1], m[2]
+	} else {
+		return nil, fmt.Errorf("unsupported remote URL: %s", remoteURL)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	i := strings.LastIndex(path, "/")
+	if host == "" || i <= 0 {
+		return nil, fmt.Errorf("remote URL does not name a repository: %s", remoteURL)
+	}
+
+	return &Remote{Host: strings.ToLower(host), Owner: path[:i], Repo: path[i+1:]}, nil
+}
+
+// typeLabels maps conventional commit types to the labels used for them
+var typeLabels = map[string]string{
+	"feat":     "enhancement",
+	"fix":      "bug",
+	"docs":     "documentation",
+	"perf":     "performance",
+	"refactor": "refactor",
+	"test":     "tests",
+	"build":    "build",
+	"ci":       "ci",
+	"chore":    "chore",
+	"revert":   "revert",
+}
+
+// conventionalHeader matches the type, scope and breaking marker of a
+// conventional commit subject
+var conventionalHeader = regexp.MustCompile(`^(\w+)(\([^)]*\))?(!)?:`)
+
+// LabelsForCommits infers labels from the conventional commit types of a
+// branch's commits, e.g. "enhancement" for feat and "bug" for fix. Breaking
+// changes add a "breaking-change" label.
+func LabelsForCommits(messages []string) []string {
+	seen := make(map[string]bool)
+	for _, message := range messages {
+		m := conventionalHeader.FindStringSubmatch(message)
+		if m == nil {
+			continue
+		}
+		if label, ok := typeLabels[m[1]]; ok {
+			seen[label] = true
+		}
+		if m[3] == "!" || strings.Contains(message, "BREAKING CHANGE:") {
+			seen["breaking-change"] = true
+		}
+	}
+
+	labels := make([]string, 0, len(seen))
+	for label := range seen {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// sendJSON sends an API request with a JSON body, if given, and decodes the
+// JSON response into out, if given
+func sendJSON(client *http.Client, service, method, endpoint string, headers map[string]string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "comma-git-client")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", service, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return apiError(service, resp.StatusCode, data)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse %s response: %w", service, err)
+		}
+	}
+	return nil
+}
+
+// apiError turns an error response into an error, using the message the
+// service returned when there is one
+func apiError(service string, status int, data []byte) error {
+	var body struct {
+		Message interface{} `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	if json.Unmarshal(data, &body) == nil && body.Message != nil {
+		msg := fmt.Sprint(body.Message)
+		for _, e := range body.Errors {
+			if e.Message != "" {
+				msg += ": " + e.Message
+			}
+		}
+		return fmt.Errorf("%s returned status %d: %s", service, status, msg)
+	}
+
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s rejected the token (status %d)", service, status)
+	default:
+		return fmt.Errorf("unexpected status code from %s: %d", service, status)
+	}
+}
@@ -0,0 +1,137 @@
+// internal/forge/github.go
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GitHubCredential is the credential store key for the GitHub token
+const GitHubCredential = "github"
+
+// GitHubClient talks to the GitHub REST API
+type GitHubClient struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// NewGitHubClient creates a client for the GitHub instance hosting a remote
+func NewGitHubClient(remote *Remote, token string) *GitHubClient {
+	apiURL := "https://api.github.com"
+	if remote.Host != "github.com" {
+		// GitHub Enterprise Server serves the API under /api/v3
+		apiURL = "https://" + remote.Host + "/api/v3"
+	}
+
+	return &GitHubClient{
+		apiURL: apiURL,
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns "GitHub"
+func (c *GitHubClient) Name() string {
+	return "GitHub"
+}
+
+// githubPull is the part of GitHub's pull request object Comma uses
+type githubPull struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	Draft   bool   `json:"draft"`
+	Head    struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// toPullRequest converts GitHub's representation
+func (p *githubPull) toPullRequest() *PullRequest {
+	return &PullRequest{
+		Number: p.Number,
+		Title:  p.Title,
+		Body:   p.Body,
+		Head:   p.Head.Ref,
+		Base:   p.Base.Ref,
+		URL:    p.HTMLURL,
+		Draft:  p.Draft,
+	}
+}
+
+// FindPullRequest returns the open pull request for a branch, or nil if there is none
+func (c *GitHubClient) FindPullRequest(remote *Remote, head string) (*PullRequest, error) {
+	query := url.Values{"head": {remote.Owner + ":" + head}, "state": {"open"}}
+
+	var pulls []githubPull
+	if err := c.do("GET", "/repos/"+remote.Path()+"/pulls?"+query.Encode(), nil, &pulls); err != nil {
+		return nil, fmt.Errorf("failed to look up pull request: %w", err)
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	return pulls[0].toPullRequest(), nil
+}
+
+// CreatePullRequest opens a pull request and returns it with its number and URL
+func (c *GitHubClient) CreatePullRequest(remote *Remote, pr *PullRequest) (*PullRequest, error) {
+	body := map[string]interface{}{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+		"draft": pr.Draft,
+	}
+
+	var created githubPull
+	if err := c.do("POST", "/repos/"+remote.Path()+"/pulls", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return created.toPullRequest(), nil
+}
+
+// UpdatePullRequest replaces the title and description of a pull request
+func (c *GitHubClient) UpdatePullRequest(remote *Remote, pr *PullRequest) (*PullRequest, error) {
+	body := map[string]interface{}{
+		"title": pr.Title,
+		"body":  pr.Body,
+	}
+
+	var updated githubPull
+	path := fmt.Sprintf("/repos/%s/pulls/%d", remote.Path(), pr.Number)
+	if err := c.do("PATCH", path, body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update pull request #%d: %w", pr.Number, err)
+	}
+	return updated.toPullRequest(), nil
+}
+
+// AddLabels adds labels to a pull request. GitHub creates labels that
+// don't exist in the repository yet.
+func (c *GitHubClient) AddLabels(remote *Remote, number int, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/%d/labels", remote.Path(), number)
+	if err := c.do("POST", path, map[string]interface{}{"labels": labels}, nil); err != nil {
+		return fmt.Errorf("failed to add labels: %w", err)
+	}
+	return nil
+}
+
+// do sends an API request and decodes the JSON response into out, if given
+func (c *GitHubClient) do(method, path string, in, out interface{}) error {
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+		"Authorization":        "Bearer " + c.token,
+	}
+	return sendJSON(c.client, "GitHub", method, c.apiURL+path, headers, in, out)
+}
@@ -0,0 +1,138 @@
+// internal/forge/gitlab.go
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitLabCredential is the credential store key for the GitLab token
+const GitLabCredential = "gitlab"
+
+// GitLabClient talks to the GitLab REST API (v4)
+type GitLabClient struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// NewGitLabClient creates a client for the GitLab instance hosting a remote
+func NewGitLabClient(remote *Remote, token string) *GitLabClient {
+	return &GitLabClient{
+		apiURL: "https://" + remote.Host + "/api/v4",
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns "GitLab"
+func (c *GitLabClient) Name() string {
+	return "GitLab"
+}
+
+// gitlabMergeRequest is the part of GitLab's merge request object Comma uses
+type gitlabMergeRequest struct {
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	WebURL       string `json:"web_url"`
+	Draft        bool   `json:"draft"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+}
+
+// toPullRequest converts GitLab's representation
+func (mr *gitlabMergeRequest) toPullRequest() *PullRequest {
+	return &PullRequest{
+		Number: mr.IID,
+		Title:  mr.Title,
+		Body:   mr.Description,
+		Head:   mr.SourceBranch,
+		Base:   mr.TargetBranch,
+		URL:    mr.WebURL,
+		Draft:  mr.Draft,
+	}
+}
+
+// FindPullRequest returns the open merge request for a branch, or nil if there is none
+func (c *GitLabClient) FindPullRequest(remote *Remote, head string) (*PullRequest, error) {
+	query := url.Values{"source_branch": {head}, "state": {"opened"}}
+
+	var mrs []gitlabMergeRequest
+	if err := c.do("GET", c.projectPath(remote)+"/merge_requests?"+query.Encode(), nil, &mrs); err != nil {
+		return nil, fmt.Errorf("failed to look up merge request: %w", err)
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return mrs[0].toPullRequest(), nil
+}
+
+// CreatePullRequest opens a merge request and returns it with its iid and URL
+func (c *GitLabClient) CreatePullRequest(remote *Remote, pr *PullRequest) (*PullRequest, error) {
+	// GitLab marks drafts by their title
+	title := pr.Title
+	if pr.Draft && !strings.HasPrefix(title, "Draft:") {
+		title = "Draft: " + title
+	}
+
+	body := map[string]interface{}{
+		"title":         title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	}
+
+	var created gitlabMergeRequest
+	if err := c.do("POST", c.projectPath(remote)+"/merge_requests", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+	return created.toPullRequest(), nil
+}
+
+// UpdatePullRequest replaces the title and description of a merge request
+func (c *GitLabClient) UpdatePullRequest(remote *Remote, pr *PullRequest) (*PullRequest, error) {
+	body := map[string]interface{}{
+		"title":       pr.Title,
+		"description": pr.Body,
+	}
+
+	var updated gitlabMergeRequest
+	path := fmt.Sprintf("%s/merge_requests/%d", c.projectPath(remote), pr.Number)
+	if err := c.do("PUT", path, body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update merge request !%d: %w", pr.Number, err)
+	}
+	return updated.toPullRequest(), nil
+}
+
+// AddLabels adds labels to a merge request. GitLab creates labels that
+// don't exist in the project yet.
+func (c *GitLabClient) AddLabels(remote *Remote, number int, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	path := fmt.Sprintf("%s/merge_requests/%d", c.projectPath(remote), number)
+	body := map[string]interface{}{"add_labels": strings.Join(labels, ",")}
+	if err := c.do("PUT", path, body, nil); err != nil {
+		return fmt.Errorf("failed to add labels: %w", err)
+	}
+	return nil
+}
+
+// projectPath returns the API path of a project, addressed by its URL-encoded path
+func (c *GitLabClient) projectPath(remote *Remote) string {
+	return "/projects/" + url.PathEscape(remote.Path())
+}
+
+// do sends an API request and decodes the JSON response into out, if given
+func (c *GitLabClient) do(method, path string, in, out interface{}) error {
+	headers := map[string]string{
+		"Accept":        "application/json",
+		"PRIVATE-TOKEN": c.token,
+	}
+	return sendJSON(c.client, "GitLab", method, c.apiURL+path, headers, in, out)
+}
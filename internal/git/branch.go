@@ -0,0 +1,99 @@
+// internal/git/branch.go
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// CurrentBranch returns the checked-out branch, or an error on a detached HEAD
+func (r *Repository) CurrentBranch() (string, error) {
+	branch, err := r.output("branch", "--show-current")
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if branch == "" {
+		return "", fmt.Errorf("HEAD is detached - check out a branch first")
+	}
+	return branch, nil
+}
+
+// RemoteURL returns the URL of a remote such as "origin"
+func (r *Repository) RemoteURL(remote string) (string, error) {
+	url, err := r.output("remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("remote %s not found: %w", remote, err)
+	}
+	return url, nil
+}
+
+// DefaultBranch returns the branch a remote's HEAD points to, e.g. "main".
+// It falls back to "main" when the remote HEAD is unknown.
+func (r *Repository) DefaultBranch(remote string) string {
+	ref, err := r.output("symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil || ref == "" {
+		return "main"
+	}
+	return strings.TrimPrefix(ref, remote+"/")
+}
+
+// RefExists reports whether a branch, tag or other revision can be resolved
+func (r *Repository) RefExists(ref string) bool {
+	if checkRevision(ref) != nil {
+		return false
+	}
+	_, err := r.output("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return err == nil
+}
+
+// GetRangeDiffStat returns 'git diff --stat' for the changes a branch made
+// since it diverged from base
+func (r *Repository) GetRangeDiffStat(base string) (string, error) {
+	if err := checkRevision(base); err != nil {
+		return "", err
+	}
+	stat, err := r.output("diff", "--stat", base+"...HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff stat against %s: %w", base, err)
+	}
+	return stat, nil
+}
+
+// LatestTag returns the most recent tag reachable from HEAD, or "" when
+// there are no tags
+func (r *Repository) LatestTag() string {
+	tag, err := r.output("describe", "--tags", "--abbrev=0")
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+// TagBefore returns the most recent tag reachable from the parent of rev,
+// or "" when there is none
+func (r *Repository) TagBefore(rev string) string {
+	if checkRevision(rev) != nil {
+		return ""
+	}
+	tag, err := r.output("describe", "--tags", "--abbrev=0", rev+"^")
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+// output runs a git command in the repository and returns its trimmed stdout
+func (r *Repository) output(args ...string) (string, error) {
+	cmd := gitCommand(append([]string{"-C", r.path}, args...)...)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
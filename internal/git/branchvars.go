@@ -0,0 +1,57 @@
+// internal/git/branchvars.go
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// branchPatterns extract variables such as a ticket key from branch names
+var branchPatterns []*regexp.Regexp
+
+// SetBranchPatterns sets the regular expressions whose named groups become
+// RepositoryContext.BranchVars, e.g. ^(?P<ticket>[A-Z]+-\d+)[-/]. The first
+// pattern that matches the current branch wins. Invalid patterns are
+// reported and skipped.
+func SetBranchPatterns(patterns []string) error {
+	branchPatterns = nil
+	var invalid []string
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s (%v)", pattern, err))
+			continue
+		}
+		branchPatterns = append(branchPatterns, re)
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid branch patterns: %s", strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+// branchVars returns the named groups the first matching branch pattern
+// captured, leaving out empty ones, or nil when none matches
+func branchVars(branch string) map[string]string {
+	if branch == "" {
+		return nil
+	}
+	for _, re := range branchPatterns {
+		match := re.FindStringSubmatch(branch)
+		if match == nil {
+			continue
+		}
+		vars := make(map[string]string)
+		for i, name := range re.SubexpNames() {
+			if name != "" && match[i] != "" {
+				vars[name] = match[i]
+			}
+		}
+		return vars
+	}
+	return nil
+}
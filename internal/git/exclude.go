@@ -0,0 +1,174 @@
+// internal/git/exclude.go
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// excludeGenerated leaves files that .gitattributes marks as generated out
+// of prompt diffs
+var excludeGenerated = true
+
+// excludeGlobs are the files whose diffs are left out of prompts, such as
+// lockfiles and vendored dependencies
+var excludeGlobs []string
+
+// SetExcludeGlobs sets the files GetStagedChanges and GetAllChanges list
+// without their diffs. A glob without a slash matches file names anywhere,
+// one ending in a slash matches a directory anywhere, and others match the
+// path from the top of the work tree.
+func SetExcludeGlobs(globs []string) {
+	excludeGlobs = nil
+	for _, glob := range globs {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			excludeGlobs = append(excludeGlobs, glob)
+		}
+	}
+}
+
+// SetExcludeGenerated sets whether GetStagedChanges and GetAllChanges omit
+// the diffs of files marked linguist-generated or -diff in .gitattributes.
+// The files are still listed.
+func SetExcludeGenerated(exclude bool) {
+	excludeGenerated = exclude
+}
+
+// omittedFiles returns the files changed by a diff with the given arguments
+// whose hunks should be left out of the prompt: those matching excludeGlobs
+// and generated ones. Staged diffs read .gitattributes from the index, others
+// from the working tree.
+func (r *Repository) omittedFiles(cached bool, diffArgs ...string) ([]string, error) {
+	if !excludeGenerated && len(excludeGlobs) == 0 {
+		return nil, nil
+	}
+
+	var namesOut bytes.Buffer
+	args := append([]string{"diff", "--name-only", "-z"}, diffArgs...)
+	if err := r.run(&namesOut, "failed to list changed files", args...); err != nil {
+		return nil, err
+	}
+	if namesOut.Len() == 0 {
+		return nil, nil
+	}
+
+	return r.filterOmitted(cached, strings.Split(strings.TrimSuffix(namesOut.String(), "\x00"), "\x00"))
+}
+
+// filterOmitted returns the names, relative to the top of the work tree,
+// that match excludeGlobs or are generated, in their original order
+func (r *Repository) filterOmitted(cached bool, names []string) ([]string, error) {
+	omitted := make(map[string]bool)
+	for _, name := range names {
+		if matchesExcludeGlob(name) {
+			omitted[name] = true
+		}
+	}
+
+	if excludeGenerated && len(names) > 0 {
+		// check-attr reads paths relative to where it runs
+		root, err := r.Root()
+		if err != nil {
+			return nil, err
+		}
+
+		args := []string{"check-attr", "-z", "--stdin"}
+		if cached {
+			args = append(args, "--cached")
+		}
+		args = append(args, "linguist-generated", "diff")
+
+		cmd := gitCommand(append([]string{"-C", root}, args...)...)
+		cmd.Stdin = strings.NewReader(strings.Join(names, "\x00") + "\x00")
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("failed to read git attributes: %w", err)
+		}
+		for _, name := range parseGeneratedAttrs(out.String()) {
+			omitted[name] = true
+		}
+	}
+
+	var paths []string
+	for _, name := range names {
+		if omitted[name] {
+			paths = append(paths, name)
+		}
+	}
+	return paths, nil
+}
+
+// matchesExcludeGlob reports whether a path from the top of the work tree
+// matches one of excludeGlobs
+func matchesExcludeGlob(file string) bool {
+	for _, glob := range excludeGlobs {
+		switch {
+		case strings.HasSuffix(glob, "/"):
+			dir := strings.TrimSuffix(glob, "/")
+			parts := strings.Split(file, "/")
+			for _, part := range parts[:len(parts)-1] {
+				if ok, _ := path.Match(dir, part); ok {
+					return true
+				}
+			}
+		case strings.Contains(glob, "/"):
+			if ok, _ := path.Match(glob, file); ok {
+				return true
+			}
+		default:
+			if ok, _ := path.Match(glob, path.Base(file)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseGeneratedAttrs reads 'git check-attr -z' output, which repeats
+// path, attribute and value separated by NULs, and returns the paths that are
+// linguist-generated or have diff unset
+func parseGeneratedAttrs(out string) []string {
+	fields := strings.Split(out, "\x00")
+	seen := make(map[string]bool)
+	var paths []string
+	for i := 0; i+2 < len(fields); i += 3 {
+		file, attr, value := fields[i], fields[i+1], fields[i+2]
+		generated := (attr == "linguist-generated" && (value == "set" || value == "true")) ||
+			(attr == "diff" && value == "unset")
+		if generated && !seen[file] {
+			seen[file] = true
+			paths = append(paths, file)
+		}
+	}
+	return paths
+}
+
+// excludePathspecs limits a diff to the given pathspecs, or the whole work
+// tree, without the excluded files, which are relative to its top
+func excludePathspecs(pathspecs, excluded []string) []string {
+	if len(pathspecs) == 0 && len(excluded) == 0 {
+		return nil
+	}
+	specs := append([]string{"--"}, pathspecs...)
+	if len(pathspecs) == 0 {
+		specs = append(specs, ":(top)")
+	}
+	for _, file := range excluded {
+		specs = append(specs, ":(top,exclude,literal)"+file)
+	}
+	return specs
+}
+
+// writeOmitted lists the files whose diffs were left out of a prompt
+func writeOmitted(sb *strings.Builder, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	sb.WriteString("\n# Excluded Files (diff omitted):\n")
+	for _, file := range paths {
+		sb.WriteString(file + "\n")
+	}
+}
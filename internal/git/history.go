@@ -0,0 +1,100 @@
+// internal/git/history.go
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CommitPatch is a commit's message together with its diff
+type CommitPatch struct {
+	Hash    string
+	Message string
+	Diff    string
+}
+
+// CommitPatches returns up to n non-merge commits in a revision range, newest
+// first, with their diffs cut to maxBytes each. The diffs have no context
+// lines, since only the changed lines are of interest.
+func (r *Repository) CommitPatches(revRange string, n, maxBytes int) ([]CommitPatch, error) {
+	if err := checkRevision(revRange); err != nil {
+		return nil, err
+	}
+	out, err := r.stream("failed to read commit history", "log", "--no-merges", fmt.Sprintf("-%d", n),
+		"--no-color", "--unified=0", "--patch", "--pretty=format:%x1e%H%n%B%x1f", revRange, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	patches, err := parseCommitPatches(out, maxBytes)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return patches, nil
+}
+
+// parseCommitPatches splits 'git log --patch' output in the format used by
+// CommitPatches. Commits start with a record separator and the hash; the
+// message ends at a unit separator and the diff follows.
+func parseCommitPatches(r io.Reader, maxBytes int) ([]CommitPatch, error) {
+	var patches []CommitPatch
+	var current *CommitPatch
+	var message, diff strings.Builder
+	inMessage := false
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Message = strings.TrimSpace(message.String())
+		current.Diff = diff.String()
+		patches = append(patches, *current)
+		message.Reset()
+		diff.Reset()
+	}
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			switch {
+			case strings.HasPrefix(line, "\x1e"):
+				flush()
+				current = &CommitPatch{Hash: strings.TrimSpace(line[1:])}
+				inMessage = true
+			case inMessage:
+				if end := strings.IndexByte(line, '\x1f'); end >= 0 {
+					message.WriteString(line[:end])
+					inMessage = false
+				} else {
+					message.WriteString(line)
+				}
+			case current != nil && (maxBytes <= 0 || diff.Len()+len(line) <= maxBytes):
+				diff.WriteString(line)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit history: %w", err)
+		}
+	}
+	flush()
+
+	return patches, nil
+}
+
+// CommitDiff returns the diff a commit introduced, cut like a staged diff.
+// The first commit is diffed against the empty tree.
+func (r *Repository) CommitDiff(rev string) (string, error) {
+	if err := checkRevision(rev); err != nil {
+		return "", err
+	}
+	return r.readDiff("show", "--no-color", "--format=", rev, "--")
+}
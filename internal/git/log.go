@@ -0,0 +1,23 @@
+// internal/git/log.go
+package git
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/jasonKoogler/comma/internal/logging"
+)
+
+// logger receives every git command run, shown with -vv
+var logger logging.Logger = logging.NewNullLogger()
+
+// SetLogger sets the logger git commands are reported to
+func SetLogger(l logging.Logger) {
+	logger = l
+}
+
+// gitCommand creates a git command, logging it at debug level
+func gitCommand(args ...string) *exec.Cmd {
+	logger.Debug("git %s", strings.Join(args, " "))
+	return exec.Command("git", args...)
+}
@@ -0,0 +1,136 @@
+// internal/git/patch.go
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeSource provides the changes a commit message is generated from.
+// Repository reads them from git; Patch uses a diff supplied by the caller.
+type ChangeSource interface {
+	Name() string
+	GetStagedChanges() (string, error)
+	GetChangedFiles() ([]FileChange, error)
+	GetRepositoryContext() (*RepositoryContext, error)
+}
+
+// Patch is a unified diff given directly, e.g. piped from 'git diff --cached',
+// so messages can be generated without a repository checkout
+type Patch struct {
+	name  string
+	diff  string
+	files []FileChange
+	stats []patchStat
+}
+
+// patchStat counts the changed lines of one file in a patch
+type patchStat struct {
+	path      string
+	additions int
+	deletions int
+}
+
+// NewPatch parses a unified diff. The name is reported as the repository name.
+func NewPatch(name, diff string) *Patch {
+	p := &Patch{name: name, diff: diff}
+
+	current := -1
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			path := line[strings.LastIndex(line, " b/")+3:]
+			p.files = append(p.files, FileChange{Path: path, Status: parseStatusCode("M")})
+			p.stats = append(p.stats, patchStat{path: path})
+			current = len(p.stats) - 1
+		case current < 0:
+		case strings.HasPrefix(line, "new file mode"):
+			p.files[current].Status = parseStatusCode("A")
+		case strings.HasPrefix(line, "deleted file mode"):
+			p.files[current].Status = parseStatusCode("D")
+		case strings.HasPrefix(line, "rename to "):
+			p.files[current].Status = parseStatusCode("R")
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			p.stats[current].additions++
+		case strings.HasPrefix(line, "-"):
+			p.stats[current].deletions++
+		}
+	}
+
+	return p
+}
+
+// Name returns the name given to the patch
+func (p *Patch) Name() string {
+	return p.name
+}
+
+// GetStagedChanges formats the patch like Repository.GetStagedChanges
+func (p *Patch) GetStagedChanges() (string, error) {
+	if strings.TrimSpace(p.diff) == "" {
+		return "", nil
+	}
+	if len(p.files) == 0 {
+		return "", fmt.Errorf("input is not a unified diff from git")
+	}
+
+	var result strings.Builder
+	result.WriteString("# Staged Files:\n")
+	for _, file := range p.files {
+		fmt.Fprintf(&result, "%s\t%s\n", statusLetter(file.Status), file.Path)
+	}
+
+	result.WriteString("\n# Changes Summary:\n")
+	var additions, deletions int
+	for _, stat := range p.stats {
+		fmt.Fprintf(&result, " %s | %d %s%s\n", stat.path, stat.additions+stat.deletions,
+			strings.Repeat("+", min(stat.additions, 40)), strings.Repeat("-", min(stat.deletions, 40)))
+		additions += stat.additions
+		deletions += stat.deletions
+	}
+	fmt.Fprintf(&result, " %d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)\n", len(p.stats), additions, deletions)
+
+	result.WriteString("\n# Diff:\n")
+	result.WriteString(p.diff)
+
+	return result.String(), nil
+}
+
+// GetChangedFiles returns the files touched by the patch
+func (p *Patch) GetChangedFiles() ([]FileChange, error) {
+	return p.files, nil
+}
+
+// GetRepositoryContext returns what can be known without a repository:
+// its name and the types of the changed files
+func (p *Patch) GetRepositoryContext() (*RepositoryContext, error) {
+	context := &RepositoryContext{RepoName: p.name, CommitHistory: []string{}}
+
+	seen := make(map[string]bool)
+	for _, file := range p.files {
+		if i := strings.LastIndex(file.Path, "."); i > strings.LastIndex(file.Path, "/") {
+			ext := file.Path[i:]
+			if !seen[ext] {
+				seen[ext] = true
+				context.FileTypes = append(context.FileTypes, ext)
+			}
+		}
+	}
+
+	return context, nil
+}
+
+// statusLetter converts a FileChange status back to git's status letter
+func statusLetter(status string) string {
+	switch status {
+	case "Added":
+		return "A"
+	case "Deleted":
+		return "D"
+	case "Renamed":
+		return "R"
+	default:
+		return "M"
+	}
+}
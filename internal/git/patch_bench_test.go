@@ -0,0 +1,47 @@
+// internal/git/patch_bench_test.go
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchmarkDiff builds a unified diff touching files files with lines added
+// lines each, shaped like the output of 'git diff --cached'
+func benchmarkDiff(files, lines int) string {
+	var sb strings.Builder
+	for f := 0; f < files; f++ {
+		path := fmt.Sprintf("internal/pkg%d/file%d.go", f%20, f)
+		fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", path, path)
+		fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", path, path)
+		fmt.Fprintf(&sb, "@@ -1,%d +1,%d @@\n", lines, lines*2)
+		for l := 0; l < lines; l++ {
+			fmt.Fprintf(&sb, " func existing%d() {}\n", l)
+			fmt.Fprintf(&sb, "+func added%d(x int) int { return x * %d }\n", l, l)
+		}
+	}
+	return sb.String()
+}
+
+func BenchmarkNewPatch(b *testing.B) {
+	for _, size := range []struct{ files, lines int }{{10, 50}, {200, 200}} {
+		diff := benchmarkDiff(size.files, size.lines)
+		b.Run(fmt.Sprintf("files=%d/lines=%d", size.files, size.lines), func(b *testing.B) {
+			b.SetBytes(int64(len(diff)))
+			for i := 0; i < b.N; i++ {
+				NewPatch("bench", diff)
+			}
+		})
+	}
+}
+
+func BenchmarkPatchGetStagedChanges(b *testing.B) {
+	patch := NewPatch("bench", benchmarkDiff(200, 200))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := patch.GetStagedChanges(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // Repository represents a git repository
 type Repository struct {
-	path string
+	path  string
+	paths []string // Pathspecs the changes are limited to; empty for all
 }
 
 // RepositoryContext contains information about the repository
@@ -23,12 +26,17 @@ type RepositoryContext struct {
 	FileTypes     []string
 	ProjectType   string
 	CommitHistory []string
+	InitialCommit bool // The branch has no commits yet
+
+	// Named groups captured from the branch name by SetBranchPatterns, such
+	// as "ticket"; available to templates as {{ .Context.BranchVars.ticket }}
+	BranchVars map[string]string
 }
 
 // NewRepository creates a new Repository instance
 func NewRepository(path string) (*Repository, error) {
 	// Check if path is a git repository
-	cmd := exec.Command("git", "-C", path, "rev-parse", "--git-dir")
+	cmd := gitCommand("-C", path, "rev-parse", "--git-dir")
 	if err := cmd.Run(); err != nil {
 		return nil, fmt.Errorf("not a git repository: %w", err)
 	}
@@ -41,9 +49,80 @@ func NewRepository(path string) (*Repository, error) {
 	return &Repository{path: absPath}, nil
 }
 
+// Only returns the repository with its staged changes, changed files and
+// commits limited to the given pathspecs, which are relative to the
+// repository's directory
+func (r *Repository) Only(paths ...string) *Repository {
+	return &Repository{path: r.path, paths: paths}
+}
+
+// Stage adds the current contents of the paths to the index, including
+// deletions
+func (r *Repository) Stage(paths ...string) error {
+	var out bytes.Buffer
+	return r.run(&out, "failed to stage files", append([]string{"add", "--all", "--"}, paths...)...)
+}
+
+// pathspecs returns "--" and the pathspecs the repository is limited to,
+// or nothing
+func (r *Repository) pathspecs() []string {
+	if len(r.paths) == 0 {
+		return nil
+	}
+	return append([]string{"--"}, r.paths...)
+}
+
+// Name returns the name of the repository directory
+func (r *Repository) Name() string {
+	return filepath.Base(r.path)
+}
+
+// Root returns the top-level directory of the working tree
+func (r *Repository) Root() (string, error) {
+	cmd := gitCommand("-C", r.path, "rev-parse", "--show-toplevel")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Head returns the commit SHA that HEAD points to
+func (r *Repository) Head() (string, error) {
+	cmd := gitCommand("-C", r.path, "rev-parse", "HEAD")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// IsUnborn reports whether the current branch has no commits yet, as in a
+// freshly initialized repository
+func (r *Repository) IsUnborn() bool {
+	var out bytes.Buffer
+	return r.run(&out, "", "rev-parse", "--verify", "--quiet", "HEAD") != nil
+}
+
+// diffBase returns what the working tree is compared with: HEAD, or the
+// empty tree before the first commit so every file shows as added
+func (r *Repository) diffBase() (string, error) {
+	if !r.IsUnborn() {
+		return "HEAD", nil
+	}
+	// The empty tree's ID depends on the repository's hash algorithm
+	var out bytes.Buffer
+	if err := r.run(&out, "failed to get empty tree", "hash-object", "-t", "tree", "--stdin"); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
 // GetGitDir returns the path to the .git directory
 func (r *Repository) GetGitDir() (string, error) {
-	cmd := exec.Command("git", "-C", r.path, "rev-parse", "--git-dir")
+	cmd := gitCommand("-C", r.path, "rev-parse", "--git-dir")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	if err := cmd.Run(); err != nil {
@@ -60,32 +139,31 @@ func (r *Repository) GetGitDir() (string, error) {
 
 // GetStagedChanges returns the git diff for staged changes
 func (r *Repository) GetStagedChanges() (string, error) {
-	// Get list of staged files
-	cmd := exec.Command("git", "-C", r.path, "diff", "--name-status", "--cached")
-	var filesOut bytes.Buffer
-	cmd.Stdout = &filesOut
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get staged files: %w", err)
-	}
-
-	if filesOut.Len() == 0 {
-		return "", nil
+	omitted, err := r.omittedFiles(true, append([]string{"--cached"}, r.pathspecs()...)...)
+	if err != nil {
+		return "", err
 	}
 
-	// Get summary of staged changes
-	cmd = exec.Command("git", "-C", r.path, "diff", "--cached", "--stat")
-	var summaryOut bytes.Buffer
-	cmd.Stdout = &summaryOut
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get changes summary: %w", err)
+	// The file list, summary and diff are independent, so read them together
+	var filesOut, summaryOut bytes.Buffer
+	var diffOut string
+	var g errgroup.Group
+	g.Go(func() error {
+		return r.run(&filesOut, "failed to get staged files", append([]string{"diff", "--name-status", "--cached"}, r.pathspecs()...)...)
+	})
+	g.Go(func() error {
+		return r.run(&summaryOut, "failed to get changes summary", append([]string{"diff", "--cached", "--stat"}, r.pathspecs()...)...)
+	})
+	g.Go(func() (err error) {
+		diffOut, err = r.readDiff(append([]string{"diff", "--cached"}, excludePathspecs(r.paths, omitted)...)...)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return "", err
 	}
 
-	// Get actual diff of staged changes
-	cmd = exec.Command("git", "-C", r.path, "diff", "--cached")
-	var diffOut bytes.Buffer
-	cmd.Stdout = &diffOut
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get diff: %w", err)
+	if filesOut.Len() == 0 {
+		return "", nil
 	}
 
 	// Combine the information
@@ -94,40 +172,63 @@ func (r *Repository) GetStagedChanges() (string, error) {
 	result.WriteString(filesOut.String())
 	result.WriteString("\n# Changes Summary:\n")
 	result.WriteString(summaryOut.String())
+	writeOmitted(&result, omitted)
 	result.WriteString("\n# Diff:\n")
-	result.WriteString(diffOut.String())
+	result.WriteString(diffOut)
+
+	untracked, err := r.UntrackedDiff()
+	if err != nil {
+		return "", err
+	}
+	if untracked != "" {
+		result.WriteString("\n# Untracked Files (not staged):\n")
+		result.WriteString(untracked)
+	}
 
 	return result.String(), nil
 }
 
-// GetAllChanges returns the git diff for all changes (staged and unstaged)
-func (r *Repository) GetAllChanges() (string, error) {
-	// Get list of changed files
-	cmd := exec.Command("git", "-C", r.path, "status", "--porcelain")
-	var filesOut bytes.Buffer
-	cmd.Stdout = &filesOut
+// run runs a git command in the repository, writing its stdout to out.
+// Failures are wrapped with what describes the command.
+func (r *Repository) run(out *bytes.Buffer, what string, args ...string) error {
+	cmd := gitCommand(append([]string{"-C", r.path}, args...)...)
+	cmd.Stdout = out
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get changed files: %w", err)
+		return fmt.Errorf("%s: %w", what, err)
 	}
+	return nil
+}
 
-	if filesOut.Len() == 0 {
-		return "", nil
+// GetAllChanges returns the git diff for all changes (staged and unstaged)
+func (r *Repository) GetAllChanges() (string, error) {
+	base, err := r.diffBase()
+	if err != nil {
+		return "", err
+	}
+	omitted, err := r.omittedFiles(false, base)
+	if err != nil {
+		return "", err
 	}
 
-	// Get summary of all changes
-	cmd = exec.Command("git", "-C", r.path, "diff", "HEAD", "--stat")
-	var summaryOut bytes.Buffer
-	cmd.Stdout = &summaryOut
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get changes summary: %w", err)
+	var filesOut, summaryOut bytes.Buffer
+	var diffOut string
+	var g errgroup.Group
+	g.Go(func() error {
+		return r.run(&filesOut, "failed to get changed files", "status", "--porcelain")
+	})
+	g.Go(func() error {
+		return r.run(&summaryOut, "failed to get changes summary", "diff", base, "--stat")
+	})
+	g.Go(func() (err error) {
+		diffOut, err = r.readDiff(append([]string{"diff", base}, excludePathspecs(nil, omitted)...)...)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return "", err
 	}
 
-	// Get actual diff of all changes
-	cmd = exec.Command("git", "-C", r.path, "diff", "HEAD")
-	var diffOut bytes.Buffer
-	cmd.Stdout = &diffOut
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get diff: %w", err)
+	if filesOut.Len() == 0 {
+		return "", nil
 	}
 
 	// Combine the information
@@ -136,117 +237,144 @@ func (r *Repository) GetAllChanges() (string, error) {
 	result.WriteString(filesOut.String())
 	result.WriteString("\n# Changes Summary:\n")
 	result.WriteString(summaryOut.String())
+	writeOmitted(&result, omitted)
 	result.WriteString("\n# Diff:\n")
-	result.WriteString(diffOut.String())
+	result.WriteString(diffOut)
 
 	return result.String(), nil
 }
 
-// GetRepositoryContext gathers context information about the repository
+// recentCommits is how many commit subjects GetRepositoryContext returns
+const recentCommits = 5
+
+// GetRepositoryContext gathers context information about the repository.
+// Only the repository path is required; the other details are best effort.
 func (r *Repository) GetRepositoryContext() (*RepositoryContext, error) {
 	context := &RepositoryContext{}
 
-	// Get repository name
-	cmd := exec.Command("git", "-C", r.path, "rev-parse", "--show-toplevel")
-	var repoPathOut bytes.Buffer
-	cmd.Stdout = &repoPathOut
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("failed to get repository path: %w", err)
-	}
-	repoPath := strings.TrimSpace(repoPathOut.String())
-	context.RepoName = filepath.Base(repoPath)
-
-	// Get current branch
-	cmd = exec.Command("git", "-C", r.path, "branch", "--show-current")
-	var branchOut bytes.Buffer
-	cmd.Stdout = &branchOut
-	if err := cmd.Run(); err == nil {
-		context.CurrentBranch = strings.TrimSpace(branchOut.String())
+	var repoPathOut, branchOut, filesOut, logOut bytes.Buffer
+	var g errgroup.Group
+	g.Go(func() error {
+		return r.run(&repoPathOut, "failed to get repository path", "rev-parse", "--show-toplevel")
+	})
+	g.Go(func() error {
+		r.run(&branchOut, "", "branch", "--show-current")
+		return nil
+	})
+	g.Go(func() error {
+		r.run(&filesOut, "", "ls-files")
+		return nil
+	})
+	g.Go(func() error {
+		context.InitialCommit = r.IsUnborn()
+		return nil
+	})
+	g.Go(func() error {
+		// One log serves both the last full message and the recent subjects
+		r.run(&logOut, "", "log", fmt.Sprintf("-%d", recentCommits), "--pretty=%B%x1e")
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
-	// Get last commit message
-	cmd = exec.Command("git", "-C", r.path, "log", "-1", "--pretty=%B")
-	var commitOut bytes.Buffer
-	cmd.Stdout = &commitOut
-	if err := cmd.Run(); err == nil {
-		context.LastCommitMsg = strings.TrimSpace(commitOut.String())
-	}
+	context.RepoName = filepath.Base(strings.TrimSpace(repoPathOut.String()))
+	context.CurrentBranch = strings.TrimSpace(branchOut.String())
+	context.BranchVars = branchVars(context.CurrentBranch)
 
 	// Get file types (extensions) in the repository
-	cmd = exec.Command("git", "-C", r.path, "ls-files")
-	var filesOut bytes.Buffer
-	cmd.Stdout = &filesOut
-	if err := cmd.Run(); err == nil {
-		files := strings.Split(strings.TrimSpace(filesOut.String()), "\n")
+	tracked := make(map[string]struct{})
+	if filesOut.Len() > 0 {
 		extensions := make(map[string]struct{})
-
-		for _, file := range files {
-			ext := filepath.Ext(file)
-			if ext != "" {
+		for _, file := range strings.Split(strings.TrimSpace(filesOut.String()), "\n") {
+			tracked[file] = struct{}{}
+			if ext := filepath.Ext(file); ext != "" {
 				extensions[ext] = struct{}{}
 			}
 		}
-
 		for ext := range extensions {
 			context.FileTypes = append(context.FileTypes, ext)
 		}
 	}
 
-	// Try to determine project type
-	if hasFile(r.path, "go.mod") {
+	// Try to determine project type from the tracked files
+	hasFile := func(name string) bool {
+		_, ok := tracked[name]
+		return ok
+	}
+	if hasFile("go.mod") {
 		context.ProjectType = "Go"
-	} else if hasFile(r.path, "package.json") {
+	} else if hasFile("package.json") {
 		context.ProjectType = "JavaScript/Node.js"
-	} else if hasFile(r.path, "Cargo.toml") {
+	} else if hasFile("Cargo.toml") {
 		context.ProjectType = "Rust"
-	} else if hasFile(r.path, "pom.xml") {
+	} else if hasFile("pom.xml") {
 		context.ProjectType = "Java"
-	} else if hasFile(r.path, "requirements.txt") || hasFile(r.path, "setup.py") {
+	} else if hasFile("requirements.txt") || hasFile("setup.py") || hasFile("pyproject.toml") {
 		context.ProjectType = "Python"
 	}
 
-	// Get recent commit messages
-	cmd = exec.Command("git", "-C", r.path, "log", "-5", "--pretty=%s")
-	var historyOut bytes.Buffer
-	cmd.Stdout = &historyOut
-	if err := cmd.Run(); err == nil {
-		history := strings.Split(strings.TrimSpace(historyOut.String()), "\n")
-		context.CommitHistory = history
+	// Recent commits, newest first: the full last message and each subject
+	for i, record := range strings.Split(logOut.String(), "\x1e") {
+		message := strings.TrimSpace(record)
+		if message == "" {
+			continue
+		}
+		if i == 0 {
+			context.LastCommitMsg = message
+		}
+		subject, _, _ := strings.Cut(message, "\n")
+		context.CommitHistory = append(context.CommitHistory, subject)
 	}
 
 	return context, nil
 }
 
 // Commit creates a new commit with the given message
+// When the repository is limited to paths, only they are committed and
+// other staged changes stay staged.
 func (r *Repository) Commit(message string) error {
-	cmd := exec.Command("git", "-C", r.path, "commit", "-m", message)
+	cmd := gitCommand(append([]string{"-C", r.path, "commit", "-m", message}, r.pathspecs()...)...)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to commit: %w", err)
 	}
 	return nil
 }
 
-// Helper function to check if a file exists in the repository
-func hasFile(repoPath, fileName string) bool {
-	cmd := exec.Command("git", "-C", repoPath, "ls-files", fileName)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	if err := cmd.Run(); err != nil {
-		return false
+// GetUserIdentity returns the configured git user, preferring the email address
+func (r *Repository) GetUserIdentity() string {
+	for _, key := range []string{"user.email", "user.name"} {
+		cmd := gitCommand("-C", r.path, "config", "--get", key)
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		if err := cmd.Run(); err == nil {
+			if value := strings.TrimSpace(out.String()); value != "" {
+				return value
+			}
+		}
 	}
-	return strings.TrimSpace(out.String()) != ""
+
+	return os.Getenv("USER")
 }
 
 // FileChange represents a changed file in the repository
 type FileChange struct {
-	Path   string // File path
-	Status string // Status code (A: added, M: modified, D: deleted, etc.)
+	Path     string // File path
+	Status   string // Status code (A: added, M: modified, D: deleted, etc.)
+	Staged   string // The staged change, e.g. "Modified", or "" if nothing is staged
+	Unstaged string // The change in the work tree that is not staged, or ""
 }
 
-// GetChangedFiles returns a list of files that have been changed
+// Untracked reports whether the file is new and not yet added
+func (c FileChange) Untracked() bool {
+	return c.Status == parseStatusCode("??")
+}
+
+// GetChangedFiles returns a list of files that have been changed. Staged
+// and Unstaged are set from the two columns of git status.
 func (r *Repository) GetChangedFiles() ([]FileChange, error) {
 	// Get list of changed files with status
-	cmd := exec.Command("git", "-C", r.path, "status", "--porcelain")
+	cmd := gitCommand(append([]string{"-C", r.path, "status", "--porcelain"}, r.pathspecs()...)...)
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	if err := cmd.Run(); err != nil {
@@ -257,33 +385,47 @@ func (r *Repository) GetChangedFiles() ([]FileChange, error) {
 		return []FileChange{}, nil
 	}
 
-	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	// Only the trailing newline is trimmed: a leading space is the first
+	// file's empty staged column
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
 	changes := make([]FileChange, 0, len(lines))
 
 	for _, line := range lines {
-		if len(line) < 3 {
+		if len(line) < 4 {
 			continue
 		}
 
 		statusCode := strings.TrimSpace(line[:2])
-		filePath := strings.TrimSpace(line[3:])
-
-		changes = append(changes, FileChange{
-			Path:   filePath,
+		change := FileChange{
+			Path:   line[3:],
 			Status: parseStatusCode(statusCode),
-		})
+		}
+		if statusCode != "??" && statusCode != "!!" {
+			change.Staged = columnStatus(line[0])
+			change.Unstaged = columnStatus(line[1])
+		}
+		changes = append(changes, change)
 	}
 
 	return changes, nil
 }
 
+// columnStatus converts one column of a git status code, where a space
+// means no change
+func columnStatus(code byte) string {
+	if code == ' ' {
+		return ""
+	}
+	return parseStatusCode(string(code))
+}
+
 // GetFileChanges returns the diff for a specific file
 func (r *Repository) GetFileChanges(filePath string) (string, error) {
 	// Check if file exists in repo
-	cmd := exec.Command("git", "-C", r.path, "ls-files", "--error-unmatch", filePath)
+	cmd := gitCommand("-C", r.path, "ls-files", "--error-unmatch", filePath)
 	if err := cmd.Run(); err != nil {
 		// Check if it's a new untracked file
-		cmd = exec.Command("git", "-C", r.path, "ls-files", "--others", "--exclude-standard", filePath)
+		cmd = gitCommand("-C", r.path, "ls-files", "--others", "--exclude-standard", filePath)
 		var out bytes.Buffer
 		cmd.Stdout = &out
 		if err := cmd.Run(); err != nil || out.Len() == 0 {
@@ -299,7 +441,11 @@ func (r *Repository) GetFileChanges(filePath string) (string, error) {
 	}
 
 	// Get diff for the file
-	cmd = exec.Command("git", "-C", r.path, "diff", "HEAD", "--", filePath)
+	base, err := r.diffBase()
+	if err != nil {
+		return "", err
+	}
+	cmd = gitCommand("-C", r.path, "diff", base, "--", filePath)
 	var diffOut bytes.Buffer
 	cmd.Stdout = &diffOut
 	if err := cmd.Run(); err != nil {
@@ -308,7 +454,7 @@ func (r *Repository) GetFileChanges(filePath string) (string, error) {
 
 	// If no changes in diff (might be staged only)
 	if diffOut.Len() == 0 {
-		cmd = exec.Command("git", "-C", r.path, "diff", "--cached", "--", filePath)
+		cmd = gitCommand("-C", r.path, "diff", "--cached", "--", filePath)
 		cmd.Stdout = &diffOut
 		if err := cmd.Run(); err != nil {
 			return "", fmt.Errorf("failed to get staged file diff: %w", err)
@@ -357,18 +503,36 @@ func parseStatusCode(code string) string {
 
 // GetCommitHistory gets commit history since a specific date
 type Commit struct {
-	Hash    string
-	Author  string
-	Date    time.Time
-	Message string
+	Hash      string
+	Author    string
+	Date      time.Time
+	Message   string
+	Additions int // Lines added, excluding binary files
+	Deletions int // Lines deleted, excluding binary files
 }
 
 func (r *Repository) GetCommitHistory(since time.Time) ([]Commit, error) {
-	// Format the date for git command
-	sinceStr := since.Format("2006-01-02")
+	return r.GetCommitHistoryBetween(since, time.Time{})
+}
+
+// GetCommitHistoryBetween gets commit history between two dates.
+// A zero until includes everything up to now.
+func (r *Repository) GetCommitHistoryBetween(since, until time.Time) ([]Commit, error) {
+	// A branch without commits has no history rather than a broken HEAD
+	if r.IsUnborn() {
+		return []Commit{}, nil
+	}
+
+	// Format the dates for git command
+	args := []string{"-C", r.path, "log", "--since=" + since.Format("2006-01-02")}
+	if !until.IsZero() {
+		args = append(args, "--until="+until.Format(time.RFC3339))
+	}
+	// Each commit starts with a record separator so its numstat lines can be grouped with it
+	args = append(args, "--pretty=format:\x1e%H|%an|%ad|%s", "--date=iso", "--numstat")
 
 	// Get commits
-	cmd := exec.Command("git", "-C", r.path, "log", "--since="+sinceStr, "--pretty=format:%H|%an|%ad|%s", "--date=iso")
+	cmd := gitCommand(args...)
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	if err := cmd.Run(); err != nil {
@@ -379,11 +543,12 @@ func (r *Repository) GetCommitHistory(since time.Time) ([]Commit, error) {
 		return []Commit{}, nil
 	}
 
-	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
-	commits := make([]Commit, 0, len(lines))
+	records := strings.Split(out.String(), "\x1e")
+	commits := make([]Commit, 0, len(records))
 
-	for _, line := range lines {
-		parts := strings.SplitN(line, "|", 4)
+	for _, record := range records {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		parts := strings.SplitN(lines[0], "|", 4)
 		if len(parts) < 4 {
 			continue
 		}
@@ -399,13 +564,102 @@ func (r *Repository) GetCommitHistory(since time.Time) ([]Commit, error) {
 			}
 		}
 
+		additions, deletions := parseNumstat(lines[1:])
+		commits = append(commits, Commit{
+			Hash:      parts[0],
+			Author:    parts[1],
+			Date:      date,
+			Message:   parts[3],
+			Additions: additions,
+			Deletions: deletions,
+		})
+	}
+
+	return commits, nil
+}
+
+// RecentMessages returns the full messages of the last n non-merge commits
+// that touched any of the paths, newest first
+func (r *Repository) RecentMessages(n int, paths ...string) ([]string, error) {
+	var out bytes.Buffer
+	args := append([]string{"log", "--no-merges", fmt.Sprintf("-%d", n), "--pretty=%B%x1e", "--"}, paths...)
+	if err := r.run(&out, "failed to read commit history", args...); err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for _, record := range strings.Split(out.String(), "\x1e") {
+		if message := strings.TrimSpace(record); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// checkRevision rejects a user-supplied revision or range that git would
+// take for an option, such as --output=<file>
+func checkRevision(rev string) error {
+	if strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("invalid revision %q: revisions cannot start with '-'", rev)
+	}
+	return nil
+}
+
+// GetCommitRange returns the non-merge commits in a revision range such as
+// "v1.0.0..HEAD", newest first, with their full messages
+func (r *Repository) GetCommitRange(revRange string) ([]Commit, error) {
+	if err := checkRevision(revRange); err != nil {
+		return nil, err
+	}
+
+	// Fields are separated by unit separators and commits by record separators,
+	// since full messages can contain any other character
+	cmd := gitCommand("-C", r.path, "log", "--no-merges", "--date=iso",
+		"--pretty=format:%H%x1f%an%x1f%ad%x1f%B%x1e", revRange, "--")
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to read commit range %s: %s", revRange, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out.String(), "\x1e") {
+		parts := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 4)
+		if len(parts) < 4 {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02 15:04:05 -0700", parts[2])
+		if err != nil {
+			date = time.Time{}
+		}
+
 		commits = append(commits, Commit{
 			Hash:    parts[0],
 			Author:  parts[1],
 			Date:    date,
-			Message: parts[3],
+			Message: strings.TrimSpace(parts[3]),
 		})
 	}
 
 	return commits, nil
 }
+
+// parseNumstat totals the added and deleted lines from 'git log --numstat'
+// output. Binary files are reported as "-" and are skipped.
+func parseNumstat(lines []string) (additions, deletions int) {
+	for _, line := range lines {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		if added, err := strconv.Atoi(fields[0]); err == nil {
+			additions += added
+		}
+		if deleted, err := strconv.Atoi(fields[1]); err == nil {
+			deletions += deleted
+		}
+	}
+	return additions, deletions
+}
@@ -0,0 +1,183 @@
+// internal/git/sequencer.go
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// revertSearchDepth is how many recent commits RevertedCommit compares the
+// staged changes with
+const revertSearchDepth = 20
+
+// Merge is a merge that has been started but not committed
+type Merge struct {
+	Heads   []string // The commits being merged in
+	Message string   // The message git prepared, without comment lines
+}
+
+// gitPath returns the path of a file in the repository's git directory,
+// such as MERGE_HEAD, which differs between worktrees
+func (r *Repository) gitPath(name string) (string, error) {
+	path, err := r.output("rev-parse", "--git-path", name)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s: %w", name, err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.path, path)
+	}
+	return path, nil
+}
+
+// readGitFile reads a file in the git directory, returning "" when it does
+// not exist
+func (r *Repository) readGitFile(name string) (string, error) {
+	path, err := r.gitPath(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// MergeInProgress returns the merge waiting to be committed, or nil
+func (r *Repository) MergeInProgress() (*Merge, error) {
+	heads, err := r.readGitFile("MERGE_HEAD")
+	if err != nil || strings.TrimSpace(heads) == "" {
+		return nil, err
+	}
+	message, err := r.readGitFile("MERGE_MSG")
+	if err != nil {
+		return nil, err
+	}
+	return &Merge{Heads: strings.Fields(heads), Message: stripComments(message)}, nil
+}
+
+// RevertedCommit returns the hash of the commit the staged changes revert:
+// the one an unfinished 'git revert --no-commit' names, or a recent commit
+// whose changes the staged diff exactly undoes. It returns "" when there is
+// none.
+func (r *Repository) RevertedCommit() (string, error) {
+	if head, err := r.readGitFile("REVERT_HEAD"); err != nil || strings.TrimSpace(head) != "" {
+		return strings.TrimSpace(head), err
+	}
+	if r.IsUnborn() {
+		return "", nil
+	}
+
+	staged, err := r.output("diff", "--cached", "--name-only", "-z")
+	if err != nil || staged == "" {
+		return "", err
+	}
+	stagedFiles := strings.Trim(staged, "\x00")
+
+	// Only commits that touched the same files can be undone by the diff,
+	// which spares computing a patch ID for every one
+	var out bytes.Buffer
+	if err := r.run(&out, "failed to read commit history", "log", "--no-merges", "--min-parents=1",
+		fmt.Sprintf("-%d", revertSearchDepth), "--no-renames", "--format=%x1e%H", "--name-only", "-z"); err != nil {
+		return "", err
+	}
+
+	var stagedID string
+	for _, record := range strings.Split(out.String(), "\x1e") {
+		// Each record is the hash and a NUL, then the files on the next line
+		hash, files, _ := strings.Cut(record, "\n")
+		hash = strings.Trim(hash, "\x00")
+		if hash == "" || !sameFiles(stagedFiles, files) {
+			continue
+		}
+
+		if stagedID == "" {
+			if stagedID, err = r.patchID("diff", "--cached", "--no-renames"); err != nil || stagedID == "" {
+				return "", err
+			}
+		}
+		// The diff from a commit back to its parent undoes it
+		revertID, err := r.patchID("diff", "--no-renames", hash, hash+"^")
+		if err != nil {
+			return "", err
+		}
+		if revertID == stagedID {
+			return hash, nil
+		}
+	}
+	return "", nil
+}
+
+// sameFiles compares NUL-separated file lists, ignoring order
+func sameFiles(a, b string) bool {
+	split := func(s string) map[string]bool {
+		files := make(map[string]bool)
+		for _, name := range strings.Split(strings.Trim(s, "\x00\n"), "\x00") {
+			if name = strings.TrimSpace(name); name != "" {
+				files[name] = true
+			}
+		}
+		return files
+	}
+	filesA, filesB := split(a), split(b)
+	if len(filesA) != len(filesB) {
+		return false
+	}
+	for name := range filesA {
+		if !filesB[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// patchID returns the stable patch ID of the diff a git command prints,
+// which is the same for equal changes wherever they are in the history
+func (r *Repository) patchID(diffArgs ...string) (string, error) {
+	var diff bytes.Buffer
+	if err := r.run(&diff, "failed to read diff", append(diffArgs, "--full-index", "--no-color")...); err != nil {
+		return "", err
+	}
+	if diff.Len() == 0 {
+		return "", nil
+	}
+
+	cmd := gitCommand("-C", r.path, "patch-id", "--stable")
+	cmd.Stdin = &diff
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to compute patch ID: %w", err)
+	}
+	id, _, _ := strings.Cut(strings.TrimSpace(out.String()), " ")
+	return id, nil
+}
+
+// CommitSubject returns the first line of a commit's message
+func (r *Repository) CommitSubject(rev string) (string, error) {
+	if err := checkRevision(rev); err != nil {
+		return "", err
+	}
+	subject, err := r.output("log", "-1", "--format=%s", rev)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit %s: %w", rev, err)
+	}
+	return subject, nil
+}
+
+// stripComments removes the '#' lines git adds to messages it prepares
+func stripComments(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,95 @@
+// internal/git/stash.go
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Stash is an entry of the stash list
+type Stash struct {
+	Ref     string // e.g. stash@{0}
+	Hash    string
+	Message string // e.g. "WIP on main: 1a2b3c4 Add the login form"
+}
+
+// stashMessage matches the "WIP on <branch>: " prefix git gives stashes
+// without a message, and the "On <branch>: " prefix of named ones
+var stashMessage = regexp.MustCompile(`^(WIP on|On) ([^:]+): `)
+
+// Branch returns the branch the stash was made on, if its message says
+func (s Stash) Branch() string {
+	if m := stashMessage.FindStringSubmatch(s.Message); m != nil {
+		return m[2]
+	}
+	return ""
+}
+
+// Unnamed reports whether the stash still has the message git made up,
+// which only names the commit it was based on
+func (s Stash) Unnamed() bool {
+	return strings.HasPrefix(s.Message, "WIP on ")
+}
+
+// Stashes returns the stash list, newest first
+func (r *Repository) Stashes() ([]Stash, error) {
+	var out bytes.Buffer
+	if err := r.run(&out, "failed to list stashes", "stash", "list", "--format=%gd%x1f%H%x1f%gs"); err != nil {
+		return nil, err
+	}
+
+	var stashes []Stash
+	for _, line := range strings.Split(out.String(), "\n") {
+		parts := strings.SplitN(line, "\x1f", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		stashes = append(stashes, Stash{Ref: parts[0], Hash: parts[1], Message: parts[2]})
+	}
+	return stashes, nil
+}
+
+// StashDiff returns the changes to tracked files a stash holds, cut like a
+// staged diff
+func (r *Repository) StashDiff(ref string) (string, error) {
+	if err := checkRevision(ref); err != nil {
+		return "", err
+	}
+	return r.readDiff("stash", "show", "--patch", "--no-color", ref)
+}
+
+// RenameStashes replaces the messages of the stashes whose hashes are keys
+// of messages. Git cannot edit a stash message in place, so the stash list is
+// rebuilt in its original order. If that fails part way, the error names the
+// stashes to restore with 'git stash store'.
+func (r *Repository) RenameStashes(messages map[string]string) error {
+	stashes, err := r.Stashes()
+	if err != nil || len(stashes) == 0 {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := r.run(&out, "failed to clear the stash list", "update-ref", "-d", "refs/stash"); err != nil {
+		return err
+	}
+
+	// Storing pushes onto the list, so the oldest goes first
+	for i := len(stashes) - 1; i >= 0; i-- {
+		stash := stashes[i]
+		message, ok := messages[stash.Hash]
+		if !ok {
+			message = stash.Message
+		}
+		if err := r.run(&out, "failed to store stash", "stash", "store", "-m", message, stash.Hash); err != nil {
+			var missing []string
+			for j := i; j >= 0; j-- {
+				missing = append(missing, stashes[j].Hash)
+			}
+			return fmt.Errorf("%w; restore the remaining stashes, in this order, with 'git stash store': %s",
+				err, strings.Join(missing, " "))
+		}
+	}
+	return nil
+}
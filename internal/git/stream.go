@@ -0,0 +1,144 @@
+// internal/git/stream.go
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// maxDiffBytes bounds the diff text kept in memory for a prompt; 0 keeps
+// everything
+var maxDiffBytes = 1 << 20
+
+// SetMaxDiffBytes sets how much of a diff GetStagedChanges and
+// GetAllChanges keep. Files beyond the limit are listed without their hunks.
+func SetMaxDiffBytes(n int) {
+	maxDiffBytes = n
+}
+
+// StagedDiffReader streams 'git diff --cached' without holding it in memory,
+// limited to the paths or else to those the repository is limited to.
+// Closing the reader waits for git to exit and reports its failure.
+func (r *Repository) StagedDiffReader(paths ...string) (io.ReadCloser, error) {
+	if len(paths) == 0 {
+		paths = r.paths
+	}
+	return r.stream("failed to get diff", append([]string{"diff", "--cached", "--"}, paths...)...)
+}
+
+// CommitDiffReader streams the full diff a commit introduced, where
+// CommitDiff keeps only what fits in a prompt
+func (r *Repository) CommitDiffReader(rev string) (io.ReadCloser, error) {
+	if err := checkRevision(rev); err != nil {
+		return nil, err
+	}
+	return r.stream("failed to get diff", "show", "--no-color", "--format=", rev, "--")
+}
+
+// stream starts a git command and returns its stdout
+func (r *Repository) stream(what string, args ...string) (io.ReadCloser, error) {
+	cmd := gitCommand(append([]string{"-C", r.path}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", what, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%s: %w", what, err)
+	}
+	return &commandReader{ReadCloser: out, cmd: cmd, stderr: &stderr, what: what}, nil
+}
+
+// readDiff streams a diff command through ReadDiff, so at most
+// maxDiffBytes of it is held in memory
+func (r *Repository) readDiff(args ...string) (string, error) {
+	out, err := r.stream("failed to get diff", args...)
+	if err != nil {
+		return "", err
+	}
+
+	diff, truncated, err := ReadDiff(out, maxDiffBytes)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if truncated {
+		logger.Info("Diff truncated to %d bytes (max_diff_bytes); later files are listed without their changes", maxDiffBytes)
+	}
+	return diff, nil
+}
+
+// commandReader is the stdout of a running git command
+type commandReader struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+	what   string
+}
+
+// Close stops reading and waits for the command to exit. Output left
+// unread makes git exit early, which is not an error.
+func (c *commandReader) Close() error {
+	c.ReadCloser.Close()
+	if err := c.cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(c.stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", c.what, msg)
+		}
+		if c.cmd.ProcessState != nil && !c.cmd.ProcessState.Exited() {
+			return nil // Stopped by the closed pipe
+		}
+		return fmt.Errorf("%s: %w", c.what, err)
+	}
+	return nil
+}
+
+// ReadDiff reads a unified diff, keeping at most maxBytes of it (0 for no
+// limit). Once the limit is reached only the headers of further files are
+// kept, followed by a note of how many lines were left out, so the reader
+// still learns every file that changed. It reports whether it truncated.
+func ReadDiff(r io.Reader, maxBytes int) (string, bool, error) {
+	var sb strings.Builder
+	reader := bufio.NewReader(r)
+	omitted := 0
+	truncated := false
+
+	flushOmitted := func() {
+		if omitted > 0 {
+			fmt.Fprintf(&sb, "[... %d lines omitted ...]\n", omitted)
+			omitted = 0
+		}
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			full := maxBytes > 0 && sb.Len()+len(line) > maxBytes
+			switch {
+			case !full && omitted == 0:
+				sb.WriteString(line)
+			case strings.HasPrefix(line, "diff --git "):
+				flushOmitted()
+				sb.WriteString(line)
+			default:
+				omitted++
+				truncated = true
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	flushOmitted()
+	return sb.String(), truncated, nil
+}
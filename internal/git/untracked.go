@@ -0,0 +1,128 @@
+// internal/git/untracked.go
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Limits on the untracked file contents added to a prompt
+const (
+	maxUntrackedFileBytes  = 8 << 10
+	maxUntrackedTotalBytes = 64 << 10
+)
+
+// includeUntracked adds untracked files to GetStagedChanges
+var includeUntracked = false
+
+// SetIncludeUntracked sets whether GetStagedChanges also lists untracked
+// files, not ignored by .gitignore, with their contents as new-file diffs
+func SetIncludeUntracked(include bool) {
+	includeUntracked = include
+}
+
+// UntrackedFiles returns the untracked files that are not ignored, relative
+// to the top of the work tree
+func (r *Repository) UntrackedFiles() ([]string, error) {
+	var out bytes.Buffer
+	if err := r.run(&out, "failed to list untracked files",
+		"ls-files", "--others", "--exclude-standard", "--full-name", "-z", "--", ":(top)"); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, name := range strings.Split(out.String(), "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// UntrackedDiff renders the untracked files as new-file diffs when
+// SetIncludeUntracked is on and the repository is not limited to paths, and
+// returns "" otherwise. Each file is cut to
+// a few kilobytes and binary files are only named. Files excluded from
+// prompt diffs are left out.
+func (r *Repository) UntrackedDiff() (string, error) {
+	// Chosen paths are staged before their changes are read
+	if !includeUntracked || len(r.paths) > 0 {
+		return "", nil
+	}
+
+	files, err := r.UntrackedFiles()
+	if err != nil || len(files) == 0 {
+		return "", err
+	}
+	omitted, err := r.filterOmitted(false, files)
+	if err != nil {
+		return "", err
+	}
+	skip := make(map[string]bool, len(omitted))
+	for _, file := range omitted {
+		skip[file] = true
+	}
+
+	root, err := r.Root()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, file := range files {
+		if skip[file] {
+			continue
+		}
+		if sb.Len() >= maxUntrackedTotalBytes {
+			fmt.Fprintf(&sb, "diff --git a/%s b/%s\nnew file (untracked, contents omitted)\n", file, file)
+			continue
+		}
+
+		data, err := readUntracked(filepath.Join(root, filepath.FromSlash(file)))
+		if err != nil {
+			// Files can vanish or be unreadable; they are only context
+			continue
+		}
+		writeNewFileDiff(&sb, file, data)
+	}
+	return sb.String(), nil
+}
+
+// readUntracked reads at most one byte more than maxUntrackedFileBytes, so
+// writeNewFileDiff can tell the file was truncated without loading all of it
+func readUntracked(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(io.LimitReader(f, maxUntrackedFileBytes+1))
+}
+
+// writeNewFileDiff writes a file's contents as a diff that adds it
+func writeNewFileDiff(sb *strings.Builder, file string, data []byte) {
+	fmt.Fprintf(sb, "diff --git a/%s b/%s\nnew file mode 100644\n", file, file)
+	if bytes.IndexByte(data, 0) >= 0 {
+		fmt.Fprintf(sb, "Binary files /dev/null and b/%s differ\n", file)
+		return
+	}
+
+	truncated := len(data) > maxUntrackedFileBytes
+	if truncated {
+		data = data[:maxUntrackedFileBytes]
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	fmt.Fprintf(sb, "--- /dev/null\n+++ b/%s\n@@ -0,0 +1,%d @@\n", file, len(lines))
+	for _, line := range lines {
+		sb.WriteString("+" + line + "\n")
+	}
+	if truncated {
+		sb.WriteString("[... rest of the file omitted ...]\n")
+	}
+}
@@ -2,22 +2,30 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/jasonKoogler/comma/internal/logging"
 )
 
-// generateWithAnthropic calls the Anthropic API to generate a commit message
-func (c *Client) generateWithAnthropic(prompt string, maxTokens int) (string, error) {
+// generateWithAnthropic calls the Anthropic API to generate a commit message.
+// Structured requests force a tool call whose input is the message as JSON.
+func (c *Client) generateWithAnthropic(ctx context.Context, prompt string, maxTokens int, structured bool) (string, error) {
 	// Respect rate limit
-	<-c.rateLimiter.C
+	select {
+	case <-c.rateLimiter.C:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
 
 	// Use default model if not specified
 	model := c.model
 	if model == "" {
-		model = "claude-3-opus-20240229"
+		model = defaultModels["anthropic"]
 	}
 
 	// Prepare request
@@ -25,6 +33,7 @@ func (c *Client) generateWithAnthropic(prompt string, maxTokens int) (string, er
 		"model":       model,
 		"max_tokens":  maxTokens,
 		"temperature": c.temperature,
+		"system":      c.systemPrompt,
 		"messages": []map[string]string{
 			{
 				"role":    "user",
@@ -33,24 +42,37 @@ func (c *Client) generateWithAnthropic(prompt string, maxTokens int) (string, er
 		},
 	}
 
+	if structured {
+		requestBody["tools"] = []map[string]interface{}{{
+			"name":         structuredToolName,
+			"description":  "Record the commit message",
+			"input_schema": structuredSchema,
+		}}
+		requestBody["tool_choice"] = map[string]string{"type": "tool", "name": structuredToolName}
+	}
+
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
 	// Create request
-	req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
+	if id := logging.TraceID(); id != "" {
+		req.Header.Set(logging.TraceHeader, id)
+	}
 	req.Header.Set("x-api-key", c.apiKey)
 	req.Header.Set("anthropic-version", "2023-06-01")
 
 	// Execute request with retry
-	httpClient := &http.Client{Timeout: 60 * time.Second}
+	// The context carries llm.timeout
+	httpClient := &http.Client{}
 	var resp *http.Response
 	maxRetries := 3
 
@@ -64,16 +86,20 @@ func (c *Client) generateWithAnthropic(prompt string, maxTokens int) (string, er
 			bodyBytes, _ := io.ReadAll(resp.Body)
 			resp.Body.Close()
 
-			// Print detailed error for debugging
+			// Log detailed error for debugging
 			if resp.StatusCode != http.StatusOK {
-				fmt.Printf("Anthropic API error (attempt %d/%d): Status %d, Body: %s\n",
+				logger.Warn("Anthropic API error (attempt %d/%d): Status %d, Body: %s",
 					i+1, maxRetries, resp.StatusCode, string(bodyBytes))
 			}
 		}
 
 		if i < maxRetries-1 {
 			// Exponential backoff
-			time.Sleep(time.Duration((1<<i)*500) * time.Millisecond)
+			select {
+			case <-time.After(time.Duration((1<<i)*500) * time.Millisecond):
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
 		}
 	}
 
@@ -95,8 +121,9 @@ func (c *Client) generateWithAnthropic(prompt string, maxTokens int) (string, er
 		Type    string `json:"type"`
 		Role    string `json:"role"`
 		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Input json.RawMessage `json:"input"`
 		} `json:"content"`
 		Model        string `json:"model"`
 		StopReason   string `json:"stop_reason"`
@@ -125,8 +152,13 @@ func (c *Client) generateWithAnthropic(prompt string, maxTokens int) (string, er
 		return "", fmt.Errorf("API error: %s", response.Error.Message)
 	}
 
-	// Extract message from the text content
+	c.lastUsage = &Usage{PromptTokens: response.Usage.InputTokens, CompletionTokens: response.Usage.OutputTokens}
+
+	// Extract message from the tool call or the text content
 	for _, content := range response.Content {
+		if content.Type == "tool_use" && len(content.Input) > 0 {
+			return string(content.Input), nil
+		}
 		if content.Type == "text" {
 			return content.Text, nil
 		}
@@ -2,11 +2,15 @@
 package llm
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/jasonKoogler/comma/internal/ui"
 	"github.com/jasonKoogler/comma/internal/vault"
 )
 
@@ -19,6 +23,11 @@ type ConfigProvider interface {
 	Set(key string, value interface{})
 }
 
+// ProviderPolicy decides whether a provider may be used, e.g. per team policy
+type ProviderPolicy interface {
+	CheckProvider(provider string) error
+}
+
 // Constants for configuration keys
 const (
 	LLMProviderKey            = "llm.provider"
@@ -27,12 +36,24 @@ const (
 	LLMAPIKeyKey              = "llm.api_key"
 	LLMTemperatureKey         = "llm.temperature"
 	LLMMaxTokensKey           = "llm.max_tokens"
+	LLMOfflineKey             = "llm.offline"
+	LLMLanguageKey            = "llm.language"
+	LLMRaceProvidersKey       = "llm.race_providers"
+	LLMSystemPromptKey        = "llm.system_prompt"
+	LLMTimeoutKey             = "llm.timeout"
 	ConfigDirKey              = "config_dir"
 	TemplateKey               = "template"
 	IncludeDiffKey            = "include_diff"
 	AnalysisSmartDetectionKey = "analysis.enable_smart_detection"
 )
 
+// DefaultSystemPrompt is the system prompt used unless llm.system_prompt
+// replaces it
+const DefaultSystemPrompt = "You are a helpful assistant that generates concise and descriptive git commit messages."
+
+// DefaultTimeout bounds a request when llm.timeout is not set
+const DefaultTimeout = 60 * time.Second
+
 // Client represents an LLM API client
 type Client struct {
 	provider       string
@@ -40,101 +61,185 @@ type Client struct {
 	endpoint       string
 	model          string
 	temperature    float64
+	systemPrompt   string
+	timeout        time.Duration
 	rateLimiter    *time.Ticker
 	credManager    *vault.CredentialManager
 	configProvider ConfigProvider
+
+	rivals     []*Client // Raced against this client with llm.race_providers
+	answeredBy *Client   // The client whose response was used last
+	lastUsage  *Usage    // Tokens reported for this client's last response
 }
 
-// NewClient creates a new LLM client
-func NewClient(credManager *vault.CredentialManager, configProvider ConfigProvider) (*Client, error) {
-	provider := configProvider.GetString(LLMProviderKey)
+// NewClient creates a new LLM client. If a policy is given, providers it
+// rejects are refused before any credentials are looked up.
+func NewClient(credManager *vault.CredentialManager, configProvider ConfigProvider, policy ProviderPolicy) (*Client, error) {
+	provider := ActiveProvider(configProvider)
 
-	// Get API key securely
-	apiKey, err := getSecureAPIKey(provider, credManager, configProvider)
-	if err != nil {
-		return nil, fmt.Errorf("configuration error: API key is required for %s provider (set in config or use %s_API_KEY env var)",
-			provider, strings.ToUpper(provider))
+	if policy != nil {
+		if err := policy.CheckProvider(provider); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get API key securely (local models and plugin providers that
+	// don't ask for one go without)
+	var apiKey string
+	if needsAPIKey(provider) {
+		var err error
+		apiKey, err = getSecureAPIKey(provider, credManager, configProvider)
+		if err != nil {
+			return nil, fmt.Errorf("configuration error: API key is required for %s provider (set in config or use %s_API_KEY env var)",
+				provider, strings.ToUpper(provider))
+		}
 	}
 
 	// Set the correct endpoint based on provider
 	endpoint := configProvider.GetString(LLMEndpointKey)
-	// Always update endpoint when provider changes to ensure proper defaults
-
 	// Always ensure the endpoint matches the provider
-	switch provider {
-	case "anthropic":
-		if !strings.Contains(endpoint, "anthropic.com") {
-			endpoint = "https://api.anthropic.com/v1/messages"
-			configProvider.Set(LLMEndpointKey, endpoint)
-		}
-	case "openai":
-		if !strings.Contains(endpoint, "openai.com") {
-			endpoint = "https://api.openai.com/v1/chat/completions"
-			configProvider.Set(LLMEndpointKey, endpoint)
-		}
-	case "mistral":
-		if !strings.Contains(endpoint, "mistral.ai") {
-			endpoint = "https://api.mistral.ai/v1/chat/completions"
-			configProvider.Set(LLMEndpointKey, endpoint)
-		}
-	case "google":
-		if !strings.Contains(endpoint, "googleapis.com") {
-			endpoint = "https://generativelanguage.googleapis.com/v1beta/models"
-			configProvider.Set(LLMEndpointKey, endpoint)
+	if def, ok := providerEndpoints[provider]; ok && !strings.Contains(endpoint, def.host) {
+		if endpoint != "" {
+			ui.Notify(ui.NoticeWarning, "Ignoring %s %s: %s uses %s", LLMEndpointKey, endpoint, provider, def.url)
 		}
+		endpoint = def.url
+		configProvider.Set(LLMEndpointKey, endpoint)
+	}
+	if provider == "local-server" {
+		endpoint = localServerEndpoint(endpoint)
+		apiKey = localServerAPIKey(credManager)
 	}
 
 	// Create rate limiter (1 request per second)
 	rateLimiter := time.NewTicker(time.Second)
 
-	return &Client{
+	client := &Client{
 		provider:       provider,
 		apiKey:         apiKey,
 		endpoint:       endpoint,
 		model:          configProvider.GetString(LLMModelKey),
 		temperature:    configProvider.GetFloat64(LLMTemperatureKey),
+		systemPrompt:   SystemPrompt(configProvider),
+		timeout:        requestTimeout(configProvider),
 		rateLimiter:    rateLimiter,
 		credManager:    credManager,
 		configProvider: configProvider,
-	}, nil
+	}
+
+	// Offline mode never races: nothing may leave the machine
+	if !configProvider.GetBool(LLMOfflineKey) {
+		client.rivals = newRivals(client, policy)
+	} else if configured := configProvider.GetString(LLMProviderKey); configured != "" && configured != provider {
+		ui.Notify(ui.NoticeInfo, "Offline mode: using the %s model instead of %s", provider, configured)
+	}
+
+	return client, nil
 }
 
-// getProviderAPIEnvVar returns the environment variable name for a given provider
-func getProviderAPIEnvVar(provider string) string {
-	return fmt.Sprintf("%s_API_KEY", strings.ToUpper(provider))
+// providerEndpoints are the API endpoints of the built-in providers, with the
+// host a configured endpoint must point at to be kept
+var providerEndpoints = map[string]struct{ host, url string }{
+	"anthropic": {"anthropic.com", "https://api.anthropic.com/v1/messages"},
+	"openai":    {"openai.com", "https://api.openai.com/v1/chat/completions"},
+	"mistral":   {"mistral.ai", "https://api.mistral.ai/v1/chat/completions"},
+	"groq":      {"groq.com", "https://api.groq.com/openai/v1/chat/completions"},
+	"deepseek":  {"deepseek.com", "https://api.deepseek.com/chat/completions"},
+	"xai":       {"x.ai", "https://api.x.ai/v1/chat/completions"},
+	"google":    {"googleapis.com", "https://generativelanguage.googleapis.com/v1beta/models"},
 }
 
-// getSecureAPIKey tries to get API key from secure storage
-func getSecureAPIKey(provider string, credManager *vault.CredentialManager, configProvider ConfigProvider) (string, error) {
-	// First try to get from vault
-	apiKey, err := credManager.Retrieve(provider)
-	if err == nil && apiKey != "" {
-		return apiKey, nil
+// defaultModels are the models used when none is configured
+var defaultModels = map[string]string{
+	"openai":    "gpt-4",
+	"anthropic": "claude-3-opus-20240229",
+	"groq":      "llama-3.3-70b-versatile",
+	"deepseek":  "deepseek-chat",
+	"xai":       "grok-3-mini",
+}
+
+// DefaultLocalServerURL is where the local-server provider looks for an
+// OpenAI-compatible server when llm.endpoint points at a hosted provider
+const DefaultLocalServerURL = "http://localhost:8080/v1"
+
+// localServerEndpoint turns the base URL of an OpenAI-compatible server, such
+// as LM Studio's "http://localhost:1234/v1", into its chat completions URL
+func localServerEndpoint(base string) string {
+	for _, def := range providerEndpoints {
+		if strings.Contains(base, def.host) {
+			base = ""
+			break
+		}
+	}
+	if base == "" {
+		base = DefaultLocalServerURL
+	}
+
+	base = strings.TrimSuffix(base, "/")
+	if strings.HasSuffix(base, "/chat/completions") {
+		return base
+	}
+	return base + "/chat/completions"
+}
+
+// localServerAPIKey returns the optional key for a local server, e.g. a
+// LiteLLM proxy's, from LOCAL_SERVER_API_KEY or secure storage. Other
+// providers' keys are never sent to it.
+func localServerAPIKey(credManager *vault.CredentialManager) string {
+	if key := getEnv(getProviderAPIEnvVar("local-server"), ""); key != "" {
+		return key
 	}
+	if credManager != nil {
+		if key, err := credManager.Retrieve("local-server"); err == nil {
+			return key
+		}
+	}
+	return ""
+}
 
-	// Check if the API key is set in config under api_keys.provider
-	apiKey = configProvider.GetString(fmt.Sprintf("api_keys.%s", provider))
-	if apiKey != "" && apiKey != "set" {
-		// Save to vault for future use
-		credManager.Store(provider, apiKey)
-		return apiKey, nil
+// requestTimeout returns llm.timeout, given in seconds, or DefaultTimeout
+func requestTimeout(configProvider ConfigProvider) time.Duration {
+	if seconds := configProvider.GetInt(LLMTimeoutKey); seconds > 0 {
+		return time.Duration(seconds) * time.Second
 	}
+	return DefaultTimeout
+}
 
-	// Fall back to llm.api_key
-	apiKey = configProvider.GetString(LLMAPIKeyKey)
-	if apiKey != "" {
-		// Save to vault for future use
-		credManager.Store(provider, apiKey)
-		return apiKey, nil
+// SystemPrompt returns the configured system prompt or the default
+func SystemPrompt(configProvider ConfigProvider) string {
+	if prompt := strings.TrimSpace(configProvider.GetString(LLMSystemPromptKey)); prompt != "" {
+		return prompt
 	}
+	return DefaultSystemPrompt
+}
+
+// ActiveProvider returns the provider to use. Offline mode always uses the
+// local model so that no request leaves the machine.
+func ActiveProvider(configProvider ConfigProvider) string {
+	if configProvider.GetBool(LLMOfflineKey) {
+		return "local"
+	}
+	return configProvider.GetString(LLMProviderKey)
+}
+
+// getProviderAPIEnvVar returns the environment variable name for a given provider
+func getProviderAPIEnvVar(provider string) string {
+	return fmt.Sprintf("%s_API_KEY", strings.ToUpper(strings.ReplaceAll(provider, "-", "_")))
+}
 
-	// Try standard env vars (OPENAI_API_KEY, ANTHROPIC_API_KEY, etc.)
-	envVar := getProviderAPIEnvVar(provider)
-	apiKey = getEnv(envVar, "")
-	if apiKey != "" {
-		// Save to vault for future use
-		credManager.Store(provider, apiKey)
-		return apiKey, nil
+// getSecureAPIKey tries to get API key from secure storage, then from the
+// config and environment. Keys found in the config are saved to the vault
+// for future use.
+func getSecureAPIKey(provider string, credManager *vault.CredentialManager, configProvider ConfigProvider) (string, error) {
+	for _, source := range APIKeySources(provider, credManager, configProvider) {
+		if source.Key == "" {
+			continue
+		}
+		if source.Name == KeySourceConfig || source.Name == KeySourceLLM {
+			if err := credManager.Store(provider, source.Key); err == nil {
+				ui.Notify(ui.NoticeSuccess, "Copied the %s API key from %s to the credential store (%s)", provider, source.Detail, credManager.Location(provider))
+			}
+		}
+		return source.Key, nil
 	}
 
 	return "", fmt.Errorf("no API key found for %s", provider)
@@ -149,27 +254,104 @@ func getEnv(key, fallback string) string {
 	return value
 }
 
-// GenerateCommitMessage generates a commit message using the LLM
-func (c *Client) GenerateCommitMessage(prompt string, maxTokens int) (string, error) {
+// GenerateCommitMessage generates a commit message using the LLM. Cancelling
+// ctx aborts the request.
+func (c *Client) GenerateCommitMessage(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	return c.complete(ctx, prompt, maxTokens, false)
+}
+
+// GenerateStructured asks for a commit message as the JSON object described
+// by StructuredInstructions. OpenAI-compatible providers are put in JSON
+// mode and Anthropic must answer through a tool with that schema; other
+// providers only have the instructions in the prompt to go on.
+func (c *Client) GenerateStructured(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	return c.complete(ctx, prompt, maxTokens, true)
+}
+
+// complete sends a prompt to the provider, or races it with
+// llm.race_providers, giving up after llm.timeout
+func (c *Client) complete(ctx context.Context, prompt string, maxTokens int, structured bool) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	logger.Info("Requesting a commit message from %s (model %s)", c.provider, c.model)
+	logger.Debug("LLM request: %d prompt characters, max %d tokens, temperature %.2f", len(prompt), maxTokens, c.temperature)
+
+	start := time.Now()
+	var message string
+	var err error
+	if len(c.rivals) > 0 {
+		message, err = c.race(ctx, prompt, maxTokens, structured)
+	} else {
+		c.answeredBy = c
+		message, err = c.generate(ctx, prompt, maxTokens, structured)
+	}
+	switch {
+	case err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("%s did not answer within %s (llm.timeout)", c.provider, c.timeout)
+	case errors.Is(ctx.Err(), context.Canceled):
+		err = fmt.Errorf("request to %s was cancelled", c.provider)
+	}
+	if err != nil {
+		logger.Debug("LLM request failed after %s: %v", time.Since(start).Round(time.Millisecond), err)
+		return "", err
+	}
+
+	logger.Debug("LLM response: %d characters in %s", len(message), time.Since(start).Round(time.Millisecond))
+	return message, nil
+}
+
+// generate sends the prompt to the configured provider. Plugin providers
+// finish their request even when ctx is cancelled.
+func (c *Client) generate(ctx context.Context, prompt string, maxTokens int, structured bool) (string, error) {
+	c.lastUsage = nil
 	switch c.provider {
-	case "openai":
-		return c.generateWithOpenAI(prompt, maxTokens)
+	case "openai", "groq", "deepseek", "xai", "local-server":
+		return c.generateWithOpenAI(ctx, prompt, maxTokens, structured)
 	case "anthropic":
-		return c.generateWithAnthropic(prompt, maxTokens)
+		return c.generateWithAnthropic(ctx, prompt, maxTokens, structured)
 	case "local":
 		localModel, err := NewLocalModel(c.configProvider.GetString(ConfigDirKey))
 		if err != nil {
 			return "", err
 		}
-		return localModel.Generate(prompt, maxTokens)
+		// Local models take a single prompt
+		return localModel.Generate(ctx, c.systemPrompt+"\n\n"+prompt, maxTokens)
 	default:
+		if p, ok := LookupProvider(c.provider); ok {
+			return p.Generate(&ProviderRequest{
+				System:      c.systemPrompt,
+				Prompt:      prompt,
+				MaxTokens:   maxTokens,
+				Model:       c.model,
+				Temperature: c.temperature,
+				Endpoint:    c.endpoint,
+				APIKey:      c.apiKey,
+				TraceID:     logging.TraceID(),
+			})
+		}
 		return "", fmt.Errorf("unsupported provider: %s", c.provider)
 	}
 }
 
+// needsAPIKey reports whether a provider requires an API key
+func needsAPIKey(provider string) bool {
+	if provider == "local" || provider == "local-server" {
+		return false
+	}
+	if p, ok := LookupProvider(provider); ok {
+		return p.Info().RequiresAPIKey
+	}
+	return true
+}
+
 // Close cleans up resources
 func (c *Client) Close() {
 	c.rateLimiter.Stop()
+	for _, rival := range c.rivals {
+		rival.Close()
+	}
 }
 
 // NewNoOpClient creates a client that doesn't make any actual API calls
@@ -190,8 +372,8 @@ func (c *Client) IsOperational() bool {
 		return false
 	}
 
-	// For local provider, we don't need an API key
-	if c.provider == "local" {
+	// For local and keyless plugin providers, we don't need an API key
+	if !needsAPIKey(c.provider) {
 		return true
 	}
 
@@ -0,0 +1,98 @@
+// internal/llm/keys.go
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/vault"
+)
+
+// Places an API key can come from, in the order NewClient looks
+const (
+	KeySourceVault  = "vault"       // The credential store
+	KeySourceConfig = "api_keys"    // api_keys.<provider> in the config file
+	KeySourceLLM    = "llm.api_key" // The --api-key flag or llm.api_key in the config file
+	KeySourceEnv    = "env"         // The provider's environment variable
+)
+
+// pingPrompt asks for the shortest possible answer
+const pingPrompt = "Reply with OK."
+
+// KeySource is one place an API key for a provider can come from
+type KeySource struct {
+	Name   string // One of the KeySource constants
+	Detail string // Where exactly, e.g. the config key or variable name
+	Key    string // Empty when the source has no key
+	Err    error  // Why the vault could not be read, when it is locked
+}
+
+// APIKeySources returns every place NewClient looks for a provider's API
+// key, in order. The first with a key is the one used.
+func APIKeySources(provider string, credManager *vault.CredentialManager, configProvider ConfigProvider) []KeySource {
+	var stored string
+	var storeErr error
+	if credManager != nil {
+		if stored, storeErr = credManager.Retrieve(provider); !errors.Is(storeErr, vault.ErrLocked) {
+			storeErr = nil
+		}
+	}
+
+	configKey := fmt.Sprintf("api_keys.%s", provider)
+	configured := configProvider.GetString(configKey)
+	// "set" marks a key that was moved to the vault
+	if configured == "set" {
+		configured = ""
+	}
+
+	envVar := getProviderAPIEnvVar(provider)
+	return []KeySource{
+		{Name: KeySourceVault, Detail: credentialLocation(provider, credManager), Key: stored, Err: storeErr},
+		{Name: KeySourceConfig, Detail: configKey, Key: configured},
+		{Name: KeySourceLLM, Detail: LLMAPIKeyKey, Key: configProvider.GetString(LLMAPIKeyKey)},
+		{Name: KeySourceEnv, Detail: envVar, Key: getEnv(envVar, "")},
+	}
+}
+
+// credentialLocation describes where the credential store keeps a key
+func credentialLocation(provider string, credManager *vault.CredentialManager) string {
+	if credManager == nil {
+		return "none"
+	}
+	return credManager.Location(provider)
+}
+
+// Ping sends a minimal request to a provider with the given key, to check
+// that the provider accepts it
+func Ping(ctx context.Context, provider, apiKey string, configProvider ConfigProvider) error {
+	endpoint := providerEndpoints[provider].url
+	model := defaultModels[provider]
+	if provider == ActiveProvider(configProvider) {
+		if configured := configProvider.GetString(LLMModelKey); configured != "" {
+			model = configured
+		}
+		if endpoint == "" {
+			endpoint = configProvider.GetString(LLMEndpointKey)
+		}
+	}
+	if provider == "local-server" {
+		endpoint = localServerEndpoint(configProvider.GetString(LLMEndpointKey))
+	}
+
+	client := &Client{
+		provider:       provider,
+		apiKey:         apiKey,
+		endpoint:       endpoint,
+		model:          model,
+		systemPrompt:   SystemPrompt(configProvider),
+		timeout:        requestTimeout(configProvider),
+		rateLimiter:    time.NewTicker(time.Second),
+		configProvider: configProvider,
+	}
+	defer client.Close()
+
+	_, err := client.complete(ctx, pingPrompt, 5, false)
+	return err
+}
@@ -2,15 +2,17 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"time"
+
+	"github.com/jasonKoogler/comma/internal/logging"
 )
 
 // generateWithLocal calls a local LLM API to generate a commit message
-func (c *Client) generateWithLocal(prompt string, maxTokens int) (string, error) {
+func (c *Client) generateWithLocal(ctx context.Context, prompt string, maxTokens int) (string, error) {
 	// If no endpoint is specified, use default ollama endpoint
 	endpoint := c.endpoint
 	if endpoint == "" {
@@ -27,6 +29,7 @@ func (c *Client) generateWithLocal(prompt string, maxTokens int) (string, error)
 	requestBody := map[string]interface{}{
 		"model":       model,
 		"prompt":      prompt,
+		"system":      c.systemPrompt,
 		"temperature": c.temperature,
 		"max_tokens":  maxTokens,
 		"stream":      false,
@@ -38,16 +41,20 @@ func (c *Client) generateWithLocal(prompt string, maxTokens int) (string, error)
 	}
 
 	// Create request
-	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
+	if id := logging.TraceID(); id != "" {
+		req.Header.Set(logging.TraceHeader, id)
+	}
 
 	// Execute request
-	httpClient := &http.Client{Timeout: 60 * time.Second}
+	// The context carries llm.timeout
+	httpClient := &http.Client{}
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("request failed: %w", err)
@@ -3,6 +3,7 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -132,10 +133,10 @@ func findModelPath(configDir string) (string, error) {
 }
 
 // GenerateWithLocalModel uses local LLM for generation
-func (lm *LocalModel) Generate(prompt string, maxTokens int) (string, error) {
+func (lm *LocalModel) Generate(ctx context.Context, prompt string, maxTokens int) (string, error) {
 	// Check if Ollama
 	if strings.Contains(lm.binary, "ollama") {
-		return lm.generateWithOllama(prompt, maxTokens)
+		return lm.generateWithOllama(ctx, prompt, maxTokens)
 	}
 
 	// Use llama.cpp binary
@@ -150,7 +151,7 @@ func (lm *LocalModel) Generate(prompt string, maxTokens int) (string, error) {
 		"-p", prompt,
 	}
 
-	cmd := exec.Command(lm.binary, args...)
+	cmd := exec.CommandContext(ctx, lm.binary, args...)
 	var out bytes.Buffer
 	cmd.Stdout = &out
 
@@ -162,7 +163,7 @@ func (lm *LocalModel) Generate(prompt string, maxTokens int) (string, error) {
 }
 
 // generateWithOllama handles generation using Ollama
-func (lm *LocalModel) generateWithOllama(prompt string, maxTokens int) (string, error) {
+func (lm *LocalModel) generateWithOllama(ctx context.Context, prompt string, maxTokens int) (string, error) {
 	// Determine model name - use a smaller one suitable for commit messages
 	modelName := "llama2"
 
@@ -180,7 +181,7 @@ func (lm *LocalModel) generateWithOllama(prompt string, maxTokens int) (string,
 	}
 
 	// Run ollama command
-	cmd := exec.Command(lm.binary, "run", "-j", modelName)
+	cmd := exec.CommandContext(ctx, lm.binary, "run", "-j", modelName)
 	cmd.Stdin = bytes.NewBuffer(jsonBody)
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -0,0 +1,12 @@
+// internal/llm/log.go
+package llm
+
+import "github.com/jasonKoogler/comma/internal/logging"
+
+// logger receives LLM request details, shown with -v and -vv
+var logger logging.Logger = logging.NewNullLogger()
+
+// SetLogger sets the logger LLM requests are reported to
+func SetLogger(l logging.Logger) {
+	logger = l
+}
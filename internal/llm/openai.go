@@ -2,22 +2,31 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/jasonKoogler/comma/internal/logging"
 )
 
-// generateWithOpenAI calls the OpenAI API to generate a commit message
-func (c *Client) generateWithOpenAI(prompt string, maxTokens int) (string, error) {
+// generateWithOpenAI calls the OpenAI API, or an API compatible with it, to
+// generate a commit message. Structured requests use JSON mode, except with
+// local servers, not all of which support it.
+func (c *Client) generateWithOpenAI(ctx context.Context, prompt string, maxTokens int, structured bool) (string, error) {
 	// Respect rate limit
-	<-c.rateLimiter.C
+	select {
+	case <-c.rateLimiter.C:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
 
 	// Use default model if not specified
 	model := c.model
 	if model == "" {
-		model = "gpt-4"
+		model = defaultModels[c.provider]
 	}
 
 	// Prepare request
@@ -26,7 +35,7 @@ func (c *Client) generateWithOpenAI(prompt string, maxTokens int) (string, error
 		"messages": []map[string]string{
 			{
 				"role":    "system",
-				"content": "You are a helpful assistant that generates concise and descriptive git commit messages.",
+				"content": c.systemPrompt,
 			},
 			{
 				"role":    "user",
@@ -40,23 +49,34 @@ func (c *Client) generateWithOpenAI(prompt string, maxTokens int) (string, error
 		"stop":        nil,
 	}
 
+	if structured && c.provider != "local-server" {
+		requestBody["response_format"] = map[string]string{"type": "json_object"}
+	}
+
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
 	// Create request
-	req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if id := logging.TraceID(); id != "" {
+		req.Header.Set(logging.TraceHeader, id)
+	}
+	// Local servers usually take no key
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
 
 	// Execute request with retry
-	httpClient := &http.Client{Timeout: 60 * time.Second}
+	// The context carries llm.timeout
+	httpClient := &http.Client{}
 	var resp *http.Response
 	maxRetries := 3
 
@@ -72,7 +92,11 @@ func (c *Client) generateWithOpenAI(prompt string, maxTokens int) (string, error
 
 		if i < maxRetries-1 {
 			// Exponential backoff
-			time.Sleep(time.Duration((1<<i)*500) * time.Millisecond)
+			select {
+			case <-time.After(time.Duration((1<<i)*500) * time.Millisecond):
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
 		}
 	}
 
@@ -95,6 +119,10 @@ func (c *Client) generateWithOpenAI(prompt string, maxTokens int) (string, error
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage *struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
 		Error struct {
 			Message string `json:"message"`
 		} `json:"error"`
@@ -114,5 +142,8 @@ func (c *Client) generateWithOpenAI(prompt string, maxTokens int) (string, error
 		return "", fmt.Errorf("no choices returned from API")
 	}
 
+	if response.Usage != nil {
+		c.lastUsage = &Usage{PromptTokens: response.Usage.PromptTokens, CompletionTokens: response.Usage.CompletionTokens}
+	}
 	return response.Choices[0].Message.Content, nil
 }
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/jasonKoogler/comma/internal/conventional"
 	"github.com/jasonKoogler/comma/internal/git"
 )
 
@@ -20,6 +21,48 @@ type PromptData struct {
 	Diff        string
 	CommitType  string
 	CommitScope string
+	Types       string // The project's commit types, comma-separated
+}
+
+// languageNames maps common language codes to the name given to the model
+var languageNames = map[string]string{
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"sv": "Swedish",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"zh": "Chinese",
+}
+
+// LanguageName returns the name of a language code such as "es" or "pt-BR".
+// Unknown values, e.g. a language already given by name, are returned as is.
+func LanguageName(language string) string {
+	language = strings.TrimSpace(language)
+	base := strings.ToLower(strings.SplitN(strings.ReplaceAll(language, "_", "-"), "-", 2)[0])
+	if name, ok := languageNames[base]; ok {
+		return name
+	}
+	return language
+}
+
+// LanguageInstruction tells the model which language to write the message
+// in. It is empty for English, the language of the default template.
+func LanguageInstruction(language string) string {
+	name := LanguageName(language)
+	if name == "" || name == "English" {
+		return ""
+	}
+	return fmt.Sprintf("Write the commit subject and body in %s. Keep the conventional commit "+
+		"type keywords (feat, fix, docs, ...) and the scope in English.", name)
 }
 
 // PreparePrompt prepares the prompt for the LLM
@@ -37,6 +80,7 @@ func PreparePrompt(templateStr string, changes string, withDiff bool, context *g
 		Context:     context,
 		CommitType:  commitType,
 		CommitScope: commitScope,
+		Types:       strings.Join(conventional.Types, ", "),
 	}
 
 	// Execute template
@@ -97,8 +141,9 @@ func EditPrompt(prompt string) (string, error) {
 	// Get editor from git config or environment
 	editor := getEditor()
 
-	// Open editor
-	cmd := exec.Command(editor, tempFile.Name())
+	// Open editor; it may be configured with arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], tempFile.Name())...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
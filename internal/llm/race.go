@@ -0,0 +1,125 @@
+// internal/llm/race.go
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jasonKoogler/comma/internal/ui"
+)
+
+// raceResult is one provider's answer in a race
+type raceResult struct {
+	client  *Client
+	message string
+	err     error
+}
+
+// newRivals builds clients for the providers listed in llm.race_providers
+// besides the active one. Providers that are refused by policy or have no
+// API key are left out of the race with a warning.
+func newRivals(primary *Client, policy ProviderPolicy) []*Client {
+	var rivals []*Client
+	seen := map[string]bool{primary.provider: true}
+
+	for _, name := range strings.Split(primary.configProvider.GetString(LLMRaceProvidersKey), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if policy != nil {
+			if err := policy.CheckProvider(name); err != nil {
+				logger.Warn("Not racing %s: %v", name, err)
+				continue
+			}
+		}
+
+		var apiKey string
+		if needsAPIKey(name) {
+			var err error
+			if apiKey, err = getSecureAPIKey(name, primary.credManager, primary.configProvider); err != nil {
+				logger.Warn("Not racing %s: %v", name, err)
+				continue
+			}
+		}
+
+		endpoint := providerEndpoints[name].url
+		if name == "local-server" {
+			endpoint = localServerEndpoint(primary.configProvider.GetString(LLMEndpointKey))
+			apiKey = localServerAPIKey(primary.credManager)
+		}
+
+		rivals = append(rivals, &Client{
+			provider:       name,
+			apiKey:         apiKey,
+			endpoint:       endpoint,
+			model:          defaultModels[name],
+			temperature:    primary.temperature,
+			systemPrompt:   primary.systemPrompt,
+			timeout:        primary.timeout,
+			rateLimiter:    time.NewTicker(time.Second),
+			credManager:    primary.credManager,
+			configProvider: primary.configProvider,
+		})
+	}
+
+	return rivals
+}
+
+// race sends the prompt to this client and its rivals at once and returns the
+// first successful response, cancelling the requests still in flight
+func (c *Client) race(ctx context.Context, prompt string, maxTokens int, structured bool) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clients := append([]*Client{c}, c.rivals...)
+	results := make(chan raceResult, len(clients))
+	for _, client := range clients {
+		go func(client *Client) {
+			message, err := client.generate(ctx, prompt, maxTokens, structured)
+			results <- raceResult{client: client, message: message, err: err}
+		}(client)
+	}
+
+	var failures []string
+	primaryFailed := false
+	for range clients {
+		result := <-results
+		if result.err == nil {
+			logger.Info("%s answered first", result.client.provider)
+			c.answeredBy = result.client
+			if result.client != c {
+				c.noticeRaceWinner(result.client, primaryFailed)
+			}
+			return result.message, nil
+		}
+		primaryFailed = primaryFailed || result.client == c
+		logger.Debug("Raced provider %s failed: %v", result.client.provider, result.err)
+		failures = append(failures, fmt.Sprintf("%s: %v", result.client.provider, result.err))
+	}
+
+	return "", fmt.Errorf("all raced providers failed: %s", strings.Join(failures, "; "))
+}
+
+// noticeRaceWinner tells the user a rival's answer is used rather than one
+// from the configured provider
+func (c *Client) noticeRaceWinner(winner *Client, primaryFailed bool) {
+	if primaryFailed {
+		ui.Notify(ui.NoticeWarning, "%s failed, using the answer from %s", c.provider, winner.provider)
+		return
+	}
+	ui.Notify(ui.NoticeInfo, "Using the answer from %s, which was faster than %s (%s)", winner.provider, c.provider, LLMRaceProvidersKey)
+}
+
+// Responder returns the provider and model that produced the last message,
+// which with llm.race_providers may not be the configured ones
+func (c *Client) Responder() (provider, model string) {
+	if c.answeredBy == nil {
+		return c.provider, c.model
+	}
+	return c.answeredBy.provider, c.answeredBy.model
+}
@@ -0,0 +1,125 @@
+// internal/llm/registry.go
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ProviderInfo describes a provider added by a plugin
+type ProviderInfo struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	Models         []string `json:"models"`
+	RequiresAPIKey bool     `json:"requires_api_key"`
+}
+
+// ProviderRequest is a single generation request passed to a plugin provider
+type ProviderRequest struct {
+	System      string  `json:"system,omitempty"`
+	Prompt      string  `json:"prompt"`
+	MaxTokens   int     `json:"max_tokens"`
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature"`
+	Endpoint    string  `json:"endpoint,omitempty"`
+	APIKey      string  `json:"api_key,omitempty"`
+	TraceID     string  `json:"trace_id,omitempty"` // Pass on as the X-Comma-Trace-Id header
+}
+
+// Provider is a custom LLM backend, such as an internal company gateway,
+// registered by a plugin
+type Provider interface {
+	// Info describes the provider and the models it offers
+	Info() ProviderInfo
+
+	// Generate returns the completion for a request
+	Generate(req *ProviderRequest) (string, error)
+}
+
+// builtinProviders are the providers Comma implements itself
+var builtinProviders = []string{"openai", "anthropic", "groq", "deepseek", "xai", "local", "local-server"}
+
+var (
+	registryMu     sync.RWMutex
+	registry       = map[string]Provider{}
+	providerLoader func()
+	loaderOnce     sync.Once
+)
+
+// RegisterProvider makes a plugin provider available under its name.
+// Built-in provider names cannot be replaced.
+func RegisterProvider(p Provider) error {
+	name := p.Info().Name
+	if name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+	if IsBuiltinProvider(name) || name == "none" {
+		return fmt.Errorf("provider %s is built in and cannot be replaced", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("provider already registered: %s", name)
+	}
+	registry[name] = p
+	return nil
+}
+
+// SetProviderLoader sets a function that registers plugin providers. It is
+// called once, the first time a provider that is not built in is looked up,
+// so plugins are only started when they are needed.
+func SetProviderLoader(loader func()) {
+	providerLoader = loader
+}
+
+// IsBuiltinProvider reports whether Comma implements the provider itself
+func IsBuiltinProvider(name string) bool {
+	for _, builtin := range builtinProviders {
+		if name == builtin {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupProvider returns the plugin provider registered under a name
+func LookupProvider(name string) (Provider, bool) {
+	if IsBuiltinProvider(name) || name == "none" || name == "" {
+		return nil, false
+	}
+	loadProviders()
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	p, ok := registry[name]
+	return p, ok
+}
+
+// RegisteredProviders describes all plugin providers, sorted by name
+func RegisteredProviders() []ProviderInfo {
+	loadProviders()
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	infos := make([]ProviderInfo, 0, len(registry))
+	for _, p := range registry {
+		infos = append(infos, p.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// loadProviders runs the provider loader, if any, the first time it is needed
+func loadProviders() {
+	loaderOnce.Do(func() {
+		if providerLoader != nil {
+			providerLoader()
+		}
+	})
+}
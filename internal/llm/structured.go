@@ -0,0 +1,23 @@
+// internal/llm/structured.go
+package llm
+
+// StructuredInstructions asks for the JSON object GenerateStructured expects
+const StructuredInstructions = `Respond with only a JSON object with these fields: "type" (the conventional commit type), ` +
+	`"scope" (a short scope, or ""), "subject" (an imperative summary without type or scope), ` +
+	`"body" (what changed and why, or "") and "breaking" (true only for a breaking change).`
+
+// structuredToolName is the tool Anthropic models must call with the message
+const structuredToolName = "commit_message"
+
+// structuredSchema is the JSON schema of a structured commit message
+var structuredSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"type":     map[string]string{"type": "string", "description": "Conventional commit type, e.g. feat or fix"},
+		"scope":    map[string]string{"type": "string", "description": "Optional scope, e.g. api"},
+		"subject":  map[string]string{"type": "string", "description": "Imperative summary without type or scope"},
+		"body":     map[string]string{"type": "string", "description": "What changed and why; may be empty"},
+		"breaking": map[string]string{"type": "boolean", "description": "Whether the change breaks compatibility"},
+	},
+	"required": []string{"type", "subject"},
+}
@@ -0,0 +1,90 @@
+// internal/llm/usage.go
+package llm
+
+import "strings"
+
+// Usage counts the tokens spent on a generation
+type Usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Estimated        bool    `json:"estimated"`          // Counted locally rather than reported by the provider
+	CostUSD          float64 `json:"cost_usd,omitempty"` // Estimated from list prices; 0 when unknown
+}
+
+// TotalTokens returns the prompt and completion tokens combined
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// EstimateTokens approximates the token count of text at about four
+// characters per token, which is close enough for budgeting
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// EstimateUsage approximates the usage of a generation from its text
+func EstimateUsage(prompt, completion string) *Usage {
+	return &Usage{
+		PromptTokens:     EstimateTokens(prompt),
+		CompletionTokens: EstimateTokens(completion),
+		Estimated:        true,
+	}
+}
+
+// Add counts the tokens of another generation, e.g. a retry, as well
+func (u *Usage) Add(other *Usage) {
+	if other == nil {
+		return
+	}
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.Estimated = u.Estimated || other.Estimated
+	u.CostUSD += other.CostUSD
+}
+
+// modelPrices are list prices in USD per million prompt and completion
+// tokens, matched by model name prefix. They drift, so costs computed from
+// them are estimates.
+var modelPrices = map[string][2]float64{
+	"gpt-4o-mini":             {0.15, 0.60},
+	"gpt-4o":                  {2.50, 10},
+	"gpt-4-turbo":             {10, 30},
+	"gpt-4":                   {30, 60},
+	"gpt-3.5-turbo":           {0.50, 1.50},
+	"claude-3-opus":           {15, 75},
+	"claude-3-7-sonnet":       {3, 15},
+	"claude-3-5-sonnet":       {3, 15},
+	"claude-3-sonnet":         {3, 15},
+	"claude-3-haiku":          {0.25, 1.25},
+	"llama-3.3-70b-versatile": {0.59, 0.79},
+	"llama-3.1-8b-instant":    {0.05, 0.08},
+	"deepseek-chat":           {0.27, 1.10},
+	"deepseek-reasoner":       {0.55, 2.19},
+	"grok-3-mini":             {0.30, 0.50},
+	"grok-3":                  {3, 15},
+}
+
+// EstimateCost returns the approximate price of a generation in USD, or
+// false for models without a known price such as local ones
+func EstimateCost(model string, u Usage) (float64, bool) {
+	var prices [2]float64
+	match := ""
+	for prefix, p := range modelPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(match) {
+			match, prices = prefix, p
+		}
+	}
+	if match == "" {
+		return 0, false
+	}
+	return (float64(u.PromptTokens)*prices[0] + float64(u.CompletionTokens)*prices[1]) / 1e6, true
+}
+
+// Usage returns the token usage the provider reported for the last message,
+// or nil when it reported none
+func (c *Client) Usage() *Usage {
+	if c.answeredBy == nil {
+		return c.lastUsage
+	}
+	return c.answeredBy.lastUsage
+}
@@ -1,10 +1,13 @@
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -27,22 +30,59 @@ const (
 	DebugLevel LogLevel = "DEBUG"
 )
 
+// levelRank orders levels from most to least verbose
+func levelRank(level LogLevel) int {
+	switch level {
+	case DebugLevel:
+		return 0
+	case InfoLevel:
+		return 1
+	case WarnLevel:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// ParseLogLevel parses a level name such as "debug" or "WARN"
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch level := LogLevel(strings.ToUpper(strings.TrimSpace(name))); level {
+	case DebugLevel, InfoLevel, WarnLevel, ErrorLevel:
+		return level, nil
+	case "WARNING":
+		return WarnLevel, nil
+	}
+	return "", fmt.Errorf("invalid log level %q (use debug, info, warn or error)", name)
+}
+
+// LevelForVerbosity maps the number of -v flags to a console log level:
+// warnings by default, info with -v and debug with -vv
+func LevelForVerbosity(verbosity int) LogLevel {
+	switch {
+	case verbosity >= 2:
+		return DebugLevel
+	case verbosity == 1:
+		return InfoLevel
+	default:
+		return WarnLevel
+	}
+}
+
 // FileLogger implements Logger with file-based logging
 type FileLogger struct {
 	logger *log.Logger
 	file   *os.File
+	format string
 	mu     sync.Mutex
 }
 
 // NewFileLogger creates a new file logger
 func NewFileLogger(appName string) (*FileLogger, error) {
 	// Create log directory if it doesn't exist
-	homeDir, err := os.UserHomeDir()
+	logDir, err := LogDir(appName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
-
-	logDir := filepath.Join(homeDir, "."+appName, "logs")
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
@@ -58,16 +98,55 @@ func NewFileLogger(appName string) (*FileLogger, error) {
 	return &FileLogger{
 		logger: logger,
 		file:   file,
+		format: FormatText,
 	}, nil
 }
 
+// SetFormat chooses between plain text lines and JSON entries
+func (l *FileLogger) SetFormat(format string) error {
+	switch format {
+	case "", FormatText:
+		format = FormatText
+	case FormatJSON:
+	default:
+		return fmt.Errorf("invalid log format %q (use text or json)", format)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.format = format
+	return nil
+}
+
 // log logs a message with the given level
 func (l *FileLogger) log(level LogLevel, format string, v ...interface{}) {
+	l.logEntry(level, "", nil, format, v...)
+}
+
+// logEntry writes a message with the component and fields it was logged with
+func (l *FileLogger) logEntry(level LogLevel, component string, fields Fields, format string, v ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	msg := fmt.Sprintf(format, v...)
-	l.logger.Printf("[%s] %s", level, msg)
+	if l.format == FormatJSON {
+		data, err := json.Marshal(&Entry{
+			Time:      time.Now(),
+			Level:     strings.ToLower(string(level)),
+			Component: component,
+			Message:   msg,
+			Fields:    fields,
+		})
+		if err == nil {
+			l.file.Write(append(data, '\n'))
+		}
+		return
+	}
+
+	if component != "" {
+		msg = "[" + component + "] " + msg
+	}
+	l.logger.Printf("[%s] %s%s", level, msg, fields)
 }
 
 // Info logs an info message
@@ -136,6 +215,114 @@ func (l *ConsoleLogger) Debug(format string, v ...interface{}) {
 	l.debugLogger.Printf(format, v...)
 }
 
+// NewConsoleLoggerTo creates a console logger writing every level to w
+// without timestamps, for chatter shown alongside command output
+func NewConsoleLoggerTo(w io.Writer) *ConsoleLogger {
+	return &ConsoleLogger{
+		infoLogger:  log.New(w, "", 0),
+		warnLogger:  log.New(w, "⚠️  ", 0),
+		errorLogger: log.New(w, "✗ ", 0),
+		debugLogger: log.New(w, "[debug] ", 0),
+	}
+}
+
+// LeveledLogger drops messages below a minimum level
+type LeveledLogger struct {
+	logger Logger
+	level  LogLevel
+}
+
+// NewLeveledLogger wraps a logger so only messages at level or above are written
+func NewLeveledLogger(logger Logger, level LogLevel) *LeveledLogger {
+	return &LeveledLogger{logger: logger, level: level}
+}
+
+// enabled reports whether messages at level are written
+func (l *LeveledLogger) enabled(level LogLevel) bool {
+	return levelRank(level) >= levelRank(l.level)
+}
+
+// Info logs an info message
+func (l *LeveledLogger) Info(format string, v ...interface{}) {
+	if l.enabled(InfoLevel) {
+		l.logger.Info(format, v...)
+	}
+}
+
+// Warn logs a warning message
+func (l *LeveledLogger) Warn(format string, v ...interface{}) {
+	if l.enabled(WarnLevel) {
+		l.logger.Warn(format, v...)
+	}
+}
+
+// Error logs an error message
+func (l *LeveledLogger) Error(format string, v ...interface{}) {
+	if l.enabled(ErrorLevel) {
+		l.logger.Error(format, v...)
+	}
+}
+
+// Debug logs a debug message
+func (l *LeveledLogger) Debug(format string, v ...interface{}) {
+	if l.enabled(DebugLevel) {
+		l.logger.Debug(format, v...)
+	}
+}
+
+// logEntry passes on a tagged message at level or above
+func (l *LeveledLogger) logEntry(level LogLevel, component string, fields Fields, format string, v ...interface{}) {
+	if l.enabled(level) {
+		logTo(l.logger, level, component, fields, format, v...)
+	}
+}
+
+// MultiLogger sends every message to several loggers, e.g. the log file
+// and the console
+type MultiLogger struct {
+	loggers []Logger
+}
+
+// NewMultiLogger creates a logger writing to all of the given loggers
+func NewMultiLogger(loggers ...Logger) *MultiLogger {
+	return &MultiLogger{loggers: loggers}
+}
+
+// Info logs an info message
+func (l *MultiLogger) Info(format string, v ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.Info(format, v...)
+	}
+}
+
+// Warn logs a warning message
+func (l *MultiLogger) Warn(format string, v ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.Warn(format, v...)
+	}
+}
+
+// Error logs an error message
+func (l *MultiLogger) Error(format string, v ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.Error(format, v...)
+	}
+}
+
+// Debug logs a debug message
+func (l *MultiLogger) Debug(format string, v ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.Debug(format, v...)
+	}
+}
+
+// logEntry passes a tagged message on to every logger
+func (l *MultiLogger) logEntry(level LogLevel, component string, fields Fields, format string, v ...interface{}) {
+	for _, logger := range l.loggers {
+		logTo(logger, level, component, fields, format, v...)
+	}
+}
+
 // NullLogger implements Logger without any output
 type NullLogger struct{}
 
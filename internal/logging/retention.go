@@ -0,0 +1,87 @@
+// internal/logging/retention.go
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogFile is a dated log file in the log directory
+type LogFile struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// LogDir returns the directory an application's log files are written to
+func LogDir(appName string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, "."+appName, "logs"), nil
+}
+
+// ListLogFiles returns an application's log files, oldest first
+func ListLogFiles(dir, appName string) ([]LogFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read log directory: %w", err)
+	}
+
+	var files []LogFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, appName+"-") || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, LogFile{Path: filepath.Join(dir, name), Size: info.Size(), ModTime: info.ModTime()})
+	}
+
+	// Dated names sort chronologically
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// PruneLogFiles removes log files last written more than maxAge ago, then the
+// oldest files until the rest fit in maxBytes. A zero limit is not applied.
+// The newest file, which may still be written to, is always kept.
+func PruneLogFiles(dir, appName string, maxAge time.Duration, maxBytes int64) ([]LogFile, error) {
+	files, err := ListLogFiles(dir, appName)
+	if err != nil || len(files) < 2 {
+		return nil, err
+	}
+
+	var total int64
+	for _, f := range files {
+		total += f.Size
+	}
+
+	var removed []LogFile
+	cutoff := time.Now().Add(-maxAge)
+	for _, f := range files[:len(files)-1] {
+		expired := maxAge > 0 && f.ModTime.Before(cutoff)
+		oversize := maxBytes > 0 && total > maxBytes
+		if !expired && !oversize {
+			continue
+		}
+		if err := os.Remove(f.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", f.Path, err)
+		}
+		total -= f.Size
+		removed = append(removed, f)
+	}
+
+	return removed, nil
+}
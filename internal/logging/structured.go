@@ -0,0 +1,121 @@
+// internal/logging/structured.go
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Log file formats for logging.format
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Entry is one line of a JSON log file
+type Entry struct {
+	Time      time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Component string    `json:"component,omitempty"`
+	Message   string    `json:"message"`
+	Fields    Fields    `json:"fields,omitempty"`
+}
+
+// Fields are structured data attached to log messages
+type Fields map[string]interface{}
+
+// String formats fields as " key=value" pairs in key order for text logs
+func (f Fields) String() string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&sb, " %s=%v", key, f[key])
+	}
+	return sb.String()
+}
+
+// entryLogger is implemented by loggers that keep the component and fields
+// of a message rather than just its text
+type entryLogger interface {
+	logEntry(level LogLevel, component string, fields Fields, format string, v ...interface{})
+}
+
+// logTo writes a message to a logger, with its component and fields when the
+// logger records them
+func logTo(l Logger, level LogLevel, component string, fields Fields, format string, v ...interface{}) {
+	if el, ok := l.(entryLogger); ok {
+		el.logEntry(level, component, fields, format, v...)
+		return
+	}
+
+	switch level {
+	case DebugLevel:
+		l.Debug(format, v...)
+	case InfoLevel:
+		l.Info(format, v...)
+	case WarnLevel:
+		l.Warn(format, v...)
+	default:
+		l.Error(format, v...)
+	}
+}
+
+// componentLogger tags messages with the part of Comma that wrote them
+type componentLogger struct {
+	logger    Logger
+	component string
+	fields    Fields
+}
+
+// WithComponent returns a logger that tags messages with a component such as
+// "git" or "llm"
+func WithComponent(l Logger, component string) Logger {
+	if cl, ok := l.(*componentLogger); ok {
+		return &componentLogger{logger: cl.logger, component: component, fields: cl.fields}
+	}
+	return &componentLogger{logger: l, component: component}
+}
+
+// WithFields returns a logger that attaches fields to every message
+func WithFields(l Logger, fields Fields) Logger {
+	cl, ok := l.(*componentLogger)
+	if !ok {
+		cl = &componentLogger{logger: l}
+	}
+
+	merged := make(Fields, len(cl.fields)+len(fields))
+	for key, value := range cl.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return &componentLogger{logger: cl.logger, component: cl.component, fields: merged}
+}
+
+// Info logs an info message
+func (l *componentLogger) Info(format string, v ...interface{}) {
+	logTo(l.logger, InfoLevel, l.component, l.fields, format, v...)
+}
+
+// Warn logs a warning message
+func (l *componentLogger) Warn(format string, v ...interface{}) {
+	logTo(l.logger, WarnLevel, l.component, l.fields, format, v...)
+}
+
+// Error logs an error message
+func (l *componentLogger) Error(format string, v ...interface{}) {
+	logTo(l.logger, ErrorLevel, l.component, l.fields, format, v...)
+}
+
+// Debug logs a debug message
+func (l *componentLogger) Debug(format string, v ...interface{}) {
+	logTo(l.logger, DebugLevel, l.component, l.fields, format, v...)
+}
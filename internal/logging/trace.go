@@ -0,0 +1,40 @@
+// internal/logging/trace.go
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+)
+
+// TraceHeader carries the trace ID on outgoing HTTP requests and API responses
+const TraceHeader = "X-Comma-Trace-Id"
+
+var (
+	traceMu sync.RWMutex
+	traceID string
+)
+
+// NewTraceID returns a random ID for one invocation of Comma
+func NewTraceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// SetTraceID sets the trace ID of the current invocation, which log lines,
+// audit entries and HTTP requests are tagged with
+func SetTraceID(id string) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	traceID = id
+}
+
+// TraceID returns the trace ID of the current invocation, if any
+func TraceID() string {
+	traceMu.RLock()
+	defer traceMu.RUnlock()
+	return traceID
+}
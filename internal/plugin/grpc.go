@@ -0,0 +1,301 @@
+// internal/plugin/grpc.go
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/llm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+)
+
+// RemotePrefix is the file name prefix of plugin executables served over gRPC
+const RemotePrefix = "comma-plugin-"
+
+// Handshake is shared by Comma and its gRPC plugins so that neither starts
+// talking to a binary that is not a Comma plugin
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "COMMA_PLUGIN",
+	MagicCookieValue: "comma-plugin-v1",
+}
+
+// RemoteInfo describes a gRPC plugin, the hooks it wants to run on and
+// the LLM providers it offers
+type RemoteInfo struct {
+	Name      string             `json:"name"`
+	Version   string             `json:"version"`
+	Hooks     []string           `json:"hooks"`
+	Providers []llm.ProviderInfo `json:"providers,omitempty"`
+}
+
+// RemotePlugin is implemented by plugins that run as separate executables.
+// Unlike Go plugins they can be built with any toolchain for any platform.
+type RemotePlugin interface {
+	// Info reports the plugin's name, version and hook points
+	Info() (*RemoteInfo, error)
+
+	// RunHook handles a hook point and returns the possibly modified context.
+	// Returning an error vetoes the operation.
+	RunHook(hook string, hctx *HookContext) (*HookContext, error)
+}
+
+// RemoteProvider is implemented by gRPC plugins that list providers in
+// their RemoteInfo
+type RemoteProvider interface {
+	// Generate returns the completion for a request to one of the plugin's providers
+	Generate(provider string, req *llm.ProviderRequest) (string, error)
+}
+
+// Serve runs a RemotePlugin implementation; call it from a plugin's main function
+func Serve(impl RemotePlugin) {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         goplugin.PluginSet{remotePluginKey: &grpcPlugin{impl: impl}},
+		GRPCServer:      goplugin.DefaultGRPCServer,
+	})
+}
+
+// remotePluginKey names the plugin interface dispensed by go-plugin
+const remotePluginKey = "comma"
+
+// jsonCodec encodes plugin messages as JSON, so the service can be declared
+// without generated protobuf code
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return "comma-json" }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// runHookRequest is the RunHook call payload
+type runHookRequest struct {
+	Hook    string       `json:"hook"`
+	Context *HookContext `json:"context"`
+}
+
+// generateRequest is the Generate call payload
+type generateRequest struct {
+	Provider string               `json:"provider"`
+	Request  *llm.ProviderRequest `json:"request"`
+}
+
+// generateResponse is the Generate call result
+type generateResponse struct {
+	Text string `json:"text"`
+}
+
+// serviceDesc declares the plugin's gRPC service by hand
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "comma.Plugin",
+	HandlerType: (*RemotePlugin)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Info",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				if err := dec(&struct{}{}); err != nil {
+					return nil, err
+				}
+				return srv.(RemotePlugin).Info()
+			},
+		},
+		{
+			MethodName: "RunHook",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				var req runHookRequest
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				return srv.(RemotePlugin).RunHook(req.Hook, req.Context)
+			},
+		},
+		{
+			MethodName: "Generate",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				var req generateRequest
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				provider, ok := srv.(RemoteProvider)
+				if !ok {
+					return nil, status.Errorf(codes.Unimplemented, "plugin does not provide LLM providers")
+				}
+				text, err := provider.Generate(req.Provider, req.Request)
+				if err != nil {
+					return nil, err
+				}
+				return &generateResponse{Text: text}, nil
+			},
+		},
+	},
+	Metadata: "comma/plugin",
+}
+
+// grpcPlugin connects a RemotePlugin to go-plugin's gRPC transport
+type grpcPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	impl RemotePlugin
+}
+
+func (p *grpcPlugin) GRPCServer(_ *goplugin.GRPCBroker, s *grpc.Server) error {
+	s.RegisterService(&serviceDesc, p.impl)
+	return nil
+}
+
+func (p *grpcPlugin) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return &grpcClient{conn: conn}, nil
+}
+
+// grpcClient is the host side of a RemotePlugin
+type grpcClient struct {
+	conn *grpc.ClientConn
+}
+
+func (c *grpcClient) Info() (*RemoteInfo, error) {
+	var info RemoteInfo
+	if err := c.invoke("Info", &struct{}{}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (c *grpcClient) RunHook(hook string, hctx *HookContext) (*HookContext, error) {
+	var out HookContext
+	if err := c.invoke("RunHook", &runHookRequest{Hook: hook, Context: hctx}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *grpcClient) Generate(provider string, req *llm.ProviderRequest) (string, error) {
+	var out generateResponse
+	if err := c.invoke("Generate", &generateRequest{Provider: provider, Request: req}, &out); err != nil {
+		return "", err
+	}
+	return out.Text, nil
+}
+
+func (c *grpcClient) invoke(method string, in, out interface{}) error {
+	return c.conn.Invoke(context.Background(), "/comma.Plugin/"+method, in, out,
+		grpc.CallContentSubtype(jsonCodec{}.Name()))
+}
+
+// remotePlugin adapts a running gRPC plugin to the Plugin interface
+type remotePlugin struct {
+	client *goplugin.Client
+	impl   RemotePlugin
+	info   *RemoteInfo
+}
+
+func (r *remotePlugin) Initialize(*config.AppContext) error { return nil }
+func (r *remotePlugin) Name() string                        { return r.info.Name }
+func (r *remotePlugin) Version() string                     { return r.info.Version }
+
+func (r *remotePlugin) Shutdown() error {
+	r.client.Kill()
+	return nil
+}
+
+// startRemote launches a plugin executable and asks it to describe itself
+func startRemote(path string) (*remotePlugin, error) {
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          goplugin.PluginSet{remotePluginKey: &grpcPlugin{}},
+		Cmd:              exec.Command(path),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		Logger:           hclog.New(&hclog.LoggerOptions{Name: "plugin", Level: hclog.Warn, Output: os.Stderr}),
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	raw, err := rpcClient.Dispense(remotePluginKey)
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("failed to connect to plugin: %w", err)
+	}
+
+	impl := raw.(RemotePlugin)
+	info, err := impl.Info()
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("failed to describe plugin: %w", err)
+	}
+
+	return &remotePlugin{client: client, impl: impl, info: info}, nil
+}
+
+// loadRemote starts a gRPC plugin and registers it for the hooks it asked for
+func (m *Manager) loadRemote(path string) error {
+	remote, err := startRemote(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.plugins[remote.Name()] = remote
+	for _, hook := range remote.info.Hooks {
+		hook := hook
+		m.hooks[hook] = append(m.hooks[hook], Hook{
+			PluginName: remote.Name(),
+			Callback: func(args ...interface{}) error {
+				hctx, ok := args[0].(*HookContext)
+				if !ok {
+					return fmt.Errorf("unexpected hook arguments")
+				}
+
+				out, err := remote.impl.RunHook(hook, hctx)
+				if err != nil {
+					return &VetoError{Hook: hook, Script: remote.Name(), Reason: status.Convert(err).Message()}
+				}
+				if out != nil {
+					*hctx = *out
+				}
+				return nil
+			},
+		})
+	}
+
+	for _, info := range remote.info.Providers {
+		provider := &remoteProvider{info: info, impl: remote.impl.(RemoteProvider)}
+		if err := llm.RegisterProvider(provider); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Plugin %s: %v\n", remote.Name(), err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Loaded plugin: %s v%s\n", remote.Name(), remote.Version())
+	return nil
+}
+
+// remoteProvider adapts a provider offered by a gRPC plugin to llm.Provider
+type remoteProvider struct {
+	info llm.ProviderInfo
+	impl RemoteProvider
+}
+
+func (p *remoteProvider) Info() llm.ProviderInfo { return p.info }
+
+func (p *remoteProvider) Generate(req *llm.ProviderRequest) (string, error) {
+	text, err := p.impl.Generate(p.info.Name, req)
+	if err != nil {
+		return "", fmt.Errorf("%s provider failed: %s", p.info.Name, status.Convert(err).Message())
+	}
+	return text, nil
+}
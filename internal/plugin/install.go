@@ -0,0 +1,391 @@
+// internal/plugin/install.go
+package plugin
+
+import (
+	"crypto/sha256"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"plugin"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+)
+
+// manifestFile records where installed plugins came from
+const manifestFile = "installed.json"
+
+// Plugin kinds
+const (
+	KindGo   = "go"   // Go plugin (.so) loaded into the process
+	KindGRPC = "grpc" // Separate executable served over gRPC
+)
+
+// InstalledPlugin describes a plugin file in the plugins directory
+type InstalledPlugin struct {
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	Path        string    `json:"path"`
+	Source      string    `json:"source,omitempty"`
+	SHA256      string    `json:"sha256"`
+	Size        int64     `json:"size"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Dir returns the directory plugins are loaded from
+func (m *Manager) Dir() string {
+	return m.pluginsDir
+}
+
+// Installed lists the plugin files in the plugins directory, including
+// ones that were copied there by hand
+func (m *Manager) Installed() ([]InstalledPlugin, error) {
+	files, err := os.ReadDir(m.pluginsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	manifest := m.loadManifest()
+
+	var installed []InstalledPlugin
+	for _, file := range files {
+		kind := KindGo
+		switch {
+		case file.IsDir():
+			continue
+		case strings.HasPrefix(file.Name(), RemotePrefix):
+			kind = KindGRPC
+		case filepath.Ext(file.Name()) != ".so":
+			continue
+		}
+
+		name := pluginName(file.Name())
+		entry, ok := manifest[name]
+		if !ok {
+			entry = InstalledPlugin{Name: name, Kind: kind, Path: filepath.Join(m.pluginsDir, file.Name())}
+			if info, err := file.Info(); err == nil {
+				entry.Size = info.Size()
+				entry.InstalledAt = info.ModTime()
+			}
+		}
+		entry.Kind = kind
+		installed = append(installed, entry)
+	}
+
+	sort.Slice(installed, func(i, j int) bool { return installed[i].Name < installed[j].Name })
+	return installed, nil
+}
+
+// Get returns an installed plugin by name
+func (m *Manager) Get(name string) (*InstalledPlugin, error) {
+	installed, err := m.Installed()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range installed {
+		if p.Name == name {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("plugin not installed: %s", name)
+}
+
+// Describe opens a plugin without initializing it and returns its reported
+// name and version
+func (m *Manager) Describe(name string) (pluginName, version string, err error) {
+	installed, err := m.Get(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	if installed.Kind == KindGRPC {
+		remote, err := startRemote(installed.Path)
+		if err != nil {
+			return "", "", err
+		}
+		defer remote.Shutdown()
+		return remote.Name(), remote.Version(), nil
+	}
+
+	// Opening a library that is not a compatible Go plugin aborts the process
+	if err := checkToolchain(installed.Path); err != nil {
+		return "", "", err
+	}
+
+	p, err := plugin.Open(installed.Path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open plugin: %w", err)
+	}
+
+	sym, err := p.Lookup("Plugin")
+	if err != nil {
+		return "", "", fmt.Errorf("plugin doesn't export 'Plugin' symbol: %w", err)
+	}
+
+	loaded, ok := sym.(Plugin)
+	if !ok {
+		return "", "", fmt.Errorf("plugin doesn't implement Plugin interface")
+	}
+
+	return loaded.Name(), loaded.Version(), nil
+}
+
+// Install copies a plugin from a local path or an https URL into the plugins
+// directory, after checking it was built for this platform. Go plugins must
+// be .so files; anything else is installed as a gRPC plugin executable. When
+// wantSHA256 is set, the plugin is only installed if its checksum matches.
+func (m *Manager) Install(source, wantSHA256 string) (*InstalledPlugin, error) {
+	name, localPath, cleanup, err := m.fetchPlugin(source)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	if wantSHA256 != "" && !strings.EqualFold(wantSHA256, checksum) {
+		return nil, fmt.Errorf("plugin checksum mismatch: got %s, want %s", checksum, wantSHA256)
+	}
+
+	kind := KindGRPC
+	if strings.HasSuffix(source, ".so") {
+		kind = KindGo
+		if err := checkPluginSupport(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkPlatform(localPath, kind == KindGo); err != nil {
+		return nil, err
+	}
+	if kind == KindGo {
+		if err := checkToolchain(localPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// Write to a temporary file first so a failed copy never leaves a
+	// truncated plugin behind for the next start to load
+	target := filepath.Join(m.pluginsDir, name+".so")
+	if kind == KindGRPC {
+		target = filepath.Join(m.pluginsDir, RemotePrefix+name)
+		if runtime.GOOS == "windows" {
+			target += ".exe"
+		}
+	}
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, data, 0755); err != nil {
+		return nil, fmt.Errorf("failed to write plugin: %w", err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to install plugin: %w", err)
+	}
+
+	installed := InstalledPlugin{
+		Name:        name,
+		Kind:        kind,
+		Path:        target,
+		Source:      source,
+		SHA256:      checksum,
+		Size:        int64(len(data)),
+		InstalledAt: time.Now(),
+	}
+
+	manifest := m.loadManifest()
+	manifest[name] = installed
+	if err := m.saveManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	return &installed, nil
+}
+
+// Remove deletes an installed plugin
+func (m *Manager) Remove(name string) error {
+	installed, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(installed.Path); err != nil {
+		return fmt.Errorf("failed to remove plugin: %w", err)
+	}
+
+	manifest := m.loadManifest()
+	delete(manifest, name)
+	return m.saveManifest(manifest)
+}
+
+// fetchPlugin resolves a source to a local file, downloading URLs to a
+// temporary file. It returns the plugin name derived from the file name.
+func (m *Manager) fetchPlugin(source string) (name, localPath string, cleanup func(), err error) {
+	cleanup = func() {}
+
+	if !strings.Contains(source, "://") {
+		if _, err := os.Stat(source); err != nil {
+			return "", "", cleanup, fmt.Errorf("plugin file not found: %s", source)
+		}
+		return pluginName(filepath.Base(source)), source, cleanup, nil
+	}
+
+	parsed, err := url.Parse(source)
+	if err != nil {
+		return "", "", cleanup, fmt.Errorf("invalid plugin URL: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return "", "", cleanup, fmt.Errorf("plugin URL must use https: %s", source)
+	}
+	if m.ctx.IsOffline() {
+		return "", "", cleanup, fmt.Errorf("cannot download plugin: %w", apperrors.ErrOffline)
+	}
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return "", "", cleanup, fmt.Errorf("failed to download plugin: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", cleanup, fmt.Errorf("plugin download failed with status: %s", resp.Status)
+	}
+
+	tmp, err := os.CreateTemp("", "comma-plugin-*.so")
+	if err != nil {
+		return "", "", cleanup, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	cleanup = func() { os.Remove(tmp.Name()) }
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return "", "", cleanup, fmt.Errorf("failed to save plugin: %w", err)
+	}
+	tmp.Close()
+
+	return pluginName(path.Base(parsed.Path)), tmp.Name(), cleanup, nil
+}
+
+// pluginName derives a plugin name from its file name
+func pluginName(file string) string {
+	file = strings.TrimPrefix(file, RemotePrefix)
+	return strings.TrimSuffix(strings.TrimSuffix(file, ".so"), ".exe")
+}
+
+// checkPluginSupport fails on platforms where Go cannot load plugins
+func checkPluginSupport() error {
+	switch runtime.GOOS {
+	case "linux", "darwin", "freebsd":
+		return nil
+	default:
+		return fmt.Errorf("plugins are not supported on %s", runtime.GOOS)
+	}
+}
+
+// checkPlatform verifies a plugin was built for this OS and architecture,
+// and is a shared library when sharedLib is set
+func checkPlatform(path string, sharedLib bool) error {
+	switch runtime.GOOS {
+	case "windows":
+		file, err := pe.Open(path)
+		if err != nil {
+			return fmt.Errorf("plugin is not a Windows executable: %w", err)
+		}
+		defer file.Close()
+
+		want := map[string]uint16{"amd64": pe.IMAGE_FILE_MACHINE_AMD64, "arm64": pe.IMAGE_FILE_MACHINE_ARM64}[runtime.GOARCH]
+		if file.Machine != want {
+			return fmt.Errorf("plugin was built for machine type %#x, not %s", file.Machine, runtime.GOARCH)
+		}
+
+	case "darwin":
+		file, err := macho.Open(path)
+		if err != nil {
+			return fmt.Errorf("plugin is not a macOS binary: %w", err)
+		}
+		defer file.Close()
+
+		want := map[string]macho.Cpu{"amd64": macho.CpuAmd64, "arm64": macho.CpuArm64}[runtime.GOARCH]
+		if file.Cpu != want {
+			return fmt.Errorf("plugin was built for %s, not %s", file.Cpu, runtime.GOARCH)
+		}
+
+	default:
+		file, err := elf.Open(path)
+		if err != nil {
+			return fmt.Errorf("plugin is not an ELF binary: %w", err)
+		}
+		defer file.Close()
+
+		if sharedLib && file.Type != elf.ET_DYN {
+			return fmt.Errorf("plugin is not a shared library (ELF type %s)", file.Type)
+		}
+
+		want := map[string]elf.Machine{
+			"amd64": elf.EM_X86_64,
+			"arm64": elf.EM_AARCH64,
+			"386":   elf.EM_386,
+			"arm":   elf.EM_ARM,
+		}[runtime.GOARCH]
+		if file.Machine != want {
+			return fmt.Errorf("plugin was built for %s, not %s", file.Machine, runtime.GOARCH)
+		}
+	}
+
+	return nil
+}
+
+// checkToolchain verifies a library is a Go plugin built with the same Go
+// version as Comma, which the plugin package requires
+func checkToolchain(path string) error {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("not a Go plugin: %w", err)
+	}
+
+	if info.GoVersion != runtime.Version() {
+		return fmt.Errorf("plugin was built with %s but Comma was built with %s", info.GoVersion, runtime.Version())
+	}
+	return nil
+}
+
+// loadManifest reads the install records, returning an empty set if there are none
+func (m *Manager) loadManifest() map[string]InstalledPlugin {
+	manifest := make(map[string]InstalledPlugin)
+	if data, err := os.ReadFile(filepath.Join(m.pluginsDir, manifestFile)); err == nil {
+		json.Unmarshal(data, &manifest)
+	}
+	return manifest
+}
+
+// saveManifest writes the install records
+func (m *Manager) saveManifest(manifest map[string]InstalledPlugin) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plugin manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(m.pluginsDir, manifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write plugin manifest: %w", err)
+	}
+	return nil
+}
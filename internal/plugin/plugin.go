@@ -6,9 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"plugin"
+	"strings"
 	"sync"
 
 	"github.com/jasonKoogler/comma/internal/config"
+	"github.com/jasonKoogler/comma/internal/llm"
 )
 
 // Plugin represents a loadable extension to Comma
@@ -26,7 +28,16 @@ type Plugin interface {
 	Shutdown() error
 }
 
-// Hook points where plugins can register callbacks
+// ProviderPlugin is implemented by Go plugins that add LLM providers.
+// The providers are registered once the plugin has been initialized and
+// can then be selected like the built-in ones.
+type ProviderPlugin interface {
+	Providers() []llm.Provider
+}
+
+// Hook points where plugins can register callbacks. Callbacks receive a
+// *HookContext as their only argument and may modify it; returning an
+// error vetoes the operation.
 const (
 	HookPreCommit    = "pre-commit"
 	HookPostCommit   = "post-commit"
@@ -69,31 +80,44 @@ func NewManager(ctx *config.AppContext) *Manager {
 
 // Initialize loads and initializes all available plugins
 func (m *Manager) Initialize() error {
+	// The lock is not held while loading, since plugins register their
+	// hooks through RegisterHook during initialization
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	if m.initialized {
+		m.mu.Unlock()
 		return nil
 	}
+	m.initialized = true
+	m.mu.Unlock()
 
-	// List plugin files (*.so)
+	// List plugin files: Go plugins (*.so) and gRPC plugin executables
 	files, err := os.ReadDir(m.pluginsDir)
 	if err != nil {
 		return fmt.Errorf("failed to read plugins directory: %w", err)
 	}
 
 	for _, file := range files {
+		if !file.IsDir() && strings.HasPrefix(file.Name(), RemotePrefix) {
+			if err := m.loadRemote(filepath.Join(m.pluginsDir, file.Name())); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to load plugin %s: %v\n", file.Name(), err)
+			}
+			continue
+		}
+
 		if file.IsDir() || filepath.Ext(file.Name()) != ".so" {
 			continue
 		}
 
 		pluginPath := filepath.Join(m.pluginsDir, file.Name())
+		if err := checkToolchain(pluginPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Skipping plugin %s: %v\n", file.Name(), err)
+			continue
+		}
 		if err := m.loadPlugin(pluginPath); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: Failed to load plugin %s: %v\n", file.Name(), err)
 		}
 	}
 
-	m.initialized = true
 	return nil
 }
 
@@ -117,14 +141,27 @@ func (m *Manager) loadPlugin(path string) error {
 		return fmt.Errorf("plugin doesn't implement Plugin interface")
 	}
 
+	// Register the plugin first so it can register hooks while initializing
+	m.mu.Lock()
+	m.plugins[plugin.Name()] = plugin
+	m.mu.Unlock()
+
 	// Initialize the plugin
 	if err := plugin.Initialize(m.ctx); err != nil {
+		m.mu.Lock()
+		delete(m.plugins, plugin.Name())
+		m.mu.Unlock()
 		return fmt.Errorf("plugin initialization failed: %w", err)
 	}
 
-	// Register the plugin
-	m.plugins[plugin.Name()] = plugin
-	fmt.Printf("Loaded plugin: %s v%s\n", plugin.Name(), plugin.Version())
+	if pp, ok := plugin.(ProviderPlugin); ok {
+		for _, provider := range pp.Providers() {
+			if err := llm.RegisterProvider(provider); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Plugin %s: %v\n", plugin.Name(), err)
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Loaded plugin: %s v%s\n", plugin.Name(), plugin.Version())
 
 	return nil
 }
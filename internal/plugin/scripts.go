@@ -0,0 +1,187 @@
+// internal/plugin/scripts.go
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// scriptTimeout bounds how long a single hook script may run
+const scriptTimeout = 30 * time.Second
+
+// HookContext is the structured data passed to hooks. Scripts receive it as
+// JSON on stdin and may print a JSON object with a replacement prompt or
+// message on stdout.
+type HookContext struct {
+	Hook       string `json:"hook"`
+	Repository string `json:"repository"`
+	Branch     string `json:"branch,omitempty"`
+	Changes    string `json:"changes,omitempty"`
+	Prompt     string `json:"prompt,omitempty"`
+	Message    string `json:"message,omitempty"`
+	CommitHash string `json:"commit_hash,omitempty"`
+}
+
+// VetoError is returned when a hook rejects the operation it was run for
+type VetoError struct {
+	Hook   string
+	Script string
+	Reason string
+}
+
+func (e *VetoError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%s hook %s vetoed the operation", e.Hook, e.Script)
+	}
+	return fmt.Sprintf("%s hook %s vetoed the operation: %s", e.Hook, e.Script, e.Reason)
+}
+
+// Hooks runs executable scripts from <configDir>/hooks/<hook>.d/ in name order,
+// followed by any plugin callbacks registered for the hook point.
+// A script that exits non-zero vetoes the operation; its stderr is the reason.
+type Hooks struct {
+	dir     string
+	manager *Manager
+}
+
+// NewHooks creates a hook runner for scripts under configDir
+func NewHooks(configDir string) *Hooks {
+	return &Hooks{dir: filepath.Join(configDir, "hooks")}
+}
+
+// SetManager also dispatches hook points to plugins. Plugins are loaded the
+// first time a hook runs, so commands that never reach a hook don't start them.
+func (h *Hooks) SetManager(manager *Manager) {
+	h.manager = manager
+}
+
+// Run executes every script and plugin callback for a hook point, letting
+// each one see the changes made by those before it
+func (h *Hooks) Run(hook string, hctx *HookContext) error {
+	if h == nil {
+		return nil
+	}
+
+	scripts, err := h.scripts(hook)
+	if err != nil {
+		return err
+	}
+
+	hctx.Hook = hook
+	for _, script := range scripts {
+		if err := runScript(script, hctx); err != nil {
+			return err
+		}
+	}
+
+	if h.manager == nil {
+		return nil
+	}
+	if err := h.manager.Initialize(); err != nil {
+		return fmt.Errorf("failed to load plugins: %w", err)
+	}
+	if errs := h.manager.ExecuteHook(hook, hctx); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// scripts lists the executable files for a hook point
+func (h *Hooks) scripts(hook string) ([]string, error) {
+	dir := filepath.Join(h.dir, hook+".d")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read hooks directory: %w", err)
+	}
+
+	var scripts []string
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() || !isExecutable(info) {
+			continue // Non-executable files are ignored, like git's sample hooks
+		}
+		scripts = append(scripts, filepath.Join(dir, entry.Name()))
+	}
+
+	sort.Strings(scripts)
+	return scripts, nil
+}
+
+// isExecutable reports whether a hook file can be run. Windows has no execute
+// bit, so there the extension must be listed in PATHEXT, as for commands
+// found on PATH.
+func isExecutable(info os.FileInfo) bool {
+	if runtime.GOOS != "windows" {
+		return info.Mode()&0111 != 0
+	}
+
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".com;.exe;.bat;.cmd"
+	}
+	ext := filepath.Ext(info.Name())
+	for _, allowed := range filepath.SplitList(pathext) {
+		if allowed != "" && strings.EqualFold(allowed, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// runScript runs one hook script and applies any changes it prints
+func runScript(script string, hctx *HookContext) error {
+	input, err := json.Marshal(hctx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hook context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scriptTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(), "COMMA_HOOK="+hctx.Hook)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok && ctx.Err() == nil {
+			return &VetoError{Hook: hctx.Hook, Script: filepath.Base(script), Reason: strings.TrimSpace(stderr.String())}
+		}
+		return fmt.Errorf("failed to run %s hook %s: %w", hctx.Hook, filepath.Base(script), err)
+	}
+
+	output := bytes.TrimSpace(stdout.Bytes())
+	if len(output) == 0 {
+		return nil
+	}
+
+	var changes struct {
+		Prompt  *string `json:"prompt"`
+		Message *string `json:"message"`
+	}
+	if err := json.Unmarshal(output, &changes); err != nil {
+		return fmt.Errorf("%s hook %s printed invalid JSON: %w", hctx.Hook, filepath.Base(script), err)
+	}
+
+	if changes.Prompt != nil {
+		hctx.Prompt = *changes.Prompt
+	}
+	if changes.Message != nil {
+		hctx.Message = *changes.Message
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// internal/security/gitleaks.go
+package security
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// gitleaksConfig is the subset of a gitleaks ruleset that maps onto scanner patterns
+type gitleaksConfig struct {
+	Title string         `toml:"title"`
+	Rules []gitleaksRule `toml:"rules"`
+}
+
+// gitleaksRule is a single gitleaks detection rule
+type gitleaksRule struct {
+	ID          string   `toml:"id"`
+	Description string   `toml:"description"`
+	Regex       string   `toml:"regex"`
+	Tags        []string `toml:"tags"`
+}
+
+// LoadGitleaksRules reads a gitleaks TOML ruleset and converts its rules into
+// custom patterns. Rules that only match on file paths have no regex and are
+// skipped, since the scanner inspects diff content.
+func LoadGitleaksRules(path string) ([]CustomPattern, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gitleaks config: %w", err)
+	}
+
+	var config gitleaksConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse gitleaks config: %w", err)
+	}
+
+	patterns := make([]CustomPattern, 0, len(config.Rules))
+	for _, rule := range config.Rules {
+		if rule.Regex == "" {
+			continue
+		}
+
+		name := rule.ID
+		if name == "" {
+			name = rule.Description
+		}
+
+		suggestion := "Remove this secret from the change"
+		if rule.Description != "" {
+			suggestion = fmt.Sprintf("%s detected - remove it from the change", rule.Description)
+		}
+
+		patterns = append(patterns, CustomPattern{
+			Name:       name,
+			Regex:      rule.Regex,
+			Severity:   "HIGH",
+			Suggestion: suggestion,
+		})
+	}
+
+	return patterns, nil
+}
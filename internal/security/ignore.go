@@ -0,0 +1,116 @@
+// internal/security/ignore.go
+package security
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the allowlist file read from the repository root
+const IgnoreFileName = ".commaignore"
+
+// InlineIgnoreMarker suppresses findings on any added line containing it
+const InlineIgnoreMarker = "comma:ignore"
+
+// Allowlist suppresses known false positives by path, pattern type, or fingerprint
+type Allowlist struct {
+	paths        []string
+	types        map[string]bool
+	fingerprints map[string]bool
+}
+
+// LoadAllowlist reads an allowlist file. A missing file yields an empty allowlist.
+//
+// Each non-comment line is one rule:
+//
+//	path:testdata/**       suppress findings in matching files
+//	type:IP Address        suppress a pattern type everywhere
+//	fingerprint:1a2b3c...  suppress one specific finding
+//	docs/*.md              lines without a prefix are path globs
+func LoadAllowlist(path string) (*Allowlist, error) {
+	a := &Allowlist{
+		types:        make(map[string]bool),
+		fingerprints: make(map[string]bool),
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return a, nil
+		}
+		return nil, fmt.Errorf("failed to open ignore file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "type:"):
+			a.types[strings.TrimSpace(strings.TrimPrefix(line, "type:"))] = true
+		case strings.HasPrefix(line, "fingerprint:"):
+			a.fingerprints[strings.TrimSpace(strings.TrimPrefix(line, "fingerprint:"))] = true
+		case strings.HasPrefix(line, "path:"):
+			a.paths = append(a.paths, strings.TrimSpace(strings.TrimPrefix(line, "path:")))
+		default:
+			a.paths = append(a.paths, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
+	return a, nil
+}
+
+// Allows reports whether a finding is suppressed by the allowlist
+func (a *Allowlist) Allows(f Finding) bool {
+	if a == nil {
+		return false
+	}
+
+	if a.types[f.Type] || a.fingerprints[f.Fingerprint] {
+		return true
+	}
+
+	if f.FilePath == "" {
+		return false
+	}
+
+	for _, pattern := range a.paths {
+		if matchPath(pattern, f.FilePath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchPath matches a file path against a glob. A trailing "/" or "/**"
+// matches everything below a directory, and patterns without a slash
+// also match the file's base name.
+func matchPath(pattern, path string) bool {
+	if strings.HasSuffix(pattern, "/**") || strings.HasSuffix(pattern, "/") {
+		dir := strings.TrimSuffix(strings.TrimSuffix(pattern, "**"), "/")
+		return path == dir || strings.HasPrefix(path, dir+"/")
+	}
+
+	if matched, _ := filepath.Match(pattern, path); matched {
+		return true
+	}
+
+	if !strings.Contains(pattern, "/") {
+		matched, _ := filepath.Match(pattern, filepath.Base(path))
+		return matched
+	}
+
+	return false
+}
@@ -0,0 +1,22 @@
+// internal/security/pii.go
+package security
+
+import "regexp"
+
+// piiPatterns detect personal data. They are opt-in because they are noisier
+// than the secret patterns, e.g. email addresses in author lists.
+var piiPatterns = map[string]*regexp.Regexp{
+	"Email Address": regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`),
+	"Phone Number":  regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?\(?\b\d{3}\)?[\s.-]\d{3}[\s.-]\d{4}\b`),
+	"US SSN":        regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	"UK NI Number":  regexp.MustCompile(`\b[A-CEGHJ-PR-TW-Z]{2}\s?\d{2}\s?\d{2}\s?\d{2}\s?[A-D]\b`),
+}
+
+// EnablePII adds the personal data patterns to the scanner
+func (s *Scanner) EnablePII() {
+	for name, pattern := range piiPatterns {
+		if _, exists := s.patterns[name]; !exists {
+			s.patterns[name] = pattern
+		}
+	}
+}
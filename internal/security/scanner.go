@@ -2,26 +2,46 @@
 package security
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"regexp"
 	"strings"
 )
 
 // Finding represents a security concern found in code
 type Finding struct {
-	Type        string
-	LineContent string
-	LineNumber  int
-	Severity    string
-	Suggestion  string
+	Type        string `json:"type"`
+	FilePath    string `json:"file_path"`
+	LineContent string `json:"line_content"`
+	LineNumber  int    `json:"line_number"`
+	Severity    string `json:"severity"`
+	Suggestion  string `json:"suggestion"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// CustomPattern is a user-defined detection rule from configuration
+type CustomPattern struct {
+	Name       string `mapstructure:"name" yaml:"name"`
+	Regex      string `mapstructure:"regex" yaml:"regex"`
+	Severity   string `mapstructure:"severity" yaml:"severity"`
+	Suggestion string `mapstructure:"suggestion" yaml:"suggestion"`
 }
 
 // Scanner detects sensitive data patterns
 type Scanner struct {
-	patterns map[string]*regexp.Regexp
+	patterns    map[string]*regexp.Regexp
+	severities  map[string]string
+	suggestions map[string]string
+	overrides   map[string]string
+	allowlist   *Allowlist
 }
 
-// NewScanner creates a scanner with default patterns
-func NewScanner() *Scanner {
+// NewScanner creates a scanner with the default patterns plus any custom
+// patterns. A custom pattern with the same name as a built-in replaces it.
+func NewScanner(custom []CustomPattern) (*Scanner, error) {
 	s := &Scanner{
 		patterns: map[string]*regexp.Regexp{
 			"AWS Key":           regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
@@ -31,47 +51,148 @@ func NewScanner() *Scanner {
 			"Connection String": regexp.MustCompile(`(?i)(mongodb|redis|postgres|mysql)://[^\s'"]+`),
 			"IP Address":        regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`),
 		},
+		severities:  make(map[string]string),
+		suggestions: make(map[string]string),
+		overrides:   make(map[string]string),
+	}
+
+	for _, p := range custom {
+		if err := s.AddPattern(p); err != nil {
+			return nil, err
+		}
 	}
-	return s
+
+	return s, nil
+}
+
+// AddPattern compiles a custom pattern and adds it to the scanner
+func (s *Scanner) AddPattern(p CustomPattern) error {
+	if p.Name == "" {
+		return fmt.Errorf("custom pattern is missing a name")
+	}
+
+	re, err := regexp.Compile(p.Regex)
+	if err != nil {
+		return fmt.Errorf("invalid regex for pattern %q: %w", p.Name, err)
+	}
+
+	if p.Severity != "" {
+		severity, err := ParseSeverity(p.Severity)
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", p.Name, err)
+		}
+		s.severities[p.Name] = severity
+	}
+
+	s.patterns[p.Name] = re
+	if p.Suggestion != "" {
+		s.suggestions[p.Name] = p.Suggestion
+	}
+
+	return nil
+}
+
+// SetAllowlist suppresses findings matched by the allowlist
+func (s *Scanner) SetAllowlist(a *Allowlist) {
+	s.allowlist = a
 }
 
 // ScanChanges scans git diff for sensitive information
 func (s *Scanner) ScanChanges(diff string) []Finding {
+	findings, _ := s.ScanReader(strings.NewReader(diff))
+	return findings
+}
+
+// ScanReader scans a git diff line by line as it is read, so diffs of any
+// size can be scanned without holding them in memory
+func (s *Scanner) ScanReader(r io.Reader) ([]Finding, error) {
 	findings := []Finding{}
-	lines := strings.Split(diff, "\n")
+	reader := bufio.NewReader(r)
+	currentFile := ""
+
+	for i := 0; ; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return findings, err
+		}
+		if line == "" && err == io.EOF {
+			break
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		// Track which file the following hunks belong to
+		if strings.HasPrefix(line, "+++ ") {
+			currentFile = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			continue
+		}
 
-	for i, line := range lines {
 		// Only scan added lines (starting with +)
-		if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
+		if !strings.HasPrefix(line, "+") {
 			continue
 		}
 
 		cleanLine := strings.TrimPrefix(line, "+")
 
+		// Respect inline suppression comments
+		if strings.Contains(cleanLine, InlineIgnoreMarker) {
+			continue
+		}
+
 		for patternName, pattern := range s.patterns {
 			if pattern.MatchString(cleanLine) {
-				findings = append(findings, Finding{
+				finding := Finding{
 					Type:        patternName,
+					FilePath:    currentFile,
 					LineContent: cleanLine,
 					LineNumber:  i + 1,
 					Severity:    s.getSeverity(patternName),
 					Suggestion:  s.getSuggestion(patternName),
-				})
+					Fingerprint: fingerprint(patternName, currentFile, cleanLine),
+				}
+
+				if s.allowlist.Allows(finding) {
+					continue
+				}
+
+				findings = append(findings, finding)
 			}
 		}
 	}
 
-	return findings
+	return findings, nil
 }
 
-// getSeverity returns severity level for a pattern type
+// fingerprint identifies a finding independently of its line number, so
+// suppressions survive unrelated edits to the same file
+func fingerprint(patternType, filePath, line string) string {
+	hash := sha256.Sum256([]byte(patternType + "\x00" + filePath + "\x00" + strings.TrimSpace(line)))
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+// getSeverity returns severity level for a pattern type. Configured overrides
+// win over custom pattern severities, which win over the built-in defaults.
 func (s *Scanner) getSeverity(patternType string) string {
-	// Map pattern types to severity levels
-	return "HIGH" // Default high severity for sensitive data
+	if severity, ok := s.overrides[strings.ToLower(patternType)]; ok {
+		return severity
+	}
+
+	if severity, ok := s.severities[patternType]; ok {
+		return severity
+	}
+
+	if severity, ok := defaultSeverities[patternType]; ok {
+		return severity
+	}
+
+	return SeverityHigh // Default high severity for unknown sensitive data
 }
 
 // getSuggestion provides remediation advice
 func (s *Scanner) getSuggestion(patternType string) string {
+	if suggestion, ok := s.suggestions[patternType]; ok {
+		return suggestion
+	}
+
 	suggestions := map[string]string{
 		"AWS Key":           "Store AWS credentials using environment variables or AWS credential providers",
 		"Generic API Key":   "Move API keys to environment variables or a secure vault",
@@ -79,6 +200,10 @@ func (s *Scanner) getSuggestion(patternType string) string {
 		"Private Key":       "Remove private keys from code. Store in a secure location outside the repository",
 		"Connection String": "Move connection strings to environment variables or configuration files",
 		"IP Address":        "Consider using hostnames instead of hardcoded IP addresses",
+		"Email Address":     "Use placeholder addresses such as user@example.com in code and fixtures",
+		"Phone Number":      "Replace real phone numbers with reserved test numbers",
+		"US SSN":            "Never commit national identifiers. Use synthetic test data",
+		"UK NI Number":      "Never commit national identifiers. Use synthetic test data",
 	}
 
 	if suggestion, ok := suggestions[patternType]; ok {
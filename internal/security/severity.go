@@ -0,0 +1,78 @@
+// internal/security/severity.go
+package security
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity levels, from least to most severe
+const (
+	SeverityLow      = "LOW"
+	SeverityMedium   = "MEDIUM"
+	SeverityHigh     = "HIGH"
+	SeverityCritical = "CRITICAL"
+)
+
+// severityRanks orders severity levels for comparison
+var severityRanks = map[string]int{
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// defaultSeverities are the built-in severities for the default patterns
+var defaultSeverities = map[string]string{
+	"AWS Key":           SeverityCritical,
+	"Private Key":       SeverityCritical,
+	"Generic API Key":   SeverityHigh,
+	"Password":          SeverityHigh,
+	"Connection String": SeverityMedium,
+	"IP Address":        SeverityLow,
+	"Email Address":     SeverityMedium,
+	"Phone Number":      SeverityMedium,
+	"US SSN":            SeverityHigh,
+	"UK NI Number":      SeverityHigh,
+}
+
+// ParseSeverity normalizes a severity name, rejecting unknown levels
+func ParseSeverity(severity string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(severity))
+	if _, ok := severityRanks[normalized]; !ok {
+		return "", fmt.Errorf("invalid severity %q (use LOW, MEDIUM, HIGH or CRITICAL)", severity)
+	}
+	return normalized, nil
+}
+
+// AtLeast reports whether the finding is at or above the given severity.
+// An unrecognized threshold is treated as HIGH.
+func (f Finding) AtLeast(severity string) bool {
+	threshold, ok := severityRanks[strings.ToUpper(severity)]
+	if !ok {
+		threshold = severityRanks[SeverityHigh]
+	}
+	return severityRanks[f.Severity] >= threshold
+}
+
+// FilterBySeverity returns the findings at or above the given severity
+func FilterBySeverity(findings []Finding, severity string) []Finding {
+	filtered := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		if f.AtLeast(severity) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// SetSeverityOverride changes the severity reported for a pattern.
+// Pattern names are matched case-insensitively.
+func (s *Scanner) SetSeverityOverride(patternType, severity string) error {
+	normalized, err := ParseSeverity(severity)
+	if err != nil {
+		return fmt.Errorf("severity override for %q: %w", patternType, err)
+	}
+	s.overrides[strings.ToLower(patternType)] = normalized
+	return nil
+}
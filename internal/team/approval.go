@@ -0,0 +1,273 @@
+// internal/team/approval.go
+package team
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Approval statuses
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+)
+
+// Approval records a commit waiting for (or granted) sign-off by a team admin
+type Approval struct {
+	ID          string    `json:"id"`
+	Team        string    `json:"team"`
+	Message     string    `json:"message"`
+	Author      string    `json:"author"`
+	Reviewers   []string  `json:"reviewers"`
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requested_at"`
+	ApprovedBy  string    `json:"approved_by,omitempty"`
+	ApprovedAt  time.Time `json:"approved_at,omitempty"`
+	Signature   string    `json:"signature,omitempty"` // SSH signature of the approver over the ID and team
+}
+
+// approvalIDPattern matches the IDs ApprovalID returns
+var approvalIDPattern = regexp.MustCompile(`^[0-9a-f]{12}$`)
+
+// RequiresApproval reports whether the loaded team requires commits to be approved
+func (m *Manager) RequiresApproval() bool {
+	return m.config != nil && m.config.RequiresApproval
+}
+
+// ApprovalID derives a stable ID for a commit from its message and staged diff,
+// so an approval only covers exactly the change that was reviewed. The diff
+// must be the complete one, not a prompt's, which may leave files out.
+func ApprovalID(message string, diff io.Reader) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(strings.TrimSpace(message)))
+	hash.Write([]byte{0})
+	if _, err := io.Copy(hash, diff); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12], nil
+}
+
+// RequestApproval records a pending approval for the commit with the given
+// ID, or returns the existing record if this commit was already submitted.
+// With a team server, pending requests are shared through it so reviewers on
+// other machines see them.
+func (m *Manager) RequestApproval(id, message, author string) (*Approval, error) {
+	if m.config == nil {
+		return nil, fmt.Errorf("no team loaded")
+	}
+
+	approval, err := m.GetApproval(id)
+	if err != nil {
+		approval = &Approval{
+			ID:          id,
+			Team:        m.currentTeam,
+			Message:     strings.TrimSpace(message),
+			Author:      author,
+			Reviewers:   m.config.AdminUsers,
+			Status:      ApprovalPending,
+			RequestedAt: time.Now(),
+		}
+		if err := m.saveApproval(approval); err != nil {
+			return nil, err
+		}
+	}
+
+	// Shared every time, in case an earlier attempt failed
+	if approval.Status == ApprovalPending && m.sharesApprovals() {
+		if err := m.remoteJSON(http.MethodPut, approvalsPath(approval.Team, id), approval, nil); err != nil {
+			return nil, fmt.Errorf("failed to share approval request with the team server: %w", err)
+		}
+	}
+
+	return approval, nil
+}
+
+// GetApproval loads an approval record by ID. With a team server, records
+// that are missing or still pending locally are fetched from it.
+func (m *Manager) GetApproval(id string) (*Approval, error) {
+	if !approvalIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid approval ID: %s", id)
+	}
+
+	approval, err := m.readApproval(id)
+	if (err != nil || approval.Status == ApprovalPending) && m.sharesApprovals() && m.currentTeam != "" {
+		var shared Approval
+		remoteErr := m.remoteJSON(http.MethodGet, approvalsPath(m.currentTeam, id), nil, &shared)
+		switch {
+		case remoteErr == nil && shared.ID == id:
+			if err := m.saveApproval(&shared); err != nil {
+				return nil, err
+			}
+			return &shared, nil
+		case remoteErr != nil && err != nil && !errors.Is(remoteErr, errRemoteNotFound):
+			return nil, fmt.Errorf("failed to fetch approval request: %w", remoteErr)
+		}
+	}
+	return approval, err
+}
+
+// IsApproved reports whether the commit with the given approval ID has been
+// approved with a valid signature by an admin of the loaded team
+func (m *Manager) IsApproved(id string) bool {
+	approval, err := m.GetApproval(id)
+	return err == nil && approval.Status == ApprovalApproved && m.verifyApproval(approval) == nil
+}
+
+// Approve signs off on a pending approval with the SSH key at keyPath. The
+// key must be one of the team's admin_keys, and the approver is the admin it
+// belongs to, so approvals do not depend on anyone's git identity. Nobody
+// may approve their own commit.
+func (m *Manager) Approve(id, keyPath string) (*Approval, error) {
+	approval, err := m.GetApproval(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if approval.Status == ApprovalApproved && m.verifyApproval(approval) == nil {
+		return approval, nil
+	}
+	if approval.Team != m.currentTeam {
+		return nil, fmt.Errorf("approval %s is for team %s, not %s", id, approval.Team, m.currentTeam)
+	}
+
+	signed := *approval
+	if signed.Signature, err = signApproval(&signed, keyPath); err != nil {
+		return nil, err
+	}
+	if signed.ApprovedBy, err = m.approvalSigner(&signed); err != nil {
+		return nil, err
+	}
+	signed.Status = ApprovalApproved
+	signed.ApprovedAt = time.Now()
+	if err := m.verifyApproval(&signed); err != nil {
+		return nil, err
+	}
+
+	if err := m.saveApproval(&signed); err != nil {
+		return nil, err
+	}
+	if m.sharesApprovals() {
+		if err := m.remoteJSON(http.MethodPut, approvalsPath(signed.Team, id), &signed, nil); err != nil {
+			return nil, fmt.Errorf("failed to share approval with the team server: %w", err)
+		}
+	}
+
+	return &signed, nil
+}
+
+// ListApprovals returns approval records with the given status (all if empty),
+// oldest first. With a team server, they are those shared through it.
+func (m *Manager) ListApprovals(status string) ([]Approval, error) {
+	approvals := []Approval{}
+	if m.sharesApprovals() && m.currentTeam != "" {
+		path := approvalsPath(m.currentTeam, "") + "?status=" + url.QueryEscape(status)
+		if err := m.remoteJSON(http.MethodGet, path, nil, &approvals); err != nil {
+			return nil, fmt.Errorf("failed to fetch approval requests: %w", err)
+		}
+	} else {
+		entries, err := os.ReadDir(filepath.Join(m.configDir, "approvals"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read approvals directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+
+			approval, err := m.readApproval(strings.TrimSuffix(entry.Name(), ".json"))
+			if err != nil {
+				continue
+			}
+
+			if status == "" || approval.Status == status {
+				approvals = append(approvals, *approval)
+			}
+		}
+	}
+
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].RequestedAt.Before(approvals[j].RequestedAt)
+	})
+
+	return approvals, nil
+}
+
+// readApproval loads an approval record from disk
+func (m *Manager) readApproval(id string) (*Approval, error) {
+	data, err := os.ReadFile(m.approvalPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("approval request not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to read approval request: %w", err)
+	}
+
+	var approval Approval
+	if err := json.Unmarshal(data, &approval); err != nil {
+		return nil, fmt.Errorf("failed to parse approval request: %w", err)
+	}
+
+	return &approval, nil
+}
+
+// sharesApprovals reports whether approvals go through a team server
+func (m *Manager) sharesApprovals() bool {
+	return m.remote != nil && !m.offline
+}
+
+// approvalsPath returns the team server path of a team's approvals, or of
+// one of them
+func approvalsPath(team, id string) string {
+	path := "/teams/" + url.PathEscape(team) + "/approvals"
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	return path
+}
+
+// saveApproval writes an approval record to disk
+func (m *Manager) saveApproval(approval *Approval) error {
+	path := m.approvalPath(approval.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create approvals directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(approval, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal approval request: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write approval request: %w", err)
+	}
+
+	return nil
+}
+
+// approvalPath returns the file path for an approval record
+func (m *Manager) approvalPath(id string) string {
+	return filepath.Join(m.configDir, "approvals", id+".json")
+}
+
+// containsUser checks whether a user appears in a list, ignoring case
+func containsUser(users []string, user string) bool {
+	for _, u := range users {
+		if strings.EqualFold(u, user) {
+			return true
+		}
+	}
+	return false
+}
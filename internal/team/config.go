@@ -9,7 +9,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/jasonKoogler/comma/internal/conventional"
 )
 
 // TeamConfig represents shared team configuration
@@ -22,6 +25,18 @@ type TeamConfig struct {
 	AllowedProviders []string            `json:"allowed_providers"`
 	RequiresApproval bool                `json:"requires_approval"`
 	AdminUsers       []string            `json:"admin_users"`
+	AdminKeys        map[string]string   `json:"admin_keys,omitempty"` // SSH public keys by admin, which approvals must be signed with
+	IssueLinking     *IssueLinking       `json:"issue_linking,omitempty"`
+	SystemPrompt     string              `json:"system_prompt,omitempty"` // Replaces the user's llm.system_prompt
+	CommitTypes      []string            `json:"commit_types,omitempty"`  // Replaces the user's conventional.types
+}
+
+// IssueLinking sets how a team links commits to the issues they close,
+// overriding the user's issues.* settings
+type IssueLinking struct {
+	Enabled bool   `json:"enabled"`           // Link issues named in the branch
+	Tracker string `json:"tracker,omitempty"` // "github" or "linear"
+	Keyword string `json:"keyword,omitempty"` // e.g. "Closes" or "Fixes"
 }
 
 // Template represents a commit message template
@@ -48,6 +63,8 @@ type Manager struct {
 	configDir   string
 	currentTeam string
 	config      *TeamConfig
+	remote      *RemoteSource
+	offline     bool
 }
 
 // NewManager creates a team configuration manager
@@ -74,6 +91,14 @@ func (m *Manager) LoadTeam(teamName string) error {
 		}
 	}
 
+	// Refresh from the team server if one is configured, falling back to
+	// the cached copy when the server is unreachable
+	if m.remote != nil && !m.offline {
+		if err := m.SyncTeam(teamName); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to sync team configuration, using cached copy: %v\n", err)
+		}
+	}
+
 	configPath := filepath.Join(m.configDir, fmt.Sprintf("%s.json", teamName))
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return fmt.Errorf("team configuration not found: %s", teamName)
@@ -95,6 +120,16 @@ func (m *Manager) LoadTeam(teamName string) error {
 	return nil
 }
 
+// Config returns the currently loaded team configuration, or nil if no team is loaded
+func (m *Manager) Config() *TeamConfig {
+	return m.config
+}
+
+// CurrentTeam returns the name of the currently loaded team
+func (m *Manager) CurrentTeam() string {
+	return m.currentTeam
+}
+
 // GetTemplate gets a template by name
 func (m *Manager) GetTemplate(name string) (string, error) {
 	if m.config == nil {
@@ -113,6 +148,20 @@ func (m *Manager) GetTemplate(name string) (string, error) {
 	return template.Content, nil
 }
 
+// TemplateNames returns the names of the loaded team's templates, sorted
+func (m *Manager) TemplateNames() []string {
+	if m.config == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(m.config.Templates))
+	for name := range m.config.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ValidateCommitMessage checks if a message follows team conventions
 func (m *Manager) ValidateCommitMessage(message string) (bool, []string) {
 	if m.config == nil {
@@ -135,9 +184,46 @@ func (m *Manager) ValidateCommitMessage(message string) (bool, []string) {
 		}
 	}
 
+	// Messages that aren't conventional at all are left to the checks above
+	if parsed, err := conventional.Parse(message); err == nil && len(m.config.CommitTypes) > 0 {
+		known := false
+		for _, t := range m.config.CommitTypes {
+			known = known || strings.EqualFold(t, parsed.Type)
+		}
+		if !known {
+			errors = append(errors, fmt.Sprintf("Commit type %q is not one of the team's types: %s",
+				parsed.Type, strings.Join(m.config.CommitTypes, ", ")))
+			valid = false
+		}
+	}
+
 	return valid, errors
 }
 
+// CheckProvider returns an error if the loaded team does not allow the given
+// LLM provider. Any provider is allowed when no team is loaded or the team
+// does not restrict providers.
+func (m *Manager) CheckProvider(provider string) error {
+	if m.config == nil || len(m.config.AllowedProviders) == 0 {
+		return nil
+	}
+
+	for _, allowed := range m.config.AllowedProviders {
+		if strings.EqualFold(allowed, provider) {
+			return nil
+		}
+	}
+
+	admins := "a team admin"
+	if len(m.config.AdminUsers) > 0 {
+		admins = strings.Join(m.config.AdminUsers, ", ")
+	}
+
+	return fmt.Errorf("provider %q is not allowed by team %s policy (allowed: %s). "+
+		"To use it, ask %s to add it to allowed_providers in the team configuration",
+		provider, m.currentTeam, strings.Join(m.config.AllowedProviders, ", "), admins)
+}
+
 // detectTeamFromGit tries to determine team from git config or remote URL
 func (m *Manager) detectTeamFromGit() (string, error) {
 	// Try to get organization from remote URL
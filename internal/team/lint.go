@@ -0,0 +1,95 @@
+// internal/team/lint.go
+package team
+
+import (
+	"sort"
+	"strings"
+)
+
+// LintCommit is a historical commit to check against team conventions
+type LintCommit struct {
+	Hash    string
+	Author  string
+	Message string
+}
+
+// LintFailure is a commit that failed one or more convention checks
+type LintFailure struct {
+	Hash     string   `json:"hash"`
+	Subject  string   `json:"subject"`
+	Problems []string `json:"problems"`
+}
+
+// AuthorCompliance summarizes how well one author's commits follow the conventions
+type AuthorCompliance struct {
+	Author    string        `json:"author"`
+	Commits   int           `json:"commits"`
+	Compliant int           `json:"compliant"`
+	Failures  []LintFailure `json:"failures,omitempty"`
+}
+
+// Percent returns the share of the author's commits that passed every check
+func (a AuthorCompliance) Percent() float64 {
+	if a.Commits == 0 {
+		return 100
+	}
+	return float64(a.Compliant) / float64(a.Commits) * 100
+}
+
+// LintReport is the result of checking a range of commits against team conventions
+type LintReport struct {
+	Team      string             `json:"team"`
+	Commits   int                `json:"commits"`
+	Compliant int                `json:"compliant"`
+	Authors   []AuthorCompliance `json:"authors"` // Least compliant first
+}
+
+// Percent returns the share of commits that passed every check
+func (r *LintReport) Percent() float64 {
+	if r.Commits == 0 {
+		return 100
+	}
+	return float64(r.Compliant) / float64(r.Commits) * 100
+}
+
+// LintCommits runs the loaded team's convention checks over historical
+// commits and groups the results by author
+func (m *Manager) LintCommits(commits []LintCommit) *LintReport {
+	report := &LintReport{Team: m.currentTeam}
+	byAuthor := make(map[string]*AuthorCompliance)
+
+	for _, commit := range commits {
+		author := byAuthor[commit.Author]
+		if author == nil {
+			author = &AuthorCompliance{Author: commit.Author}
+			byAuthor[commit.Author] = author
+		}
+
+		report.Commits++
+		author.Commits++
+
+		if valid, problems := m.ValidateCommitMessage(commit.Message); valid {
+			report.Compliant++
+			author.Compliant++
+		} else {
+			author.Failures = append(author.Failures, LintFailure{
+				Hash:     commit.Hash,
+				Subject:  strings.SplitN(commit.Message, "\n", 2)[0],
+				Problems: problems,
+			})
+		}
+	}
+
+	for _, author := range byAuthor {
+		report.Authors = append(report.Authors, *author)
+	}
+	sort.Slice(report.Authors, func(i, j int) bool {
+		pi, pj := report.Authors[i].Percent(), report.Authors[j].Percent()
+		if pi != pj {
+			return pi < pj
+		}
+		return report.Authors[i].Author < report.Authors[j].Author
+	})
+
+	return report
+}
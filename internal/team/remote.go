@@ -0,0 +1,213 @@
+// internal/team/remote.go
+package team
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/logging"
+)
+
+// TeamServerCredential is the credential store key for the team server token
+const TeamServerCredential = "team-server"
+
+// RemoteSource fetches team configurations from a central HTTPS server
+type RemoteSource struct {
+	baseURL string
+	token   func() string
+	client  *http.Client
+}
+
+// NewRemoteSource creates a client for a team configuration server.
+// Configurations are fetched from <baseURL>/teams/<name>. The token is
+// looked up only when a request is made, since credential backends may
+// themselves need the network.
+func NewRemoteSource(baseURL string, token func() string) (*RemoteSource, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid team server URL: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("team server URL must use https: %s", baseURL)
+	}
+
+	return &RemoteSource{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// ConfigureRemote makes LoadTeam fetch configurations from a team server,
+// keeping the local copy as an offline cache
+func (m *Manager) ConfigureRemote(baseURL string, token func() string) error {
+	remote, err := NewRemoteSource(baseURL, token)
+	if err != nil {
+		return err
+	}
+	m.remote = remote
+	return nil
+}
+
+// SetOffline stops the manager from contacting the team server;
+// LoadTeam then uses the cached configuration only
+func (m *Manager) SetOffline(offline bool) {
+	m.offline = offline
+}
+
+// SyncTeam fetches the named team configuration from the team server and
+// updates the local cache. The request is revalidated with the cached ETag,
+// so an unchanged configuration is not downloaded again.
+func (m *Manager) SyncTeam(teamName string) error {
+	if m.remote == nil {
+		return fmt.Errorf("no team server configured")
+	}
+	if m.offline {
+		return fmt.Errorf("cannot sync team configuration: %w", apperrors.ErrOffline)
+	}
+
+	configPath := filepath.Join(m.configDir, fmt.Sprintf("%s.json", teamName))
+	etagPath := filepath.Join(m.configDir, ".etags", teamName)
+
+	req, err := m.remote.newRequest(http.MethodGet, "/teams/"+url.PathEscape(teamName), nil)
+	if err != nil {
+		return err
+	}
+
+	// Only revalidate if we still have the cached body the ETag refers to
+	if _, err := os.Stat(configPath); err == nil {
+		if etag, err := os.ReadFile(etagPath); err == nil && len(etag) > 0 {
+			req.Header.Set("If-None-Match", strings.TrimSpace(string(etag)))
+		}
+	}
+
+	resp, err := m.remote.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach team server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("team server rejected credentials (status %d)", resp.StatusCode)
+	case http.StatusNotFound:
+		return fmt.Errorf("team configuration not found on server: %s", teamName)
+	default:
+		return fmt.Errorf("unexpected status code from team server: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read team server response: %w", err)
+	}
+
+	var config TeamConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse team config from server: %w", err)
+	}
+
+	if err := m.SaveTeam(teamName, &config); err != nil {
+		return err
+	}
+
+	// Remember the ETag for the next revalidation
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		if err := os.MkdirAll(filepath.Dir(etagPath), 0755); err != nil {
+			return fmt.Errorf("failed to create etag directory: %w", err)
+		}
+		if err := os.WriteFile(etagPath, []byte(etag), 0644); err != nil {
+			return fmt.Errorf("failed to write etag: %w", err)
+		}
+	} else {
+		os.Remove(etagPath)
+	}
+
+	return nil
+}
+
+// errRemoteNotFound is returned by remoteJSON when the team server has no
+// such resource
+var errRemoteNotFound = errors.New("not found on team server")
+
+// newRequest creates a request to the team server with its credentials
+func (r *RemoteSource) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, r.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "comma-git-client")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if id := logging.TraceID(); id != "" {
+		req.Header.Set(logging.TraceHeader, id)
+	}
+	if r.token != nil {
+		if token := r.token(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return req, nil
+}
+
+// remoteJSON sends a request with an optional JSON body to the team server
+// and decodes its JSON answer into out, if given
+func (m *Manager) remoteJSON(method, path string, body, out interface{}) error {
+	if m.remote == nil {
+		return fmt.Errorf("no team server configured")
+	}
+	if m.offline {
+		return apperrors.ErrOffline
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := m.remote.newRequest(method, path, reader)
+	if err != nil {
+		return err
+	}
+	resp, err := m.remote.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach team server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errRemoteNotFound
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("team server rejected credentials (status %d)", resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("unexpected status code from team server: %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse team server response: %w", err)
+	}
+	return nil
+}
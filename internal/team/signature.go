@@ -0,0 +1,139 @@
+// internal/team/signature.go
+package team
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// approvalNamespace keeps approval signatures from being valid for anything
+// else, such as signed commits, and the reverse
+const approvalNamespace = "comma-approval"
+
+// approvalPayload is the data an admin signs to approve a commit. The ID
+// covers the message and the full staged diff.
+func approvalPayload(approval *Approval) []byte {
+	return []byte(fmt.Sprintf("comma approval %s for team %s\n", approval.ID, approval.Team))
+}
+
+// SigningKey returns the SSH key approvals are signed with by default: git's
+// user.signingkey when it is a file, else ~/.ssh/id_ed25519
+func SigningKey() string {
+	if out, err := exec.Command("git", "config", "--get", "user.signingkey").Output(); err == nil {
+		if key := strings.TrimSpace(string(out)); key != "" && !strings.HasPrefix(key, "key::") {
+			return expandHome(key)
+		}
+	}
+	return expandHome("~/.ssh/id_ed25519")
+}
+
+// expandHome replaces a leading ~/ with the home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// signApproval signs an approval's payload with the SSH key at keyPath,
+// which may also be a public key whose private half is in the SSH agent
+func signApproval(approval *Approval, keyPath string) (string, error) {
+	cmd := exec.Command("ssh-keygen", "-Y", "sign", "-f", keyPath, "-n", approvalNamespace)
+	cmd.Stdin = bytes.NewReader(approvalPayload(approval))
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to sign approval with %s: %s", keyPath, strings.TrimSpace(stderr.String()))
+	}
+	return out.String(), nil
+}
+
+// approvalSigner returns the admin whose key made the approval's signature
+func (m *Manager) approvalSigner(approval *Approval) (string, error) {
+	signers, sigPath, cleanup, err := m.signatureFiles(approval)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	out, err := exec.Command("ssh-keygen", "-Y", "find-principals", "-s", sigPath, "-f", signers).Output()
+	if err != nil {
+		return "", fmt.Errorf("the signing key is not one of team %s's admin_keys", approval.Team)
+	}
+	return strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0]), nil
+}
+
+// verifyApproval checks that an approved record carries a valid signature by
+// an admin of the loaded team other than the commit's author
+func (m *Manager) verifyApproval(approval *Approval) error {
+	if approval.Signature == "" {
+		return fmt.Errorf("approval %s is not signed", approval.ID)
+	}
+	if m.config == nil || approval.Team != m.currentTeam {
+		return fmt.Errorf("approval %s is for team %s, which is not loaded", approval.ID, approval.Team)
+	}
+	if len(m.config.AdminUsers) > 0 && !containsUser(m.config.AdminUsers, approval.ApprovedBy) {
+		return fmt.Errorf("%s is not an admin of team %s", approval.ApprovedBy, approval.Team)
+	}
+	if approval.Author != "" && strings.EqualFold(approval.Author, approval.ApprovedBy) {
+		return fmt.Errorf("commits cannot be approved by their author")
+	}
+
+	signers, sigPath, cleanup, err := m.signatureFiles(approval)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cmd := exec.Command("ssh-keygen", "-Y", "verify", "-f", signers, "-I", approval.ApprovedBy,
+		"-n", approvalNamespace, "-s", sigPath)
+	cmd.Stdin = bytes.NewReader(approvalPayload(approval))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("invalid signature on approval %s: %s", approval.ID, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// signatureFiles writes the team's admin keys in ssh-keygen's allowed
+// signers format and the approval's signature to temporary files
+func (m *Manager) signatureFiles(approval *Approval) (signers, sigPath string, cleanup func(), err error) {
+	if m.config == nil || len(m.config.AdminKeys) == 0 {
+		return "", "", nil, fmt.Errorf("team %s has no admin_keys to check approvals against", approval.Team)
+	}
+
+	dir, err := os.MkdirTemp("", "comma-approval-")
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	users := make([]string, 0, len(m.config.AdminKeys))
+	for user := range m.config.AdminKeys {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	var lines strings.Builder
+	for _, user := range users {
+		fmt.Fprintf(&lines, "%s namespaces=\"%s\" %s\n", user, approvalNamespace, strings.TrimSpace(m.config.AdminKeys[user]))
+	}
+
+	signers = filepath.Join(dir, "allowed_signers")
+	sigPath = filepath.Join(dir, "approval.sig")
+	if err := os.WriteFile(signers, []byte(lines.String()), 0600); err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("failed to write allowed signers: %w", err)
+	}
+	if err := os.WriteFile(sigPath, []byte(approval.Signature), 0600); err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("failed to write signature: %w", err)
+	}
+	return signers, sigPath, cleanup, nil
+}
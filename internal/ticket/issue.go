@@ -0,0 +1,80 @@
+// internal/ticket/issue.go
+package ticket
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Issue trackers for issues.tracker
+const (
+	TrackerGitHub = "github" // References like #42
+	TrackerLinear = "linear" // References like ENG-123
+)
+
+// DefaultIssueKeyword starts the footer that closes an issue
+const DefaultIssueKeyword = "Closes"
+
+// Issue references at the start of a branch name segment, e.g.
+// "42-fix-login", "fix/gh-42" or "jane/eng-123-login"
+var (
+	githubBranchIssue = regexp.MustCompile(`(?i)(?:^|/)(?:issue-|gh-)?(\d+)(?:[-_]|$)`)
+	linearBranchIssue = regexp.MustCompile(`(?i)(?:^|/)([a-z][a-z0-9]*-\d+)(?:[-_]|$)`)
+	plainIssueNumber  = regexp.MustCompile(`^#?\d+$`)
+)
+
+// Issues adds "Closes #42"-style footers for the issues a commit resolves
+type Issues struct {
+	Tracker string // TrackerGitHub or TrackerLinear
+	Keyword string // Empty for DefaultIssueKeyword
+}
+
+// FromBranch returns the issue a branch name refers to, or ""
+func (i *Issues) FromBranch(branch string) string {
+	if i.Tracker == TrackerLinear {
+		if m := linearBranchIssue.FindStringSubmatch(branch); m != nil {
+			return strings.ToUpper(m[1])
+		}
+		return ""
+	}
+
+	if m := githubBranchIssue.FindStringSubmatch(branch); m != nil {
+		return "#" + m[1]
+	}
+	return ""
+}
+
+// Normalize turns a reference given by the user into the form the tracker
+// recognizes: "42" becomes "#42"; "eng-123" becomes "ENG-123"
+func (i *Issues) Normalize(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if plainIssueNumber.MatchString(ref) {
+		return "#" + strings.TrimPrefix(ref, "#")
+	}
+	if i.Tracker == TrackerLinear {
+		return strings.ToUpper(ref)
+	}
+	return ref
+}
+
+// Apply adds a footer for each issue the message doesn't reference yet
+func (i *Issues) Apply(message string, refs []string) string {
+	keyword := i.Keyword
+	if keyword == "" {
+		keyword = DefaultIssueKeyword
+	}
+
+	for _, ref := range refs {
+		if ref == "" || referencesIssue(message, ref) {
+			continue
+		}
+		message = appendTrailer(message, keyword+" "+ref)
+	}
+	return message
+}
+
+// referencesIssue reports whether a message already mentions an issue, without
+// mistaking #4 for a reference to #42
+func referencesIssue(message, ref string) bool {
+	return regexp.MustCompile(regexp.QuoteMeta(ref) + `\b`).MatchString(message)
+}
@@ -0,0 +1,155 @@
+// internal/ticket/jira.go
+package ticket
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Ticket placements for jira.placement
+const (
+	PlacementFooter = "footer" // A footer line such as "Refs: ABC-123"
+	PlacementPrefix = "prefix" // Before the subject, after any conventional type
+)
+
+// Default formats per placement. {ticket} is replaced by the ticket key and
+// {url} by its link when jira.url is set.
+const (
+	DefaultFooterFormat = "Refs: {ticket}"
+	DefaultPrefixFormat = "{ticket} "
+)
+
+// jiraKey matches Jira issue keys: an uppercase project key, a dash and a number
+var jiraKey = regexp.MustCompile(`\b([A-Z][A-Z0-9_]+-[1-9][0-9]*)\b`)
+
+// conventionalHeader matches "type(scope)!: " at the start of a subject
+var conventionalHeader = regexp.MustCompile(`^\w+(\([^)]*\))?!?: `)
+
+// trailerLine matches a git trailer such as "Refs: ABC-123" or
+// "Signed-off-by: ...", or an issue footer such as "Closes #42"
+var trailerLine = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*: |[A-Z][a-z]+ (#\d+|[A-Z][A-Z0-9]*-\d+)$)`)
+
+// Jira inserts Jira ticket keys into commit messages
+type Jira struct {
+	Placement string   // PlacementFooter or PlacementPrefix
+	Format    string   // Empty for the placement's default format
+	URL       string   // Jira base URL, e.g. https://acme.atlassian.net
+	Projects  []string // Project keys to accept; empty accepts any
+}
+
+// ValidateURL checks that a Jira base URL is an https URL with a host, such
+// as https://acme.atlassian.net
+func ValidateURL(jiraURL string) error {
+	parsed, err := url.Parse(jiraURL)
+	if err != nil {
+		return fmt.Errorf("invalid Jira URL: %w", err)
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("Jira URL must be an https URL such as https://acme.atlassian.net: %s", jiraURL)
+	}
+	return nil
+}
+
+// Find returns the first ticket key in the branch name or, failing that, in
+// the recent commit messages. Keys of projects not in Projects are skipped.
+func (j *Jira) Find(branch string, recentCommits []string) string {
+	// Branch names are often lowercased, e.g. feature/abc-123-login
+	if key := j.match(strings.ToUpper(branch)); key != "" {
+		return key
+	}
+	for _, message := range recentCommits {
+		if key := j.match(message); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// match returns the first accepted ticket key in text
+func (j *Jira) match(text string) string {
+	for _, key := range jiraKey.FindAllString(text, -1) {
+		if j.accepts(key) {
+			return key
+		}
+	}
+	return ""
+}
+
+// accepts reports whether a key belongs to one of the configured projects
+func (j *Jira) accepts(key string) bool {
+	if len(j.Projects) == 0 {
+		return true
+	}
+	project := key[:strings.LastIndex(key, "-")]
+	for _, p := range j.Projects {
+		if strings.EqualFold(strings.TrimSpace(p), project) {
+			return true
+		}
+	}
+	return false
+}
+
+// Link returns the browse URL of a ticket, or "" without a Jira URL
+func (j *Jira) Link(key string) string {
+	if j.URL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(j.URL, "/") + "/browse/" + key
+}
+
+// Apply inserts the ticket into a message. Messages that already mention
+// the ticket are returned unchanged.
+func (j *Jira) Apply(message, key string) string {
+	if key == "" || strings.Contains(message, key) {
+		return message
+	}
+
+	format := j.Format
+	if format == "" {
+		format = DefaultFooterFormat
+		if j.Placement == PlacementPrefix {
+			format = DefaultPrefixFormat
+		}
+	}
+	text := strings.NewReplacer("{ticket}", key, "{url}", j.Link(key)).Replace(format)
+
+	if j.Placement == PlacementPrefix {
+		return InsertPrefix(message, text)
+	}
+
+	return appendTrailer(message, strings.TrimSpace(text))
+}
+
+// InsertPrefix puts text at the start of a message's subject, after any
+// conventional "type(scope): "
+func InsertPrefix(message, text string) string {
+	subject, rest, _ := strings.Cut(message, "\n")
+	header := conventionalHeader.FindString(subject)
+	subject = header + text + strings.TrimPrefix(subject, header)
+	if rest == "" {
+		return subject
+	}
+	return subject + "\n" + rest
+}
+
+// appendTrailer adds a footer line, joining an existing trailer block
+func appendTrailer(message, line string) string {
+	message = strings.TrimRight(message, "\n")
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+
+	isTrailers := len(paragraphs) > 1
+	for _, l := range strings.Split(last, "\n") {
+		if !trailerLine.MatchString(l) {
+			isTrailers = false
+			break
+		}
+	}
+
+	if isTrailers {
+		return message + "\n" + line
+	}
+	return message + "\n\n" + line
+}
@@ -0,0 +1,111 @@
+// internal/ui/chart.go
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sparkBlocks are the bar heights used by Sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a single line of block characters. When there
+// are more values than width, neighbouring values are summed into buckets.
+func Sparkline(values []int, width int) string {
+	values = bucket(values, width)
+
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		if max == 0 || v == 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		level := (v*len(sparkBlocks) - 1) / max
+		sb.WriteRune(sparkBlocks[level])
+	}
+	return sb.String()
+}
+
+// BarChart renders labelled horizontal bars scaled to width
+func BarChart(labels []string, values []int, width int) string {
+	max, labelWidth := 0, 0
+	for i, v := range values {
+		if v > max {
+			max = v
+		}
+		if len(labels[i]) > labelWidth {
+			labelWidth = len(labels[i])
+		}
+	}
+
+	var sb strings.Builder
+	for i, v := range values {
+		length := 0
+		if max > 0 {
+			length = v * width / max
+		}
+		fmt.Fprintf(&sb, "%-*s %s %d\n", labelWidth, labels[i], strings.Repeat("█", length), v)
+	}
+	return sb.String()
+}
+
+// bucket sums consecutive values so that at most width values remain
+func bucket(values []int, width int) []int {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+
+	size := (len(values) + width - 1) / width
+	buckets := make([]int, 0, width)
+	for i := 0; i < len(values); i += size {
+		sum := 0
+		for j := i; j < i+size && j < len(values); j++ {
+			sum += values[j]
+		}
+		buckets = append(buckets, sum)
+	}
+	return buckets
+}
+
+// heatShades are the cell shades used by Heatmap, lightest first
+var heatShades = []rune(" ░▒▓█")
+
+// Heatmap renders a grid of counts as shaded cells, one row per label,
+// with an hour ruler across the top for 24-column grids
+func Heatmap(labels []string, grid [][]int) string {
+	max, labelWidth := 0, 0
+	for i, row := range grid {
+		for _, v := range row {
+			if v > max {
+				max = v
+			}
+		}
+		if len(labels[i]) > labelWidth {
+			labelWidth = len(labels[i])
+		}
+	}
+
+	var sb strings.Builder
+	if len(grid) > 0 && len(grid[0]) == 24 {
+		fmt.Fprintf(&sb, "%-*s 0     6     12    18    23\n", labelWidth, "")
+	}
+	for i, row := range grid {
+		fmt.Fprintf(&sb, "%-*s ", labelWidth, labels[i])
+		for _, v := range row {
+			level := 0
+			if max > 0 && v > 0 {
+				level = 1 + (v*(len(heatShades)-1)-1)/max
+			}
+			sb.WriteRune(heatShades[level])
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
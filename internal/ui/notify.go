@@ -0,0 +1,121 @@
+// internal/ui/notify.go
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// NoticeLevel says how a notice is shown
+type NoticeLevel int
+
+const (
+	NoticeInfo    NoticeLevel = iota // Something happened the user may want to know about
+	NoticeSuccess                    // Something was saved or set up
+	NoticeWarning                    // Something fell back or looks wrong, but the command goes on
+)
+
+// NoticeLog receives every notice, so they end up in the log file even
+// when they are not shown
+type NoticeLog interface {
+	Info(format string, v ...interface{})
+}
+
+// notices holds the state of the notice line. Notices are one-line messages
+// on stderr about things that happen on the side of a command, such as a
+// cache hit or a provider fallback. While a spinner runs they are held back
+// and shown when it stops, so they do not garble it.
+var notices = struct {
+	mu     sync.Mutex
+	out    io.Writer
+	log    NoticeLog
+	held   int
+	queued []string
+	seen   map[string]bool
+}{out: os.Stderr, seen: make(map[string]bool)}
+
+// SetNoticeOutput sets where notices are shown; nil hides them, for quiet
+// runs
+func SetNoticeOutput(w io.Writer) {
+	notices.mu.Lock()
+	defer notices.mu.Unlock()
+
+	notices.out = w
+}
+
+// SetNoticeLog sets the log notices are also written to
+func SetNoticeLog(log NoticeLog) {
+	notices.mu.Lock()
+	defer notices.mu.Unlock()
+
+	notices.log = log
+}
+
+// Notify shows a notice. The same notice is shown once per run.
+func Notify(level NoticeLevel, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+
+	notices.mu.Lock()
+	defer notices.mu.Unlock()
+
+	if notices.seen[message] {
+		return
+	}
+	notices.seen[message] = true
+	if notices.log != nil {
+		notices.log.Info("Notice: %s", message)
+	}
+	if notices.out == nil {
+		return
+	}
+
+	line := noticeGlyph(level) + " " + message + "\n"
+	if notices.held > 0 {
+		notices.queued = append(notices.queued, line)
+		return
+	}
+	fmt.Fprint(notices.out, line)
+}
+
+// noticeGlyph returns the marker shown before a notice
+func noticeGlyph(level NoticeLevel) string {
+	switch level {
+	case NoticeSuccess:
+		return color.New(color.FgGreen, color.Bold).Sprint("✓")
+	case NoticeWarning:
+		return color.New(color.FgYellow, color.Bold).Sprint("!")
+	default:
+		return color.New(color.FgCyan, color.Bold).Sprint("•")
+	}
+}
+
+// holdNotices queues notices until releaseNotices is called
+func holdNotices() {
+	notices.mu.Lock()
+	defer notices.mu.Unlock()
+
+	notices.held++
+}
+
+// releaseNotices shows the notices queued since holdNotices
+func releaseNotices() {
+	notices.mu.Lock()
+	defer notices.mu.Unlock()
+
+	if notices.held > 0 {
+		notices.held--
+	}
+	if notices.held > 0 {
+		return
+	}
+	if notices.out != nil {
+		for _, line := range notices.queued {
+			fmt.Fprint(notices.out, line)
+		}
+	}
+	notices.queued = nil
+}
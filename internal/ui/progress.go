@@ -28,6 +28,7 @@ type SpinnerProgress struct {
 	spinner *spinner.Spinner
 	mu      sync.Mutex
 	writer  io.Writer
+	holding bool // Notices are held back while the spinner runs
 }
 
 // NewSpinnerProgress creates a new spinner progress indicator
@@ -48,6 +49,10 @@ func (p *SpinnerProgress) Start(message string) {
 
 	p.spinner.Suffix = " " + message
 	p.spinner.Start()
+	if !p.holding {
+		holdNotices()
+		p.holding = true
+	}
 }
 
 // Update changes the message shown with the spinner
@@ -66,6 +71,7 @@ func (p *SpinnerProgress) Success(message string) {
 	p.spinner.Stop()
 	successColor := color.New(color.FgGreen, color.Bold)
 	fmt.Fprintf(p.writer, "%s %s\n", successColor.Sprint("✓"), message)
+	p.releaseNotices()
 }
 
 // Failure stops the spinner and shows a failure message
@@ -76,6 +82,7 @@ func (p *SpinnerProgress) Failure(message string) {
 	p.spinner.Stop()
 	failureColor := color.New(color.FgRed, color.Bold)
 	fmt.Fprintf(p.writer, "%s %s\n", failureColor.Sprint("✗"), message)
+	p.releaseNotices()
 }
 
 // Warning stops the spinner and shows a warning message
@@ -86,6 +93,7 @@ func (p *SpinnerProgress) Warning(message string) {
 	p.spinner.Stop()
 	warningColor := color.New(color.FgYellow, color.Bold)
 	fmt.Fprintf(p.writer, "%s %s\n", warningColor.Sprint("!"), message)
+	p.releaseNotices()
 }
 
 // Stop halts the spinner
@@ -94,6 +102,15 @@ func (p *SpinnerProgress) Stop() {
 	defer p.mu.Unlock()
 
 	p.spinner.Stop()
+	p.releaseNotices()
+}
+
+// releaseNotices shows the notices held back while the spinner ran
+func (p *SpinnerProgress) releaseNotices() {
+	if p.holding {
+		p.holding = false
+		releaseNotices()
+	}
 }
 
 // SimpleProgress implements a simple text-based progress indicator
@@ -157,6 +174,28 @@ func (p *SimpleProgress) Stop() {
 	// No action needed for simple progress
 }
 
+// NullProgress implements ProgressIndicator without any output, for quiet
+// and machine-readable runs
+type NullProgress struct{}
+
+// Start does nothing
+func (p NullProgress) Start(message string) {}
+
+// Update does nothing
+func (p NullProgress) Update(message string) {}
+
+// Success does nothing
+func (p NullProgress) Success(message string) {}
+
+// Failure does nothing
+func (p NullProgress) Failure(message string) {}
+
+// Warning does nothing
+func (p NullProgress) Warning(message string) {}
+
+// Stop does nothing
+func (p NullProgress) Stop() {}
+
 // CreateProgress creates the appropriate progress indicator based on terminal capabilities
 func CreateProgress(interactive bool) ProgressIndicator {
 	// In interactive terminals, use the spinner
@@ -0,0 +1,120 @@
+// internal/ui/tree.go
+package ui
+
+import (
+	"sort"
+	"strings"
+)
+
+// TreeNode is a folder or a file in a tree of paths
+type TreeNode struct {
+	Name      string      // Relative to the parent; a folder whose only entry is a folder is merged with it, e.g. "internal/config"
+	Index     int         // The file's position in the paths given to NewFileTree, or -1 for a folder
+	Files     int         // Files under a folder, at any depth
+	Collapsed bool        // Walk skips the folder's entries
+	Children  []*TreeNode // Folders first, then files, each sorted by name
+}
+
+// NewFileTree arranges slash-separated paths into folders and returns the
+// root folder
+func NewFileTree(paths []string) *TreeNode {
+	root := &TreeNode{Index: -1}
+	for i, path := range paths {
+		// A path ending in a slash, such as an untracked directory, is
+		// an entry of its own
+		parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
+		name := parts[len(parts)-1]
+		if strings.HasSuffix(path, "/") {
+			name += "/"
+		}
+
+		node := root
+		for _, dir := range parts[:len(parts)-1] {
+			node = node.folder(dir)
+		}
+		node.Children = append(node.Children, &TreeNode{Name: name, Index: i})
+	}
+	root.finish()
+	return root
+}
+
+// IsFolder reports whether the node is a folder
+func (n *TreeNode) IsFolder() bool {
+	return n.Index < 0
+}
+
+// Walk calls fn for every entry under the folder in display order, with its
+// depth below it. Entries of collapsed folders are skipped.
+func (n *TreeNode) Walk(fn func(node *TreeNode, depth int)) {
+	n.walk(fn, 0)
+}
+
+// CollapseAbove collapses every folder under this one that holds more than
+// limit files, so large folders take one line until they are expanded
+func (n *TreeNode) CollapseAbove(limit int) {
+	for _, child := range n.Children {
+		if child.IsFolder() {
+			child.Collapsed = child.Files > limit
+			child.CollapseAbove(limit)
+		}
+	}
+}
+
+// FileIndexes returns the indexes of the file, or of every file under the
+// folder
+func (n *TreeNode) FileIndexes() []int {
+	if !n.IsFolder() {
+		return []int{n.Index}
+	}
+	var indexes []int
+	for _, child := range n.Children {
+		indexes = append(indexes, child.FileIndexes()...)
+	}
+	return indexes
+}
+
+func (n *TreeNode) walk(fn func(node *TreeNode, depth int), depth int) {
+	for _, child := range n.Children {
+		fn(child, depth)
+		if child.IsFolder() && !child.Collapsed {
+			child.walk(fn, depth+1)
+		}
+	}
+}
+
+// folder returns the subfolder with the given name, adding it if needed
+func (n *TreeNode) folder(name string) *TreeNode {
+	for _, child := range n.Children {
+		if child.IsFolder() && child.Name == name {
+			return child
+		}
+	}
+	child := &TreeNode{Name: name, Index: -1}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// finish merges chains of single folders, counts files and sorts entries
+func (n *TreeNode) finish() {
+	for _, child := range n.Children {
+		if !child.IsFolder() {
+			n.Files++
+			continue
+		}
+		for len(child.Children) == 1 && child.Children[0].IsFolder() {
+			only := child.Children[0]
+			child.Name += "/" + only.Name
+			child.Children = only.Children
+		}
+		child.finish()
+		n.Files += child.Files
+	}
+
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		a, b := n.Children[i], n.Children[j]
+		if a.IsFolder() != b.IsFolder() {
+			return a.IsFolder()
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,93 @@
+// internal/update/backup.go
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Backup records the binary that an update replaced, so it can be restored
+type Backup struct {
+	Path       string    `json:"path"`       // Where the previous binary was kept
+	Version    string    `json:"version"`    // Version of the previous binary
+	Executable string    `json:"executable"` // The binary the backup belongs to
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// backupRecordPath returns where the backup record is stored
+func backupRecordPath(configDir string) string {
+	return filepath.Join(configDir, "update_backup.json")
+}
+
+// SaveBackup records the binary kept by the last update
+func SaveBackup(configDir string, backup *Backup) error {
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup record: %w", err)
+	}
+
+	if err := os.WriteFile(backupRecordPath(configDir), data, 0644); err != nil {
+		return fmt.Errorf("failed to write backup record: %w", err)
+	}
+	return nil
+}
+
+// LoadBackup returns the binary kept by the last update, checking it still exists
+func LoadBackup(configDir string) (*Backup, error) {
+	data, err := os.ReadFile(backupRecordPath(configDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no previous version to roll back to")
+		}
+		return nil, fmt.Errorf("failed to read backup record: %w", err)
+	}
+
+	var backup Backup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("failed to parse backup record: %w", err)
+	}
+
+	if _, err := os.Stat(backup.Path); err != nil {
+		return nil, fmt.Errorf("previous version v%s is no longer at %s", backup.Version, backup.Path)
+	}
+
+	return &backup, nil
+}
+
+// Rollback swaps the current binary with its backup. The replaced binary
+// becomes the new backup, so a rollback can itself be undone.
+func Rollback(configDir, currentVersion string) (*Backup, error) {
+	backup, err := LoadBackup(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Renaming works even for a running executable, on Windows too
+	swap := backup.Executable + ".rollback"
+	if err := os.Rename(backup.Executable, swap); err != nil {
+		return nil, fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(backup.Path, backup.Executable); err != nil {
+		os.Rename(swap, backup.Executable)
+		return nil, fmt.Errorf("failed to restore previous binary: %w", err)
+	}
+	if err := os.Rename(swap, backup.Path); err != nil {
+		return nil, fmt.Errorf("failed to keep replaced binary as backup: %w", err)
+	}
+
+	restored := *backup
+	next := &Backup{
+		Path:       backup.Path,
+		Version:    currentVersion,
+		Executable: backup.Executable,
+		CreatedAt:  time.Now(),
+	}
+	if err := SaveBackup(configDir, next); err != nil {
+		return nil, err
+	}
+
+	return &restored, nil
+}
@@ -0,0 +1,122 @@
+// internal/update/source.go
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Release sources understood by the version checker
+const (
+	SourceGitHub = "github"
+	SourceGitLab = "gitlab"
+	SourceGitea  = "gitea"
+)
+
+// DetectSource works out which release API a configured repository URL
+// belongs to and returns the URL of its latest-release endpoint. Both API
+// URLs and plain repository URLs (https://gitlab.example.com/group/project)
+// are accepted. Self-hosted instances whose host name does not mention
+// gitlab or gitea should be configured with their API URL.
+func DetectSource(repoURL string) (source, latestURL string) {
+	parsed, err := url.Parse(strings.TrimSuffix(repoURL, "/"))
+	if err != nil {
+		return SourceGitHub, repoURL
+	}
+
+	host := strings.ToLower(parsed.Host)
+	path := strings.TrimSuffix(strings.TrimPrefix(parsed.Path, "/"), ".git")
+
+	switch {
+	case strings.Contains(parsed.Path, "/api/v4/"):
+		return SourceGitLab, repoURL
+	case strings.Contains(parsed.Path, "/api/v1/"):
+		return SourceGitea, repoURL
+	case host == "api.github.com":
+		return SourceGitHub, repoURL
+	case host == "github.com":
+		return SourceGitHub, "https://api.github.com/repos/" + path + "/releases/latest"
+	case strings.Contains(host, "gitlab"):
+		return SourceGitLab, fmt.Sprintf("%s://%s/api/v4/projects/%s/releases/permalink/latest",
+			parsed.Scheme, parsed.Host, url.PathEscape(path))
+	case strings.Contains(host, "gitea") || host == "codeberg.org":
+		return SourceGitea, fmt.Sprintf("%s://%s/api/v1/repos/%s/releases/latest",
+			parsed.Scheme, parsed.Host, path)
+	default:
+		return SourceGitHub, repoURL
+	}
+}
+
+// parseRelease converts a latest-release response from the given source into UpdateInfo
+func parseRelease(source string, body []byte) (*UpdateInfo, error) {
+	info := &UpdateInfo{Assets: make(map[string]string)}
+
+	switch source {
+	case SourceGitLab:
+		var release struct {
+			TagName     string    `json:"tag_name"`
+			ReleasedAt  time.Time `json:"released_at"`
+			Description string    `json:"description"`
+			Links       struct {
+				Self string `json:"self"`
+			} `json:"_links"`
+			Assets struct {
+				Links []struct {
+					Name           string `json:"name"`
+					URL            string `json:"url"`
+					DirectAssetURL string `json:"direct_asset_url"`
+				} `json:"links"`
+			} `json:"assets"`
+		}
+		if err := json.Unmarshal(body, &release); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		info.LatestVersion = release.TagName
+		info.ReleaseDate = release.ReleasedAt
+		info.ReleaseNotes = release.Description
+		info.DownloadURL = release.Links.Self
+		for _, link := range release.Assets.Links {
+			if link.DirectAssetURL != "" {
+				info.Assets[link.Name] = link.DirectAssetURL
+			} else {
+				info.Assets[link.Name] = link.URL
+			}
+		}
+
+	default:
+		// Gitea mirrors the GitHub release format
+		var release struct {
+			TagName     string    `json:"tag_name"`
+			PublishedAt time.Time `json:"published_at"`
+			Body        string    `json:"body"`
+			HTMLURL     string    `json:"html_url"`
+			Assets      []struct {
+				Name               string `json:"name"`
+				BrowserDownloadURL string `json:"browser_download_url"`
+			} `json:"assets"`
+		}
+		if err := json.Unmarshal(body, &release); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		info.LatestVersion = release.TagName
+		info.ReleaseDate = release.PublishedAt
+		info.ReleaseNotes = release.Body
+		info.DownloadURL = release.HTMLURL
+		for _, asset := range release.Assets {
+			info.Assets[asset.Name] = asset.BrowserDownloadURL
+		}
+	}
+
+	if info.LatestVersion == "" {
+		return nil, fmt.Errorf("release response from %s has no tag name", source)
+	}
+
+	// Clean version string (remove 'v' prefix if present)
+	info.LatestVersion = strings.TrimPrefix(info.LatestVersion, "v")
+	return info, nil
+}
@@ -22,6 +22,13 @@ type UpdateInfo struct {
 	ReleaseNotes  string    `json:"release_notes"`
 	DownloadURL   string    `json:"download_url"`
 	CheckedAt     time.Time `json:"checked_at"`
+
+	// AttemptedAt is when a background check last started, successful or
+	// not, so a failing or slow release API is not queried on every command
+	AttemptedAt time.Time `json:"attempted_at,omitempty"`
+
+	// Assets maps release asset file names to their download URLs
+	Assets map[string]string `json:"assets,omitempty"`
 }
 
 // VersionChecker checks for new versions of the application
@@ -29,6 +36,7 @@ type VersionChecker struct {
 	currentVersion string
 	configDir      string
 	updateURL      string
+	source         string
 	cacheDuration  time.Duration
 }
 
@@ -46,10 +54,13 @@ func NewVersionChecker(currentVersion, configDir string) *VersionChecker {
 		}
 	}
 
+	source, latestURL := DetectSource(repoURL)
+
 	return &VersionChecker{
 		currentVersion: strings.TrimPrefix(currentVersion, "v"),
 		configDir:      configDir,
-		updateURL:      repoURL,
+		updateURL:      latestURL,
+		source:         source,
 		cacheDuration:  24 * time.Hour, // Check once per day
 	}
 }
@@ -92,29 +103,12 @@ func (vc *VersionChecker) CheckForUpdates(ctx context.Context) (*UpdateInfo, err
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse GitHub API response
-	var release struct {
-		TagName     string    `json:"tag_name"`
-		PublishedAt time.Time `json:"published_at"`
-		Body        string    `json:"body"`
-		HTMLURL     string    `json:"html_url"`
-	}
-
-	if err := json.Unmarshal(body, &release); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	// Clean version string (remove 'v' prefix if present)
-	latestVersion := strings.TrimPrefix(release.TagName, "v")
-
-	// Create update info
-	info := &UpdateInfo{
-		LatestVersion: latestVersion,
-		ReleaseDate:   release.PublishedAt,
-		ReleaseNotes:  release.Body,
-		DownloadURL:   release.HTMLURL,
-		CheckedAt:     time.Now(),
+	info, err := parseRelease(vc.source, body)
+	if err != nil {
+		return nil, err
 	}
+	info.CheckedAt = time.Now()
+	latestVersion := info.LatestVersion
 
 	// Cache the update info
 	vc.cacheUpdateInfo(info)
@@ -127,6 +121,42 @@ func (vc *VersionChecker) CheckForUpdates(ctx context.Context) (*UpdateInfo, err
 	return nil, nil // No update available
 }
 
+// Source returns the release API the checker talks to and its endpoint
+func (vc *VersionChecker) Source() (source, latestURL string) {
+	return vc.source, vc.updateURL
+}
+
+// CachedUpdate returns the update recorded by the last check without touching
+// the network. It reports whether that check is recent enough to trust; info is
+// nil when no newer version is known.
+func (vc *VersionChecker) CachedUpdate() (info *UpdateInfo, fresh bool) {
+	cachedInfo, err := vc.loadCachedInfo()
+	if err != nil {
+		return nil, false
+	}
+
+	last := cachedInfo.CheckedAt
+	if cachedInfo.AttemptedAt.After(last) {
+		last = cachedInfo.AttemptedAt
+	}
+	fresh = time.Since(last) < vc.cacheDuration
+	if vc.isNewerVersion(cachedInfo.LatestVersion) {
+		return cachedInfo, fresh
+	}
+	return nil, fresh
+}
+
+// MarkAttempted records that a check is starting, so CachedUpdate reports
+// the cache as fresh even if the check fails or never finishes
+func (vc *VersionChecker) MarkAttempted() error {
+	info, err := vc.loadCachedInfo()
+	if err != nil {
+		info = &UpdateInfo{}
+	}
+	info.AttemptedAt = time.Now()
+	return vc.cacheUpdateInfo(info)
+}
+
 // isNewerVersion checks if the latest version is newer than the current version
 func (vc *VersionChecker) isNewerVersion(latestVersion string) bool {
 	current, err := semver.NewVersion(vc.currentVersion)
@@ -180,6 +210,12 @@ func (vc *VersionChecker) loadCachedInfo() (*UpdateInfo, error) {
 	return &info, nil
 }
 
+// GetNotice returns a one-line notice about an available update
+func (vc *VersionChecker) GetNotice(info *UpdateInfo) string {
+	return fmt.Sprintf("Comma v%s is available (you have v%s), run 'comma update' to upgrade.",
+		info.LatestVersion, vc.currentVersion)
+}
+
 // GetUpdateMessage returns a formatted message about an available update
 func (vc *VersionChecker) GetUpdateMessage(info *UpdateInfo) string {
 	return fmt.Sprintf(`
@@ -0,0 +1,142 @@
+// internal/vault/agent.go
+package vault
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AgentSocketEnv overrides where the passphrase agent listens
+const AgentSocketEnv = "COMMA_AGENT_SOCK"
+
+// Requests understood by the passphrase agent, one per connection and line
+const (
+	agentGet    = "GET"    // Answered with "OK <passphrase>" or "NONE"
+	agentSet    = "SET"    // Followed by the passphrase, answered with "OK"
+	agentForget = "FORGET" // Answered with "OK"
+)
+
+// agentTimeout bounds every exchange with the agent, so a stuck agent
+// cannot hang a command
+const agentTimeout = 2 * time.Second
+
+// AgentSocket returns the path of the passphrase agent's socket
+func (cm *CredentialManager) AgentSocket() string {
+	if path := os.Getenv(AgentSocketEnv); path != "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(cm.fallback), "agent.sock")
+}
+
+// ServeAgent keeps the passphrase of the encrypted credentials file in
+// memory for commands that connect to the socket at path. It starts empty:
+// the first command that asks for the passphrase hands it over. It returns
+// when ctx is done or ttl has passed, taking the passphrase with it.
+func ServeAgent(ctx context.Context, path string, ttl time.Duration) error {
+	if _, err := agentRequest(path, agentGet); err == nil {
+		return fmt.Errorf("an agent is already listening on %s", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create agent directory: %w", err)
+	}
+	// A socket left behind by an agent that was killed
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	defer os.Remove(path)
+	defer listener.Close()
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict agent socket: %w", err)
+	}
+
+	if ttl > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ttl)
+		defer cancel()
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	var mu sync.Mutex
+	var passphrase string
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept agent connection: %w", err)
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(agentTimeout))
+
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				return
+			}
+			request, value, _ := strings.Cut(strings.TrimSuffix(line, "\n"), " ")
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch request {
+			case agentGet:
+				if passphrase == "" {
+					fmt.Fprintln(conn, "NONE")
+					return
+				}
+				fmt.Fprintln(conn, "OK "+passphrase)
+			case agentSet:
+				passphrase = value
+				fmt.Fprintln(conn, "OK")
+			case agentForget:
+				passphrase = ""
+				fmt.Fprintln(conn, "OK")
+			default:
+				fmt.Fprintln(conn, "ERR unknown request")
+			}
+		}(conn)
+	}
+}
+
+// agentRequest sends one request to the agent and returns the value in its
+// answer, if any
+func agentRequest(path, request string) (string, error) {
+	conn, err := net.DialTimeout("unix", path, agentTimeout)
+	if err != nil {
+		return "", fmt.Errorf("no agent is listening on %s: %w", path, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(agentTimeout))
+
+	if _, err := fmt.Fprintln(conn, request); err != nil {
+		return "", fmt.Errorf("failed to send agent request: %w", err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read agent answer: %w", err)
+	}
+
+	status, value, _ := strings.Cut(strings.TrimSuffix(line, "\n"), " ")
+	switch status {
+	case "OK":
+		return value, nil
+	case "NONE":
+		return "", nil
+	}
+	return "", fmt.Errorf("agent refused the request: %s", value)
+}
@@ -0,0 +1,141 @@
+// internal/vault/backend.go
+package vault
+
+import (
+	"fmt"
+	"os/exec"
+
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/zalando/go-keyring"
+)
+
+// Credential backend names accepted by security.credential_backend
+const (
+	BackendKeyring     = "keyring"
+	BackendPass        = "pass"
+	BackendOnePassword = "1password"
+	BackendHashiCorp   = "vault"
+)
+
+// Stores used by the keyring backend, set with security.keyring_backend
+const (
+	KeyringAuto   = "auto"    // The system keyring, or the encrypted file when it is unavailable
+	KeyringSystem = "keyring" // Only the system keyring
+	KeyringFile   = "file"    // Only the encrypted file, e.g. on headless servers
+	KeyringNone   = "none"    // Nothing is stored; keys come from the config or environment
+)
+
+// Backend stores and retrieves credentials in an external secret store
+type Backend interface {
+	Store(key, token string) error
+	Retrieve(key string) (string, error)
+	// Delete removes a credential, succeeding if it does not exist
+	Delete(key string) error
+	// Remote reports whether the backend needs network access
+	Remote() bool
+}
+
+// BackendOptions configures the external credential backends
+type BackendOptions struct {
+	OnePasswordVault string
+	VaultAddress     string
+	VaultMount       string
+}
+
+// UseBackend switches the credential manager to the named backend.
+// The keyring backend (with its encrypted file fallback) is the default.
+func (cm *CredentialManager) UseBackend(name string, opts BackendOptions) error {
+	switch name {
+	case "", BackendKeyring:
+		cm.backend = nil
+		return nil
+	case BackendPass:
+		if _, err := exec.LookPath("pass"); err != nil {
+			return fmt.Errorf("pass credential backend requires the pass CLI: %w", err)
+		}
+		cm.backend = &passBackend{prefix: "comma"}
+	case BackendOnePassword:
+		if _, err := exec.LookPath("op"); err != nil {
+			return fmt.Errorf("1password credential backend requires the op CLI: %w", err)
+		}
+		cm.backend = &onePasswordBackend{vault: opts.OnePasswordVault}
+	case BackendHashiCorp:
+		backend, err := newHashiCorpBackend(opts.VaultAddress, opts.VaultMount)
+		if err != nil {
+			return err
+		}
+		cm.backend = backend
+	default:
+		return fmt.Errorf("unknown credential backend: %s (supported: %s, %s, %s, %s)",
+			name, BackendKeyring, BackendPass, BackendOnePassword, BackendHashiCorp)
+	}
+
+	return nil
+}
+
+// SetKeyringMode chooses where the keyring backend keeps credentials. The
+// default, auto, falls back from the system keyring to the encrypted file.
+func (cm *CredentialManager) SetKeyringMode(mode string) error {
+	switch mode {
+	case "":
+		mode = KeyringAuto
+	case KeyringAuto, KeyringSystem, KeyringFile, KeyringNone:
+	default:
+		return fmt.Errorf("unknown keyring backend: %s (supported: %s, %s, %s, %s)",
+			mode, KeyringAuto, KeyringSystem, KeyringFile, KeyringNone)
+	}
+
+	cm.keyringMode = mode
+	return nil
+}
+
+// Location describes where a provider's key is kept, or would be stored
+func (cm *CredentialManager) Location(provider string) string {
+	switch cm.backend.(type) {
+	case *passBackend:
+		return "pass"
+	case *onePasswordBackend:
+		return "1Password"
+	case *hashiCorpBackend:
+		return "HashiCorp Vault"
+	}
+	switch cm.keyringMode {
+	case KeyringSystem:
+		return "system keyring"
+	case KeyringFile:
+		store, _ := cm.loadStore()
+		return fileLocation(store)
+	case KeyringNone:
+		return "disabled"
+	}
+	if _, err := keyring.Get(cm.service, provider); err != nil {
+		if store, err := cm.loadStore(); err == nil {
+			if _, ok := store.Credentials[provider]; ok {
+				return fileLocation(store)
+			}
+		}
+	}
+	return "system keyring"
+}
+
+// fileLocation describes the encrypted file, which may not exist yet
+func fileLocation(store *EncryptedStore) string {
+	if store != nil && store.Protected {
+		return "encrypted file, passphrase protected"
+	}
+	return "encrypted file"
+}
+
+// SetOffline makes credential lookups fail fast instead of contacting a
+// networked backend
+func (cm *CredentialManager) SetOffline(offline bool) {
+	cm.offline = offline
+}
+
+// checkOffline returns an error if the active backend needs the network in offline mode
+func (cm *CredentialManager) checkOffline() error {
+	if cm.offline && cm.backend != nil && cm.backend.Remote() {
+		return fmt.Errorf("cannot reach credential backend: %w", apperrors.ErrOffline)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// internal/vault/hashicorp.go
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// hashiCorpBackend stores credentials in a HashiCorp Vault KV v2 secrets engine
+type hashiCorpBackend struct {
+	address string
+	mount   string
+	token   string
+	client  *http.Client
+}
+
+// newHashiCorpBackend creates a Vault KV backend. The address falls back to
+// VAULT_ADDR and the token is read from VAULT_TOKEN or ~/.vault-token,
+// matching the vault CLI.
+func newHashiCorpBackend(address, mount string) (*hashiCorpBackend, error) {
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return nil, fmt.Errorf("vault credential backend requires security.vault_address or VAULT_ADDR")
+	}
+
+	if mount == "" {
+		mount = "secret"
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			if data, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+				token = strings.TrimSpace(string(data))
+			}
+		}
+	}
+	if token == "" {
+		return nil, fmt.Errorf("vault credential backend requires VAULT_TOKEN or a vault login")
+	}
+
+	return &hashiCorpBackend{
+		address: strings.TrimSuffix(address, "/"),
+		mount:   strings.Trim(mount, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Store writes a token to comma/<key> in the KV mount
+func (b *hashiCorpBackend) Store(key, token string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{"token": token},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault request: %w", err)
+	}
+
+	resp, err := b.do("POST", key, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("vault rejected credential write (status %d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Retrieve reads a token from comma/<key> in the KV mount
+func (b *hashiCorpBackend) Retrieve(key string) (string, error) {
+	resp, err := b.do("GET", key, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("no credentials found in vault for %s", key)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault rejected credential read (status %d)", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse vault response: %w", err)
+	}
+
+	token, ok := result.Data.Data["token"]
+	if !ok {
+		return "", fmt.Errorf("no credentials found in vault for %s", key)
+	}
+
+	return token, nil
+}
+
+// Delete deletes the latest version of comma/<key>. Vault keeps earlier
+// versions until they are destroyed there.
+func (b *hashiCorpBackend) Delete(key string) error {
+	resp, err := b.do("DELETE", key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("vault rejected credential delete (status %d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Remote reports that Vault is reached over the network
+func (b *hashiCorpBackend) Remote() bool {
+	return true
+}
+
+// do sends a request for a credential key to the KV v2 data endpoint
+func (b *hashiCorpBackend) do(method, key string, body io.Reader) (*http.Response, error) {
+	url := fmt.Sprintf("%s/v1/%s/data/comma/%s", b.address, b.mount, key)
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", b.token)
+	req.Header.Set("Content-Type", "application/json")
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach vault: %w", err)
+	}
+
+	return resp, nil
+}
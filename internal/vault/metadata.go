@@ -0,0 +1,157 @@
+// internal/vault/metadata.go
+package vault
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CredentialInfo records when a credential was stored. Only a fingerprint of the
+// token is kept, so the metadata file never contains secrets.
+type CredentialInfo struct {
+	CreatedAt   time.Time `json:"created_at"`
+	Fingerprint string    `json:"fingerprint"`
+}
+
+// Age returns how long ago the credential was created
+func (ci CredentialInfo) Age() time.Duration {
+	return time.Since(ci.CreatedAt)
+}
+
+// Info returns the stored metadata for a credential
+func (cm *CredentialManager) Info(provider string) (*CredentialInfo, error) {
+	meta, err := cm.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := meta[provider]
+	if !ok {
+		return nil, fmt.Errorf("no credential metadata for provider: %s", provider)
+	}
+
+	return &info, nil
+}
+
+// RecordFirstSeen returns the metadata for a credential, first recording it
+// as created now if the credential was stored before metadata was kept, so
+// its age is measured from the first time it was seen
+func (cm *CredentialManager) RecordFirstSeen(provider, token string) (*CredentialInfo, error) {
+	if info, err := cm.Info(provider); err == nil {
+		return info, nil
+	}
+
+	if err := cm.recordCreated(provider, token); err != nil {
+		return nil, err
+	}
+	return cm.Info(provider)
+}
+
+// recordCreated updates the created-at timestamp for a credential. Storing the
+// same token again keeps the original timestamp, so re-saving an unchanged key
+// does not reset its age.
+func (cm *CredentialManager) recordCreated(provider, token string) error {
+	meta, err := cm.loadMetadata()
+	if err != nil {
+		meta = make(map[string]CredentialInfo)
+	}
+
+	fingerprint := tokenFingerprint(token)
+	if existing, ok := meta[provider]; ok && existing.Fingerprint == fingerprint {
+		return nil
+	}
+
+	meta[provider] = CredentialInfo{
+		CreatedAt:   time.Now(),
+		Fingerprint: fingerprint,
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential metadata: %w", err)
+	}
+
+	return writeFileAtomic(cm.metadata, data, 0600)
+}
+
+// forget removes the metadata for a credential
+func (cm *CredentialManager) forget(provider string) error {
+	meta, err := cm.loadMetadata()
+	if err != nil {
+		return err
+	}
+	if _, ok := meta[provider]; !ok {
+		return nil
+	}
+	delete(meta, provider)
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential metadata: %w", err)
+	}
+
+	return writeFileAtomic(cm.metadata, data, 0600)
+}
+
+// loadMetadata reads the credential metadata file
+func (cm *CredentialManager) loadMetadata() (map[string]CredentialInfo, error) {
+	meta := make(map[string]CredentialInfo)
+
+	data, err := os.ReadFile(cm.metadata)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return meta, nil
+		}
+		return nil, fmt.Errorf("failed to read credential metadata: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse credential metadata: %w", err)
+	}
+
+	return meta, nil
+}
+
+// tokenFingerprint returns a short hash identifying a token
+func tokenFingerprint(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so readers never observe a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
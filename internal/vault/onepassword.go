@@ -0,0 +1,121 @@
+// internal/vault/onepassword.go
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// onePasswordBackend stores credentials as API Credential items via the 1Password CLI
+type onePasswordBackend struct {
+	vault string
+}
+
+// onePasswordItem is the item template passed to the op CLI
+type onePasswordItem struct {
+	Title    string             `json:"title"`
+	Category string             `json:"category"`
+	Fields   []onePasswordField `json:"fields"`
+}
+
+// onePasswordField is a single field in an item template
+type onePasswordField struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Store creates or updates the item for a credential. The token is passed through
+// a private template file rather than the command line, where other users could see it.
+func (b *onePasswordBackend) Store(key, token string) error {
+	item := onePasswordItem{
+		Title:    b.title(key),
+		Category: "API_CREDENTIAL",
+		Fields: []onePasswordField{
+			{ID: "credential", Type: "CONCEALED", Label: "credential", Value: token},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal 1Password item: %w", err)
+	}
+
+	template, err := os.CreateTemp("", "comma-op-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create 1Password template: %w", err)
+	}
+	defer os.Remove(template.Name())
+
+	if _, err := template.Write(data); err != nil {
+		template.Close()
+		return fmt.Errorf("failed to write 1Password template: %w", err)
+	}
+	template.Close()
+
+	args := []string{"item", "create", "--template", template.Name()}
+	if _, err := b.run("item", "get", b.title(key)); err == nil {
+		args = []string{"item", "edit", b.title(key), "--template", template.Name()}
+	}
+
+	if _, err := b.run(args...); err != nil {
+		return fmt.Errorf("failed to store credential in 1Password: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve reads the credential field of the item for a key
+func (b *onePasswordBackend) Retrieve(key string) (string, error) {
+	out, err := b.run("item", "get", b.title(key), "--fields", "credential", "--reveal")
+	if err != nil {
+		return "", fmt.Errorf("no credentials found in 1Password for %s: %w", key, err)
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// Delete removes the item for a key
+func (b *onePasswordBackend) Delete(key string) error {
+	if _, err := b.run("item", "get", b.title(key)); err != nil {
+		return nil
+	}
+
+	if _, err := b.run("item", "delete", b.title(key)); err != nil {
+		return fmt.Errorf("failed to remove credential from 1Password: %w", err)
+	}
+
+	return nil
+}
+
+// Remote reports that the op CLI talks to the 1Password service
+func (b *onePasswordBackend) Remote() bool {
+	return true
+}
+
+// run executes an op command scoped to the configured vault
+func (b *onePasswordBackend) run(args ...string) (string, error) {
+	if b.vault != "" {
+		args = append(args, "--vault", b.vault)
+	}
+
+	cmd := exec.Command("op", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// title returns the 1Password item title for a credential key
+func (b *onePasswordBackend) title(key string) string {
+	return "comma-" + key
+}
@@ -0,0 +1,69 @@
+// internal/vault/pass.go
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// passBackend stores credentials with the standard unix password manager
+type passBackend struct {
+	prefix string
+}
+
+// Store writes a token to pass, replacing any existing entry
+func (b *passBackend) Store(key, token string) error {
+	cmd := exec.Command("pass", "insert", "--multiline", "--force", b.entry(key))
+	cmd.Stdin = strings.NewReader(token + "\n")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to store credential in pass: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// Retrieve reads a token from pass. Only the first line of the entry is used,
+// following the pass convention of keeping the secret on the first line.
+func (b *passBackend) Retrieve(key string) (string, error) {
+	cmd := exec.Command("pass", "show", b.entry(key))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("no credentials found in pass for %s: %s", key, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(strings.SplitN(stdout.String(), "\n", 2)[0]), nil
+}
+
+// Delete removes the pass entry for a key
+func (b *passBackend) Delete(key string) error {
+	if _, err := b.Retrieve(key); err != nil {
+		return nil
+	}
+
+	cmd := exec.Command("pass", "rm", "--force", b.entry(key))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to remove credential from pass: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// Remote reports that pass is a local store
+func (b *passBackend) Remote() bool {
+	return false
+}
+
+// entry returns the pass entry name for a credential key
+func (b *passBackend) entry(key string) string {
+	return b.prefix + "/" + key
+}
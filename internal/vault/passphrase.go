@@ -0,0 +1,195 @@
+// internal/vault/passphrase.go
+package vault
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/term"
+)
+
+// PassphraseEnv supplies the encrypted file's passphrase without a prompt,
+// e.g. in CI
+const PassphraseEnv = "COMMA_CREDENTIAL_PASSPHRASE"
+
+// ErrLocked is returned when the encrypted file's passphrase is unavailable
+// or wrong
+var ErrLocked = errors.New("credentials file is locked")
+
+// Places the passphrase is remembered between runs, set with
+// security.passphrase_cache
+const (
+	PassphraseCacheAgent   = "agent"   // A running 'comma auth agent'
+	PassphraseCacheKeyring = "keyring" // The system keyring
+	PassphraseCacheNone    = "none"    // Asked for on every run
+)
+
+const (
+	// passphraseIterations slows down guessing the passphrase
+	passphraseIterations = 600000
+
+	// passphraseKeyringKey is the keyring entry the passphrase is cached in
+	passphraseKeyringKey = "credentials-passphrase"
+)
+
+// SetPassphraseCache chooses where the passphrase is remembered once it has
+// been typed
+func (cm *CredentialManager) SetPassphraseCache(cache string) error {
+	switch cache {
+	case "":
+		cache = PassphraseCacheAgent
+	case PassphraseCacheAgent, PassphraseCacheKeyring, PassphraseCacheNone:
+	default:
+		return fmt.Errorf("unknown passphrase cache: %s (supported: %s, %s, %s)",
+			cache, PassphraseCacheAgent, PassphraseCacheKeyring, PassphraseCacheNone)
+	}
+
+	cm.passphraseCache = cache
+	return nil
+}
+
+// FileProtected reports whether the encrypted credentials file is protected
+// by a passphrase
+func (cm *CredentialManager) FileProtected() bool {
+	store, err := cm.loadStore()
+	return err == nil && store.Protected
+}
+
+// Unlock asks for the passphrase of a protected file now rather than when a
+// key is first needed, checking it against the stored keys
+func (cm *CredentialManager) Unlock() error {
+	store, err := cm.loadStore()
+	if err != nil || !store.Protected {
+		return nil
+	}
+	for _, cred := range store.Credentials {
+		_, err := cm.open(cred, true)
+		return err
+	}
+	_, err = cm.filePassphrase()
+	return err
+}
+
+// SetFilePassphrase re-encrypts the encrypted credentials file with a new
+// passphrase, or with the machine key again when passphrase is empty. The
+// current passphrase is asked for if the file needs one.
+func (cm *CredentialManager) SetFilePassphrase(passphrase string) error {
+	store, err := cm.loadStore()
+	if err != nil {
+		store = &EncryptedStore{Credentials: make(map[string]EncryptedCredential)}
+	}
+
+	tokens := make(map[string]string, len(store.Credentials))
+	for provider, cred := range store.Credentials {
+		if tokens[provider], err = cm.open(cred, store.Protected); err != nil {
+			return err
+		}
+	}
+
+	cm.ForgetPassphrase()
+	cm.passphrase = passphrase
+	store.Protected = passphrase != ""
+	for provider, token := range tokens {
+		if store.Credentials[provider], err = cm.seal(token, store.Protected); err != nil {
+			return err
+		}
+	}
+	if err := cm.saveStore(store); err != nil {
+		return err
+	}
+
+	if store.Protected {
+		cm.cachePassphrase(passphrase)
+	}
+	return nil
+}
+
+// ForgetPassphrase drops the passphrase from memory and from its cache, so
+// it is asked for again
+func (cm *CredentialManager) ForgetPassphrase() {
+	cm.passphrase = ""
+	switch cm.passphraseCache {
+	case PassphraseCacheKeyring:
+		keyring.Delete(cm.service, passphraseKeyringKey)
+	case PassphraseCacheAgent:
+		agentRequest(cm.AgentSocket(), agentForget)
+	}
+}
+
+// passphraseKey derives a file key from the user's passphrase
+func (cm *CredentialManager) passphraseKey(salt []byte) ([]byte, error) {
+	passphrase, err := cm.filePassphrase()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLocked, err)
+	}
+	return pbkdf2.Key([]byte(passphrase), salt, passphraseIterations, 32, sha256.New), nil
+}
+
+// filePassphrase returns the passphrase from memory, the environment or the
+// cache, asking for it only when none has it
+func (cm *CredentialManager) filePassphrase() (string, error) {
+	if cm.passphrase != "" {
+		return cm.passphrase, nil
+	}
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		cm.passphrase = passphrase
+		return passphrase, nil
+	}
+
+	switch cm.passphraseCache {
+	case PassphraseCacheKeyring:
+		cm.passphrase, _ = keyring.Get(cm.service, passphraseKeyringKey)
+	case PassphraseCacheAgent:
+		cm.passphrase, _ = agentRequest(cm.AgentSocket(), agentGet)
+	}
+	if cm.passphrase != "" {
+		return cm.passphrase, nil
+	}
+
+	passphrase, err := cm.askPassphrase()
+	if err != nil {
+		return "", err
+	}
+	cm.passphrase = passphrase
+	cm.cachePassphrase(passphrase)
+	return passphrase, nil
+}
+
+// cachePassphrase remembers the passphrase for later runs. Failing to is not
+// an error: it is asked for again next time.
+func (cm *CredentialManager) cachePassphrase(passphrase string) {
+	switch cm.passphraseCache {
+	case PassphraseCacheKeyring:
+		keyring.Set(cm.service, passphraseKeyringKey, passphrase)
+	case PassphraseCacheAgent:
+		agentRequest(cm.AgentSocket(), agentSet+" "+passphrase)
+	}
+}
+
+// promptPassphrase returns a function that asks for the passphrase of the
+// file at path on the terminal
+func promptPassphrase(path string) func() (string, error) {
+	return func() (string, error) {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return "", fmt.Errorf("no terminal to ask for the passphrase of %s (set %s, or type it once with 'comma auth agent' running)",
+				filepath.Base(path), PassphraseEnv)
+		}
+
+		fmt.Fprintf(os.Stderr, "Passphrase for %s: ", path)
+		passphrase, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if len(passphrase) == 0 {
+			return "", fmt.Errorf("no passphrase given")
+		}
+		return string(passphrase), nil
+	}
+}
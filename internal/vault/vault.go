@@ -7,19 +7,29 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/jasonKoogler/comma/internal/ui"
 	"github.com/zalando/go-keyring"
 	"golang.org/x/crypto/pbkdf2"
 )
 
 // CredentialManager handles secure storage of API keys
 type CredentialManager struct {
-	service  string
-	fallback string // Fallback encrypted file path
+	service     string
+	fallback    string  // Fallback encrypted file path
+	metadata    string  // Credential metadata file path
+	backend     Backend // External secret store, nil for the system keyring
+	keyringMode string  // Where the keyring backend stores, one of the Keyring modes
+	offline     bool
+
+	passphrase      string                 // Passphrase for the encrypted file, once known
+	passphraseCache string                 // Where the passphrase is remembered between runs
+	askPassphrase   func() (string, error) // Prompts for the passphrase
 }
 
 // EncryptedCredential represents an encrypted credential
@@ -32,73 +42,179 @@ type EncryptedCredential struct {
 // EncryptedStore represents the structure of the encrypted credentials file
 type EncryptedStore struct {
 	Credentials map[string]EncryptedCredential `json:"credentials"`
+	Protected   bool                           `json:"passphrase_protected,omitempty"` // Keys come from the user's passphrase
 }
 
 // NewCredentialManager creates a new credential manager
 func NewCredentialManager(configDir string) (*CredentialManager, error) {
 	fallbackPath := filepath.Join(configDir, "credentials.enc")
 	return &CredentialManager{
-		service:  "comma-git",
-		fallback: fallbackPath,
+		service:     "comma-git",
+		fallback:    fallbackPath,
+		metadata:    filepath.Join(configDir, "credentials.meta.json"),
+		keyringMode: KeyringAuto,
+
+		passphraseCache: PassphraseCacheAgent,
+		askPassphrase:   promptPassphrase(fallbackPath),
 	}, nil
 }
 
-// Store securely stores an API token
+// errStorageDisabled is returned when security.keyring_backend is none
+var errStorageDisabled = fmt.Errorf("credential storage is disabled (security.keyring_backend is %s)", KeyringNone)
+
+// Store securely stores an API token and records when it was created
 func (cm *CredentialManager) Store(provider, token string) error {
+	if err := cm.store(provider, token); err != nil {
+		return err
+	}
+
+	return cm.recordCreated(provider, token)
+}
+
+// Rotate replaces a stored token with a new one. The new token is read back
+// to verify it was stored, and the previous token is restored if anything fails,
+// so the provider is never left without a working key.
+func (cm *CredentialManager) Rotate(provider, token string) error {
+	previous, err := cm.Retrieve(provider)
+	if err != nil {
+		previous = ""
+	}
+
+	if err := cm.store(provider, token); err != nil {
+		return fmt.Errorf("failed to store new key: %w", err)
+	}
+
+	if stored, err := cm.Retrieve(provider); err != nil || stored != token {
+		if previous != "" {
+			if restoreErr := cm.store(provider, previous); restoreErr != nil {
+				return fmt.Errorf("failed to verify new key and could not restore the previous key: %w", restoreErr)
+			}
+		}
+		return fmt.Errorf("failed to verify new key, previous key kept")
+	}
+
+	return cm.recordCreated(provider, token)
+}
+
+// store writes a token to the active backend
+func (cm *CredentialManager) store(provider, token string) error {
+	if err := cm.checkOffline(); err != nil {
+		return err
+	}
+
+	if cm.backend != nil {
+		return cm.backend.Store(provider, token)
+	}
+
+	switch cm.keyringMode {
+	case KeyringNone:
+		return errStorageDisabled
+	case KeyringFile:
+		return cm.storeFallback(provider, token)
+	}
+
 	// Try system keychain first
 	err := keyring.Set(cm.service, provider, token)
 	if err == nil {
 		return nil
 	}
+	if cm.keyringMode == KeyringSystem {
+		return fmt.Errorf("system keyring unavailable (set security.keyring_backend to file to use the encrypted file): %w", err)
+	}
 
 	// Fall back to encrypted file storage with warning
-	fmt.Println("Warning: Cannot use system keyring, falling back to encrypted file")
+	ui.Notify(ui.NoticeWarning, "System keyring unavailable, storing the %s key in the encrypted file (set security.keyring_backend to file to skip the keyring)", provider)
 	return cm.storeFallback(provider, token)
 }
 
 // Retrieve securely retrieves an API token
 func (cm *CredentialManager) Retrieve(provider string) (string, error) {
+	if err := cm.checkOffline(); err != nil {
+		return "", err
+	}
+
+	if cm.backend != nil {
+		return cm.backend.Retrieve(provider)
+	}
+
+	switch cm.keyringMode {
+	case KeyringNone:
+		return "", errStorageDisabled
+	case KeyringFile:
+		return cm.retrieveFallback(provider)
+	}
+
 	// Try system keychain first
 	token, err := keyring.Get(cm.service, provider)
 	if err == nil {
 		return token, nil
 	}
+	if cm.keyringMode == KeyringSystem {
+		return "", fmt.Errorf("no credentials found in the system keyring for %s: %w", provider, err)
+	}
 
 	// Fall back to encrypted file
 	return cm.retrieveFallback(provider)
 }
 
-// storeFallback stores tokens in encrypted file
-func (cm *CredentialManager) storeFallback(provider, token string) error {
-	// Create a better derived key using PBKDF2
-	salt := make([]byte, 16)
-	if _, err := rand.Read(salt); err != nil {
-		return fmt.Errorf("failed to generate salt: %w", err)
+// Remove deletes a stored API token and its metadata. It reports whether a
+// token was stored.
+func (cm *CredentialManager) Remove(provider string) (bool, error) {
+	if err := cm.checkOffline(); err != nil {
+		return false, err
 	}
 
-	key, err := deriveKey(salt)
-	if err != nil {
-		return fmt.Errorf("failed to derive key: %w", err)
+	if cm.backend != nil {
+		_, err := cm.backend.Retrieve(provider)
+		stored := err == nil
+		if err := cm.backend.Delete(provider); err != nil {
+			return false, err
+		}
+		return stored, cm.forget(provider)
+	}
+	if cm.keyringMode == KeyringNone {
+		return false, nil
 	}
 
-	// Encrypt the token
-	block, err := aes.NewCipher(key)
+	// The token may be in the keyring, the encrypted file or both. Neither
+	// needs decrypting, so no passphrase is asked for.
+	stored := false
+	if _, err := keyring.Get(cm.service, provider); err == nil {
+		if err := keyring.Delete(cm.service, provider); err != nil {
+			return false, fmt.Errorf("failed to remove credential from keyring: %w", err)
+		}
+		stored = true
+	}
+	removed, err := cm.removeFallback(provider)
 	if err != nil {
-		return fmt.Errorf("failed to create cipher: %w", err)
+		return false, err
 	}
+	stored = stored || removed
 
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return fmt.Errorf("failed to create GCM: %w", err)
+	return stored, cm.forget(provider)
+}
+
+// removeFallback deletes a token from the encrypted file, reporting
+// whether it was there
+func (cm *CredentialManager) removeFallback(provider string) (bool, error) {
+	if _, err := os.Stat(cm.fallback); os.IsNotExist(err) {
+		return false, nil
 	}
 
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := rand.Read(nonce); err != nil {
-		return fmt.Errorf("failed to generate nonce: %w", err)
+	store, err := cm.loadStore()
+	if err != nil {
+		return false, err
 	}
+	if _, ok := store.Credentials[provider]; !ok {
+		return false, nil
+	}
+	delete(store.Credentials, provider)
 
-	ciphertext := gcm.Seal(nil, nonce, []byte(token), nil)
+	return true, cm.saveStore(store)
+}
 
+// storeFallback stores tokens in encrypted file
+func (cm *CredentialManager) storeFallback(provider, token string) error {
 	// Create or load the store
 	store := EncryptedStore{
 		Credentials: make(map[string]EncryptedCredential),
@@ -108,90 +224,153 @@ func (cm *CredentialManager) storeFallback(provider, token string) error {
 	if _, err := os.Stat(cm.fallback); err == nil {
 		data, err := ioutil.ReadFile(cm.fallback)
 		if err == nil {
-			if err := json.Unmarshal(data, &store); err != nil {
+			if err := json.Unmarshal(data, &store); err != nil || store.Credentials == nil {
 				// Start fresh if file is corrupted
-				store.Credentials = make(map[string]EncryptedCredential)
+				store = EncryptedStore{Credentials: make(map[string]EncryptedCredential)}
 			}
 		}
 	}
 
 	// Store the new credential
-	store.Credentials[provider] = EncryptedCredential{
-		Ciphertext: ciphertext,
-		Nonce:      nonce,
-		Salt:       salt,
-	}
-
-	// Save the store
-	data, err := json.Marshal(store)
+	cred, err := cm.seal(token, store.Protected)
 	if err != nil {
-		return fmt.Errorf("failed to marshal credentials: %w", err)
+		return err
 	}
+	store.Credentials[provider] = cred
 
-	// Ensure directory exists
-	dir := filepath.Dir(cm.fallback)
-	if err := os.MkdirAll(dir, 0700); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+	return cm.saveStore(&store)
+}
+
+// retrieveFallback retrieves tokens from encrypted file
+func (cm *CredentialManager) retrieveFallback(provider string) (string, error) {
+	store, err := cm.loadStore()
+	if err != nil {
+		return "", err
 	}
 
-	// Write to file with restricted permissions
-	if err := ioutil.WriteFile(cm.fallback, data, 0600); err != nil {
-		return fmt.Errorf("failed to write credentials file: %w", err)
+	// Get the encrypted credential
+	cred, ok := store.Credentials[provider]
+	if !ok {
+		return "", fmt.Errorf("no credentials found for provider: %s", provider)
 	}
 
-	return nil
+	return cm.open(cred, store.Protected)
 }
 
-// retrieveFallback retrieves tokens from encrypted file
-func (cm *CredentialManager) retrieveFallback(provider string) (string, error) {
+// loadStore reads the encrypted credentials file
+func (cm *CredentialManager) loadStore() (*EncryptedStore, error) {
 	// Check if file exists
 	if _, err := os.Stat(cm.fallback); os.IsNotExist(err) {
-		return "", fmt.Errorf("credentials file not found")
+		return nil, fmt.Errorf("credentials file not found")
 	}
 
 	// Read encrypted credentials
 	data, err := ioutil.ReadFile(cm.fallback)
 	if err != nil {
-		return "", fmt.Errorf("failed to read credentials file: %w", err)
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
 	}
 
 	// Unmarshal the store
 	var store EncryptedStore
 	if err := json.Unmarshal(data, &store); err != nil {
-		return "", fmt.Errorf("failed to parse credentials file: %w", err)
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
 	}
-
-	// Get the encrypted credential
-	cred, ok := store.Credentials[provider]
-	if !ok {
-		return "", fmt.Errorf("no credentials found for provider: %s", provider)
+	if store.Credentials == nil {
+		store.Credentials = make(map[string]EncryptedCredential)
 	}
 
-	// Derive the key using the stored salt
-	key, err := deriveKey(cred.Salt)
+	return &store, nil
+}
+
+// saveStore writes the encrypted credentials file
+func (cm *CredentialManager) saveStore(store *EncryptedStore) error {
+	data, err := json.Marshal(store)
 	if err != nil {
-		return "", fmt.Errorf("failed to derive key: %w", err)
+		return fmt.Errorf("failed to marshal credentials: %w", err)
 	}
 
-	// Decrypt the token
-	block, err := aes.NewCipher(key)
+	// Replace the file atomically with restricted permissions
+	if err := writeFileAtomic(cm.fallback, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+
+	return nil
+}
+
+// seal encrypts a token with a key derived from a fresh salt and either the
+// passphrase or the machine
+func (cm *CredentialManager) seal(token string, protected bool) (EncryptedCredential, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return EncryptedCredential{}, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := cm.cipher(salt, protected)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
+		return EncryptedCredential{}, err
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return EncryptedCredential{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return EncryptedCredential{
+		Ciphertext: gcm.Seal(nil, nonce, []byte(token), nil),
+		Nonce:      nonce,
+		Salt:       salt,
+	}, nil
+}
+
+// open decrypts a token sealed by seal
+func (cm *CredentialManager) open(cred EncryptedCredential, protected bool) (string, error) {
+	gcm, err := cm.cipher(cred.Salt, protected)
 	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
+		return "", err
 	}
 
 	plaintext, err := gcm.Open(nil, cred.Nonce, cred.Ciphertext, nil)
 	if err != nil {
+		if protected {
+			// Don't keep offering a wrong passphrase
+			cm.ForgetPassphrase()
+			return "", fmt.Errorf("%w: wrong passphrase for %s", ErrLocked, cm.fallback)
+		}
 		return "", fmt.Errorf("failed to decrypt value: %w", err)
 	}
 
 	return string(plaintext), nil
 }
 
+// cipher returns the AES-GCM cipher for a credential's salt
+func (cm *CredentialManager) cipher(salt []byte, protected bool) (cipher.AEAD, error) {
+	var key []byte
+	var err error
+	if protected {
+		key, err = cm.passphraseKey(salt)
+	} else {
+		key, err = deriveKey(salt)
+	}
+	if errors.Is(err, ErrLocked) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return gcm, nil
+}
+
 // deriveKey derives a key from machine-specific data using PBKDF2
 func deriveKey(salt []byte) ([]byte, error) {
 	// Get machine-specific information
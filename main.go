@@ -8,6 +8,10 @@ import (
 	"github.com/jasonKoogler/comma/cmd"
 	"github.com/jasonKoogler/comma/internal/commit"
 	"github.com/jasonKoogler/comma/internal/config"
+	apperrors "github.com/jasonKoogler/comma/internal/errors"
+	"github.com/jasonKoogler/comma/internal/llm"
+	"github.com/jasonKoogler/comma/internal/logging"
+	"github.com/jasonKoogler/comma/internal/plugin"
 	"github.com/mitchellh/go-homedir"
 )
 
@@ -36,15 +40,50 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize the commit service
-	appCtx.CommitService = commit.NewService(appCtx.CredentialMgr, appCtx)
+	// Hook scripts and plugins run around generation and commit
+	plugins := plugin.NewManager(appCtx)
+	hooks := plugin.NewHooks(appCtx.ConfigDir)
+	hooks.SetManager(plugins)
+
+	// Plugins that add LLM providers are loaded when a provider is looked up
+	llm.SetProviderLoader(func() {
+		if err := plugins.Initialize(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	})
+
+	// The commit service is built when a command first needs it, after the
+	// command has configured logging
+	appCtx.SetCommitServiceFactory(func() (interface{}, error) {
+		credMgr, err := appCtx.CredentialMgr()
+		if err != nil {
+			return nil, err
+		}
+		teamMgr, err := appCtx.TeamManager()
+		if err != nil {
+			return nil, err
+		}
+		commitCache, err := appCtx.Cache()
+		if err != nil {
+			return nil, err
+		}
+
+		commitService := commit.NewService(credMgr, appCtx)
+		commitService.SetProviderPolicy(teamMgr)
+		commitService.SetCache(commitCache)
+		commitService.SetHooks(hooks)
+		commitService.SetLogger(logging.WithComponent(appCtx.Logger, "commit"))
+		return commitService, nil
+	})
 
 	// Pass version to command executor
 	cmd.SetVersion(version)
 
 	// Execute the root command with the app context
-	if err := cmd.Execute(appCtx); err != nil {
+	err = cmd.Execute(appCtx)
+	plugins.Shutdown()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(apperrors.ExitCode(err))
 	}
 }